@@ -0,0 +1,4 @@
+// Package subscriptionv1 contains the gRPC API definition and the code generated from it.
+package subscriptionv1
+
+//go:generate protoc -I ../../.. --go_out=../../.. --go_opt=paths=source_relative --go-grpc_out=../../.. --go-grpc_opt=paths=source_relative api/subscription/v1/subscription.proto
@@ -1,34 +1,335 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/Ostmind/subscriptionservice/internal/storage/sqlite"
 	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/migration"
+	"io"
 	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
 )
 
+// Exit codes of the migrator, for the deployment scripts.
+const (
+	exitOK       = 0
+	exitFailed   = 1 // a migration or a database query failed
+	exitUsage    = 2 // unknown command or invalid arguments
+	exitConnect  = 3 // the database can't be opened
+	exitLocked   = 4 // another migrator held the lock for the whole -lock-timeout
+	exitInvalid  = 5 // validate found a problem
+	exitNoChange = 6 // down or redo with no migration applied
+)
+
+const usage = `usage: migrator [config flags] <command> [-dry-run] [-lock-timeout duration] [arguments]
+
+commands:
+  up                 apply every pending migration
+  up-to VERSION      apply the pending migrations up to VERSION
+  down               roll back the latest migration
+  down-to VERSION    roll back the migrations newer than VERSION, 0 rolls back all of them
+  redo               roll back the latest migration and apply it again
+  status             print the state of every migration
+  version            print the version of the database
+  create NAME        create an empty migration file
+  validate           check the migration files and the version of the database
+
+exit codes: 0 ok, 1 failed, 2 usage, 3 database unavailable, 4 locked, 5 invalid, 6 nothing to roll back
+`
+
 func main() {
-	cfg := config.MustNew()
+	cfg, args := config.MustNewWithArgs()
 
-	connStr := config.GetConnStr(cfg.DB.Host, cfg.DB.Port,
-		cfg.DB.DBUser, cfg.DB.DBPassword,
-		cfg.DB.DBName, cfg.DB.DBSSLMode)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, cfg, args, os.Stdout)
 
-	connConfig, err := pgx.ParseConfig(connStr)
-	if err != nil {
-		log.Fatalf("migrator: failed to parse conn config: %s", err)
+	stop()
+	os.Exit(code)
+}
+
+// run runs the command of args and returns the exit code.
+func run(ctx context.Context, cfg *config.AppConfig, args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+
+		return exitUsage
+	}
+
+	command := args[0]
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print the SQL that would be run instead of running it")
+	lockTimeout := flags.Duration("lock-timeout", cfg.DB.MigrationLockTimeout, "how long to wait for a running migrator")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	dialect := dialectOf(cfg.DB.Driver)
+	if dialect == "" {
+		log.Printf("migrator: nothing to migrate for the %s driver", cfg.DB.Driver)
+
+		return exitOK
+	}
+
+	if command == "create" {
+		if flags.NArg() != 1 {
+			return usageError("create takes the migration name")
+		}
+
+		if cfg.Srv.MigrationPath == "" {
+			return usageError("create writes to the server.migration directory, set it")
+		}
+
+		dir := cfg.Srv.MigrationPath
+		if dialect == goose.DialectSQLite3 {
+			dir = filepath.Join(dir, migration.SQLiteDir)
+		}
+
+		path, err := migration.Create(dir, flags.Arg(0))
+		if err != nil {
+			log.Printf("migrator: %s", err)
+
+			return exitFailed
+		}
+
+		fmt.Fprintf(out, "created %s, bump migration.Version to match it\n", path)
+
+		return exitOK
 	}
 
-	db := stdlib.OpenDB(*connConfig)
+	var version int64
+
+	switch command {
+	case "up-to", "down-to":
+		if flags.NArg() != 1 {
+			return usageError(command + " takes the target version")
+		}
+
+		var err error
+		if version, err = strconv.ParseInt(flags.Arg(0), 10, 64); err != nil || version < 0 {
+			return usageError("invalid version " + flags.Arg(0))
+		}
+	case "up", "down", "redo", "status", "version", "validate":
+		if flags.NArg() != 0 {
+			return usageError(command + " takes no arguments")
+		}
+	default:
+		return usageError("unknown command " + command)
+	}
+
+	db, err := open(cfg)
+	if err != nil {
+		log.Printf("migrator: %s", err)
+
+		return exitConnect
+	}
 	defer db.Close()
 
-	if err = goose.SetDialect("postgres"); err != nil {
-		log.Fatalf("migrator: goose error: %s", err)
+	if err := db.PingContext(ctx); err != nil {
+		log.Printf("migrator: failed to connect to DB: %s", err)
+
+		return exitConnect
+	}
+
+	files, err := migration.Files(dialect, cfg.Srv.MigrationPath)
+	if err != nil {
+		log.Printf("migrator: %s", err)
+
+		return exitFailed
 	}
 
-	if err = goose.Up(db, cfg.Srv.MigrationPath); err != nil {
-		log.Fatalf("migrator: goose error: %s", err)
+	migrator, err := migration.NewMigrator(db, dialect, files, *lockTimeout)
+	if err != nil {
+		log.Printf("migrator: %s", err)
+
+		return exitFailed
+	}
+
+	if *dryRun {
+		return exitCode(plan(ctx, migrator, command, version, out))
+	}
+
+	return exitCode(execute(ctx, migrator, command, version, out))
+}
+
+func execute(ctx context.Context, migrator *migration.Migrator, command string, version int64, out io.Writer) error {
+	var (
+		results []*goose.MigrationResult
+		err     error
+	)
+
+	switch command {
+	case "up":
+		results, err = migrator.Up(ctx)
+	case "up-to":
+		results, err = migrator.UpTo(ctx, version)
+	case "down":
+		results, err = migrator.Down(ctx)
+	case "down-to":
+		results, err = migrator.DownTo(ctx, version)
+	case "redo":
+		results, err = migrator.Redo(ctx)
+	case "status":
+		return printStatus(ctx, migrator, out)
+	case "version":
+		if version, err = migrator.Version(ctx); err == nil {
+			fmt.Fprintln(out, version)
+		}
+
+		return err
+	case "validate":
+		if err = migrator.Validate(ctx); err == nil {
+			fmt.Fprintln(out, "migrations are valid")
+		}
+
+		return err
 	}
+
+	var partial *goose.PartialError
+	if errors.As(err, &partial) {
+		results = append(partial.Applied, partial.Failed)
+	}
+
+	for _, result := range results {
+		fmt.Fprintln(out, result)
+	}
+
+	if err == nil && len(results) == 0 {
+		fmt.Fprintln(out, "no migrations to run")
+	}
+
+	return err
+}
+
+// plan prints the SQL the command would run. The commands not changing the database run as usual.
+func plan(ctx context.Context, migrator *migration.Migrator, command string, version int64, out io.Writer) error {
+	var (
+		scripts []migration.Script
+		err     error
+	)
+
+	switch command {
+	case "up":
+		scripts, err = migrator.PlanUp(ctx, goose.MaxVersion)
+	case "up-to":
+		scripts, err = migrator.PlanUp(ctx, version)
+	case "down":
+		if scripts, err = migrator.PlanDown(ctx, -1); err == nil && len(scripts) == 0 {
+			err = migration.ErrNoMigrations
+		}
+	case "down-to":
+		scripts, err = migrator.PlanDown(ctx, version)
+	case "redo":
+		scripts, err = migrator.PlanRedo(ctx)
+	default:
+		return execute(ctx, migrator, command, version, out)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(scripts) == 0 {
+		fmt.Fprintln(out, "-- no migrations to run")
+	}
+
+	for _, script := range scripts {
+		fmt.Fprintf(out, "-- %s %s\n%s\n\n", script.Direction, script.Path, script.SQL)
+	}
+
+	return nil
+}
+
+func printStatus(ctx context.Context, migrator *migration.Migrator, out io.Writer) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "VERSION\tSTATE\tAPPLIED AT\tFILE")
+
+	for _, status := range statuses {
+		appliedAt := "-"
+		if !status.AppliedAt.IsZero() {
+			appliedAt = status.AppliedAt.Format(time.DateTime)
+		}
+
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", status.Source.Version, status.State, appliedAt, status.Source.Path)
+	}
+
+	return writer.Flush()
+}
+
+func usageError(message string) int {
+	fmt.Fprintf(os.Stderr, "migrator: %s\n\n%s", message, usage)
+
+	return exitUsage
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	log.Printf("migrator: %s", err)
+
+	switch {
+	case errors.Is(err, migration.ErrLocked):
+		return exitLocked
+	case errors.Is(err, migration.ErrInvalidMigration):
+		return exitInvalid
+	case errors.Is(err, migration.ErrNoMigrations):
+		return exitNoChange
+	default:
+		return exitFailed
+	}
+}
+
+// dialectOf returns the goose dialect of driver. The memory driver has no schema, dialectOf returns an empty
+// dialect for it.
+func dialectOf(driver string) goose.Dialect {
+	switch driver {
+	case config.DriverMemory:
+		return ""
+	case config.DriverSQLite:
+		return goose.DialectSQLite3
+	default:
+		return goose.DialectPostgres
+	}
+}
+
+// open connects to the database cfg.DB.Driver selects.
+func open(cfg *config.AppConfig) (*sql.DB, error) {
+	if cfg.DB.Driver == config.DriverSQLite {
+		db, err := sqlite.Open(cfg.DB.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open SQLite DB: %w", err)
+		}
+
+		return db, nil
+	}
+
+	connConfig, err := pgx.ParseConfig(cfg.DB.ConnString())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse conn config: %w", err)
+	}
+
+	return stdlib.OpenDB(*connConfig), nil
 }
@@ -35,20 +35,23 @@ func main() {
 
 	sloger.Info("starting Subscription Service")
 
-	app, err := application.New(sloger, cfg)
+	// Listen for signals before connecting, so waiting for the DB can be interrupted too.
+	signalCtx, stop := signal.NotifyContext(context.Background(),
+		os.Interrupt,
+		syscall.SIGINT,
+		syscall.SIGTERM)
+	defer stop()
+
+	app, err := application.New(signalCtx, sloger, cfg)
 	if err != nil {
 		log.Fatal("No App cannot start server", slog.String("err", err.Error()))
 	}
 
-	go app.Run(cfg.Srv.Host, cfg.Srv.Port)
-
-	stopChan := make(chan os.Signal, 1)
-	signal.Notify(stopChan,
-		os.Interrupt,
-		syscall.SIGINT,
-		syscall.SIGTERM)
+	if err := app.Start(signalCtx); err != nil {
+		log.Fatal("No App cannot start server", slog.String("err", err.Error()))
+	}
 
-	<-stopChan
+	<-signalCtx.Done()
 	sloger.Info("Received interrupt signal")
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Srv.ServerShutdownTimeout)
 	defer cancel()
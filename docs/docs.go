@@ -24,43 +24,106 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/subscription/users": {
+        "/healthz": {
             "get": {
-                "description": "Получает список подписок пользователя по userId из cookie",
+                "description": "Отвечает, пока процесс жив, не обращаясь к зависимостям",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "health"
                 ],
-                "summary": "Получить список подписок",
+                "summary": "Проверка жизнеспособности",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthJSON"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Проверяет доступность БД и версию миграций, сообщает о сбоях фоновых задач.\nПерестает отвечать успехом с началом остановки сервиса",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Проверка готовности",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthJSON"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthJSON"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/analytics": {
+            "get": {
+                "description": "Возвращает расходы за период с группировкой по месяцу, сервису, категории и валюте\nв любой комбинации, а также итоги, средние значения и изменения к предыдущему месяцу\nСовместные подписки домохозяйства учитываются долей пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Получить аналитику расходов",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "userId из cookie",
-                        "name": "userId",
-                        "in": "header",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода в формате MM-YYYY",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода в формате MM-YYYY",
+                        "name": "end_date",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Измерения через запятую: month, service, category, currency",
+                        "name": "group_by",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.SubscriptionListDTO"
-                            }
+                            "$ref": "#/definitions/models.SpendAnalyticsJSON"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Ошибка в параметрах запроса",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "type": "string"
                         }
@@ -68,9 +131,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriptions": {
+        "/subscription/budget": {
             "put": {
-                "description": "Обновляет подписку по id переданному в query-параметрах",
+                "description": "Обновляет бюджет по id переданному в query-параметрах. Доступно владельцу бюджета, user_id бюджета\nне меняется",
                 "consumes": [
                     "application/json"
                 ],
@@ -78,30 +141,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Обновить подписку",
+                "summary": "Обновить бюджет",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID подписки для обновления",
+                        "description": "ID бюджета для обновления",
                         "name": "id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Данные подписки для обновления",
-                        "name": "subscription",
+                        "description": "Данные бюджета для обновления",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SubscriptionListJSON"
+                            "$ref": "#/definitions/models.BudgetJSON"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, владелец бюджета",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Подписка успешно обновлена",
+                        "description": "Бюджет успешно обновлен",
                         "schema": {
                             "type": "string"
                         }
@@ -112,16 +182,55 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Бюджет принадлежит другому пользователю",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Запись уже существует",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение, в constraint и column его имя и столбец",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "Сервис временно недоступен, повторите запрос",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Добавляет подписку на сервис",
+                "description": "Добавляет месячный бюджет пользователя: общий (scope=total), на категорию (scope=category)\nили на сервис (scope=service)",
                 "consumes": [
                     "application/json"
                 ],
@@ -129,71 +238,114 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Создать новую подписку",
+                "summary": "Создать бюджет",
                 "parameters": [
                     {
-                        "description": "Данные подписки",
-                        "name": "subscription",
+                        "description": "Данные бюджета",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SubscriptionListJSON"
+                            "$ref": "#/definitions/models.BudgetJSON"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Подписка успешно создана",
+                        "description": "Бюджет успешно создан",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Неправильный запрос или дубликат подписки",
+                        "description": "Неправильный запрос или дубликат бюджета",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "409": {
+                        "description": "Запись уже существует",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение, в constraint и column его имя и столбец",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "Сервис временно недоступен, повторите запрос",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             },
             "delete": {
-                "description": "Удаление подписки по id из query-параметров",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Удаление бюджета по id из query-параметров. Доступно владельцу бюджета",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Удалить подписку",
+                "summary": "Удалить бюджет",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID подписки для удаления",
+                        "description": "ID бюджета для удаления",
                         "name": "id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, владелец бюджета",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Подписка успешно удалена",
+                        "description": "Бюджет успешно удален",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Некорректный id или не найдена подписка",
+                        "description": "Некорректный id или не найден бюджет",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Бюджет принадлежит другому пользователю",
                         "schema": {
                             "type": "string"
                         }
@@ -207,9 +359,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriptions/cost": {
+        "/subscription/budgets": {
+            "get": {
+                "description": "Получает бюджеты пользователя по user_id из query-параметров",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Получить список бюджетов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BudgetJSON"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/forecast": {
             "post": {
-                "description": "Возвращает общую стоимость подписок на сервис в указанный период",
+                "description": "Прогнозирует расходы по активным подпискам на указанное число месяцев (по умолчанию 12)\nс учетом периодичности оплаты, запланированных изменений цены, пробных периодов и дат окончания.\nПоле what_if позволяет проверить гипотетические отмены и добавления подписок без их сохранения",
                 "consumes": [
                     "application/json"
                 ],
@@ -217,28 +419,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "forecast"
                 ],
-                "summary": "Получить сумму стоимости по датам и имени сервиса",
+                "summary": "Получить прогноз расходов",
                 "parameters": [
                     {
-                        "description": "Параметры периода и имени сервиса",
-                        "name": "periodCost",
+                        "description": "Параметры прогноза",
+                        "name": "forecast",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SubscriptionListToCostJSON"
+                            "$ref": "#/definitions/models.ForecastRequestJSON"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Общая стоимость в поле result",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/models.ForecastJSON"
                         }
                     },
                     "400": {
@@ -255,40 +454,2063 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.SubscriptionListDTO": {
-            "type": "object",
-            "properties": {
-                "price": {
-                    "type": "integer",
-                    "example": 400
-                },
-                "service_name": {
-                    "type": "string",
-                    "example": "Netflix"
-                },
-                "start_date": {
-                    "type": "string",
-                    "example": "09-2025"
-                }
-            }
         },
-        "models.SubscriptionListJSON": {
-            "type": "object",
-            "properties": {
-                "price": {
-                    "type": "integer",
-                    "example": 400
-                },
-                "service_name": {
-                    "type": "string",
-                    "example": "Netflix"
-                },
-                "start_date": {
-                    "type": "string",
-                    "example": "09-2025"
+        "/subscription/household": {
+            "get": {
+                "description": "Возвращает домохозяйство с его участниками по id из query-параметров. Доступно его участникам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Получить домохозяйство",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID домохозяйства",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, участник домохозяйства",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdJSON"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к домохозяйству",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Домохозяйство не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает домохозяйство (семью, группу) для совместных подписок. Владелец становится его участником",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Создать домохозяйство",
+                "parameters": [
+                    {
+                        "description": "Название, владелец и участники",
+                        "name": "household",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdJSON"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdJSON"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Сервис временно недоступен, повторите запрос",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/household/member": {
+            "post": {
+                "description": "Добавляет участника в домохозяйство. Доступно владельцу домохозяйства",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Добавить участника домохозяйства",
+                "parameters": [
+                    {
+                        "description": "Домохозяйство и пользователь",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdMemberJSON"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, владелец домохозяйства",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Участник успешно добавлен",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к домохозяйству",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Домохозяйство не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Запись уже существует",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение, в constraint и column его имя и столбец",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Сервис временно недоступен, повторите запрос",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет участника вместе с его долями в совместных подписках. Подписки, которые он оплачивает,\nперестают быть совместными. Доступно владельцу домохозяйства, самого владельца удалить нельзя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Удалить участника домохозяйства",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID домохозяйства",
+                        "name": "household_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, владелец домохозяйства",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Участник успешно удален",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры, владелец или участник не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к домохозяйству",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/household/settle-up": {
+            "get": {
+                "description": "Считает, кто кому и сколько должен за списания совместных подписок домохозяйства за период,\nотдельно по каждой валюте. Число платежей меньше числа участников. Доступно участникам домохозяйства",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Рассчитать взаиморасчеты",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID домохозяйства",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода в формате MM-YYYY",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода в формате MM-YYYY",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, участник домохозяйства",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SettleUpJSON"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к домохозяйству",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Домохозяйство не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/household/share": {
+            "put": {
+                "description": "Задает правило разделения стоимости подписки между участниками домохозяйства: поровну (split=equal),\nв процентах (split=percentage, value — процент участника) или фиксированными суммами (split=fixed,\nvalue — сумма участника). Владелец подписки платит за нее и получает остаток, в shares его не указывают.\nЗаменяет ранее заданное правило. Доступно владельцу подписки",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Сделать подписку совместной",
+                "parameters": [
+                    {
+                        "description": "Подписка, домохозяйство и доли участников",
+                        "name": "share",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SharedSubscriptionJSON"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, владелец подписки",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Правило разделения успешно сохранено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Подписка принадлежит другому пользователю",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение, в constraint и column его имя и столбец",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Сервис временно недоступен, повторите запрос",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет правило разделения, стоимость подписки снова целиком приходится на ее владельца.\nДоступно владельцу подписки",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Прекратить совместное использование подписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "subscription_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, владелец подписки",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Правило разделения успешно удалено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный id или подписка не совместная",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Подписка принадлежит другому пользователю",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/organization": {
+            "get": {
+                "description": "Возвращает организацию с ее участниками, их ролями и отделами. Доступно участникам организации",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Получить организацию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID организации",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, пользователь, выполняющий запрос",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationJSON"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не состоит в организации",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает организацию для командных подписок. Пользователь admin_id становится ее администратором",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Создать организацию",
+                "parameters": [
+                    {
+                        "description": "Название и администратор",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationJSON"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationJSON"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Сервис временно недоступен, повторите запрос",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/organization/member": {
+            "put": {
+                "description": "Добавляет участника или меняет его роль (admin или member) и отдел. Доступно администраторам\nорганизации, свою роль администратор изменить не может",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Добавить или изменить участника организации",
+                "parameters": [
+                    {
+                        "description": "Организация, пользователь, роль и отдел",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationMemberJSON"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, администратор, выполняющий запрос",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Участник успешно сохранен",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не является администратором организации",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение, в constraint и column его имя и столбец",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Сервис временно недоступен, повторите запрос",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет участника из организации. Доступно администраторам организации, удалить себя администратор не может.\nПодписки участника остаются у организации и относятся к отделу «unassigned», если у них нет своего отдела",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Удалить участника организации",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID организации",
+                        "name": "organization_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, администратор, выполняющий запрос",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Участник успешно удален",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры или участник не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не является администратором организации",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/organization/seat-history": {
+            "get": {
+                "description": "Возвращает изменения числа мест подписки от старых к новым. Доступно владельцу подписки\nи администраторам организации, которой она принадлежит",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Получить историю мест подписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "subscription_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, пользователь, выполняющий запрос",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SeatChangeJSON"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Подписка принадлежит другому пользователю",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/organization/spend": {
+            "get": {
+                "description": "Суммирует списания подписок организации за период по отделам, сервисам и валютам. Стоимость\nсписания — цена места, умноженная на число мест в том месяце. Подписка относится к своему отделу,\nа без него — к отделу своего владельца. Администратор видит расходы всей организации (scope=organization),\nучастник — только своих подписок (scope=member)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Получить расходы организации",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID организации",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода в формате MM-YYYY",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода в формате MM-YYYY",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, пользователь, выполняющий запрос",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationSpendJSON"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не состоит в организации",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/price-change": {
+            "post": {
+                "description": "Добавляет изменение цены подписки, вступающее в силу с указанного месяца. Цена должна быть\nположительной, изменить цену может только владелец подписки",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "forecast"
+                ],
+                "summary": "Запланировать изменение цены",
+                "parameters": [
+                    {
+                        "description": "Изменение цены",
+                        "name": "priceChange",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceChangeJSON"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, владелец подписки",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Изменение цены успешно сохранено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Подписка принадлежит другому пользователю",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Запись уже существует",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение, в constraint и column его имя и столбец",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Сервис временно недоступен, повторите запрос",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/recommendations": {
+            "get": {
+                "description": "Находит пересекающиеся сервисы одной категории, точные и похожие дубликаты подписок\nи подписки дороже цены каталога, для каждой находки оценивает годовую экономию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Получить рекомендации по экономии",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecommendationsJSON"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/users": {
+            "get": {
+                "description": "Получает список подписок пользователя по userId из cookie",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получить список подписок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "userId из cookie",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SubscriptionListDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions": {
+            "put": {
+                "description": "Обновляет подписку по id переданному в query-параметрах. Изменение числа мест сохраняется в истории мест",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Обновить подписку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки для обновления",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные подписки для обновления",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionListJSON"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, владелец подписки",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка успешно обновлена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Неправильный запрос или невалидные данные",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Подписка принадлежит другому пользователю или пользователь не состоит в организации",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Запись уже существует",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение, в constraint и column его имя и столбец",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Сервис временно недоступен, повторите запрос",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавляет подписку на сервис. Стоимость подписки — цена места, умноженная на число мест (seats).\nПодписку организации (organization_id) может оформить только участник этой организации",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Создать новую подписку",
+                "parameters": [
+                    {
+                        "description": "Данные подписки",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionListJSON"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка успешно создана",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Неправильный запрос или дубликат подписки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не состоит в организации",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Запись уже существует",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение, в constraint и column его имя и столбец",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Сервис временно недоступен, повторите запрос",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаление подписки по id из query-параметров",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Удалить подписку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки для удаления",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "userId из cookie, владелец подписки",
+                        "name": "userId",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка успешно удалена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный id или не найдена подписка",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан userId в cookie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Подписка принадлежит другому пользователю",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение, в constraint и column его имя и столбец",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Сервис временно недоступен, повторите запрос",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/cost": {
+            "post": {
+                "description": "Возвращает общую стоимость подписок на сервис в указанный период\nСтоимость подписки — цена места, умноженная на число мест.\nСовместные подписки домохозяйства учитываются долей пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получить сумму стоимости по датам и имени сервиса",
+                "parameters": [
+                    {
+                        "description": "Параметры периода и имени сервиса",
+                        "name": "periodCost",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionListToCostJSON"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Общая стоимость в поле result",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в параметрах запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.BudgetJSON": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "category"
+                },
+                "scope_value": {
+                    "type": "string",
+                    "example": "music"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                }
+            }
+        },
+        "models.CurrencyTotalJSON": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 48000
+                }
+            }
+        },
+        "models.DepartmentSpendJSON": {
+            "type": "object",
+            "properties": {
+                "department": {
+                    "type": "string",
+                    "example": "Разработка"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ServiceSpendJSON"
+                    }
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyTotalJSON"
+                    }
+                }
+            }
+        },
+        "models.FindingJSON": {
+            "type": "object",
+            "properties": {
+                "annual_saving": {
+                    "type": "integer",
+                    "example": 2388
+                },
+                "category": {
+                    "type": "string",
+                    "example": "music"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Несколько подписок в категории music"
+                },
+                "service_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Spotify",
+                        "Yandex Music"
+                    ]
+                },
+                "subscription_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "example": "overlap"
+                }
+            }
+        },
+        "models.ForecastJSON": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "12-2026"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ForecastLineJSON"
+                    }
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ForecastMonthJSON"
+                    }
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "01-2026"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 4800
+                }
+            }
+        },
+        "models.ForecastLineJSON": {
+            "type": "object",
+            "properties": {
+                "charges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ForecastMonthJSON"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "hypothetical": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "subscription_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 4800
+                }
+            }
+        },
+        "models.ForecastMonthJSON": {
+            "type": "object",
+            "properties": {
+                "month": {
+                    "type": "string",
+                    "example": "01-2026"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 400
+                }
+            }
+        },
+        "models.ForecastRequestJSON": {
+            "type": "object",
+            "properties": {
+                "months": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "01-2026"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                },
+                "what_if": {
+                    "$ref": "#/definitions/models.ForecastWhatIfJSON"
+                }
+            }
+        },
+        "models.ForecastWhatIfJSON": {
+            "type": "object",
+            "properties": {
+                "add": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubscriptionListJSON"
+                    }
+                },
+                "cancel_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "cancel_services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Netflix"
+                    ]
+                }
+            }
+        },
+        "models.HealthJSON": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "models.HouseholdJSON": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Семья"
+                },
+                "owner_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                }
+            }
+        },
+        "models.HouseholdMemberJSON": {
+            "type": "object",
+            "properties": {
+                "household_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d"
+                }
+            }
+        },
+        "models.OrganizationJSON": {
+            "type": "object",
+            "properties": {
+                "admin_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrganizationMemberJSON"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ООО Ромашка"
+                }
+            }
+        },
+        "models.OrganizationMemberJSON": {
+            "type": "object",
+            "properties": {
+                "department": {
+                    "type": "string",
+                    "example": "Разработка"
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "role": {
+                    "type": "string",
+                    "example": "member"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d"
+                }
+            }
+        },
+        "models.OrganizationSpendJSON": {
+            "type": "object",
+            "properties": {
+                "departments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DepartmentSpendJSON"
+                    }
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "12-2025"
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "organization"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "01-2025"
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyTotalJSON"
+                    }
+                }
+            }
+        },
+        "models.PriceChangeJSON": {
+            "type": "object",
+            "properties": {
+                "effective_date": {
+                    "type": "string",
+                    "example": "01-2026"
+                },
+                "price": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "subscription_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.RecommendationsJSON": {
+            "type": "object",
+            "properties": {
+                "findings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FindingJSON"
+                    }
+                },
+                "total_annual_saving": {
+                    "type": "integer",
+                    "example": 2388
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                }
+            }
+        },
+        "models.SeatChangeJSON": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string",
+                    "example": "2025-09-01T10:00:00Z"
+                },
+                "previous_seats": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "seats": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "subscription_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.ServiceSpendJSON": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "seats": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Slack"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 24000
+                }
+            }
+        },
+        "models.SettleUpJSON": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "06-2025"
+                },
+                "household_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "settlements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SettlementJSON"
+                    }
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "01-2025"
+                }
+            }
+        },
+        "models.SettlementJSON": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 270
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                }
+            }
+        },
+        "models.ShareJSON": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "type": "string",
+                    "example": "7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d"
+                },
+                "value": {
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
+        "models.SharedSubscriptionJSON": {
+            "type": "object",
+            "properties": {
+                "household_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "shares": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ShareJSON"
+                    }
+                },
+                "split": {
+                    "type": "string",
+                    "example": "percentage"
+                },
+                "subscription_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.SpendAnalyticsGroupJSON": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number",
+                    "example": 400
+                },
+                "category": {
+                    "type": "string",
+                    "example": "video"
+                },
+                "charges": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "delta": {
+                    "type": "integer",
+                    "example": -100
+                },
+                "month": {
+                    "type": "string",
+                    "example": "09-2025"
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 400
+                }
+            }
+        },
+        "models.SpendAnalyticsJSON": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "12-2025"
+                },
+                "group_by": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "month",
+                        "service"
+                    ]
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SpendAnalyticsGroupJSON"
+                    }
+                },
+                "monthly_average": {
+                    "type": "number",
+                    "example": 400
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "01-2025"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 4800
+                }
+            }
+        },
+        "models.SubscriptionListDTO": {
+            "type": "object",
+            "properties": {
+                "department": {
+                    "type": "string",
+                    "example": "Разработка"
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "price": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "seats": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "09-2025"
+                }
+            }
+        },
+        "models.SubscriptionListJSON": {
+            "type": "object",
+            "properties": {
+                "billing_period": {
+                    "type": "string",
+                    "example": "monthly"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "department": {
+                    "type": "string",
+                    "example": "Разработка"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "12-2025"
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "price": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "seats": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "09-2025"
+                },
+                "trial_end_date": {
+                    "type": "string",
+                    "example": "10-2025"
                 },
                 "user_id": {
                     "type": "string",
@@ -0,0 +1,270 @@
+// Package cache keeps the results of the subscription queries in process memory. Every mutation drops the
+// cached results of the user it touches, and with Postgres the other replicas of the service are told to
+// drop theirs through LISTEN/NOTIFY.
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+// Channel is the notification channel the invalidations are spread on, the payload is the user ID.
+const Channel = "subscription_cache"
+
+const (
+	defaultSize       = 10000
+	defaultTTL        = time.Minute
+	defaultRetryDelay = 5 * time.Second
+)
+
+const querySubscriptions = "subscriptions"
+
+// Repository decorates a storage.Repository. The subscription list of a user is cached, the costs are
+// computed from it, so they share the entry whatever their period and service. A single subscription is
+// read through, the ownership checks need it fresh.
+type Repository struct {
+	repo       storage.Repository
+	notifier   storage.NotifyRepository
+	logger     *slog.Logger
+	cache      *lru
+	retryDelay time.Duration
+}
+
+// New caches up to size results of repo for ttl each. notifier spreads the invalidations, nil keeps them
+// to this process. retryDelay is the wait before listening again after losing the notifier.
+func New(repo storage.Repository, notifier storage.NotifyRepository, logger *slog.Logger,
+	size int, ttl, retryDelay time.Duration,
+) *Repository {
+	if size <= 0 {
+		size = defaultSize
+	}
+
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	if retryDelay <= 0 {
+		retryDelay = defaultRetryDelay
+	}
+
+	return &Repository{
+		repo:       repo,
+		notifier:   notifier,
+		logger:     logger,
+		cache:      newLRU(size, ttl),
+		retryDelay: retryDelay,
+	}
+}
+
+func (r *Repository) GetSubscriptionsByUserID(ctx context.Context, id uuid.UUID) ([]models.Subscription, error) {
+	k := key{user: id, query: querySubscriptions}
+
+	value, generation, ok := r.cache.get(k)
+	if ok {
+		return slices.Clone(value.([]models.Subscription)), nil
+	}
+
+	subs, err := r.load(ctx, id)
+	if err != nil {
+		return subs, err
+	}
+
+	r.cache.set(k, slices.Clone(subs), generation)
+
+	return subs, nil
+}
+
+// GetSubscriptionsByUserIDs answers the cached lists and loads the missing ones with a single query when repo
+// can batch them.
+func (r *Repository) GetSubscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) ([]models.Subscription, error) {
+	var (
+		subs       []models.Subscription
+		missing    []uuid.UUID
+		generation uint64
+	)
+
+	for _, id := range ids {
+		value, current, ok := r.cache.get(key{user: id, query: querySubscriptions})
+		if ok {
+			subs = append(subs, value.([]models.Subscription)...)
+
+			continue
+		}
+
+		// The generation only grows, the one of the first miss covers the invalidations of all of them.
+		if len(missing) == 0 {
+			generation = current
+		}
+
+		missing = append(missing, id)
+	}
+
+	if len(missing) == 0 {
+		return subs, nil
+	}
+
+	loaded, err := r.loadMany(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range missing {
+		r.cache.set(key{user: id, query: querySubscriptions}, slices.Clone(loaded[id]), generation)
+		subs = append(subs, loaded[id]...)
+	}
+
+	return subs, nil
+}
+
+// loadMany is load for several users, with a single query when repo can batch them.
+func (r *Repository) loadMany(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]models.Subscription, error) {
+	var (
+		subs []models.Subscription
+		err  error
+	)
+
+	res := make(map[uuid.UUID][]models.Subscription, len(ids))
+
+	switch repo := r.repo.(type) {
+	case storage.PrimaryRepository:
+		subs, err = repo.GetPrimarySubscriptionsByUserIDs(ctx, ids)
+	case storage.BatchRepository:
+		subs, err = repo.GetSubscriptionsByUserIDs(ctx, ids)
+	default:
+		for _, id := range ids {
+			if res[id], err = r.load(ctx, id); err != nil {
+				return nil, err
+			}
+		}
+
+		return res, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range subs {
+		res[sub.UserID] = append(res[sub.UserID], sub)
+	}
+
+	return res, nil
+}
+
+// load reads a missed list from the primary when repo has replicas. A replica may not have replayed the
+// write another replica of the service has just notified, and its stale list would stay cached until the TTL.
+func (r *Repository) load(ctx context.Context, id uuid.UUID) ([]models.Subscription, error) {
+	if primary, ok := r.repo.(storage.PrimaryRepository); ok {
+		return primary.GetPrimarySubscriptionsByUserID(ctx, id)
+	}
+
+	return r.repo.GetSubscriptionsByUserID(ctx, id)
+}
+
+func (r *Repository) GetSubscription(ctx context.Context, id int) (models.Subscription, error) {
+	return r.repo.GetSubscription(ctx, id)
+}
+
+func (r *Repository) PostSubscription(ctx context.Context, sub models.Subscription) (int, error) {
+	id, err := r.repo.PostSubscription(ctx, sub)
+	if err != nil {
+		return id, err
+	}
+
+	r.invalidate(ctx, sub.UserID)
+
+	return id, nil
+}
+
+// UpdateSubscription invalidates both the old and the new owner, the update may move the subscription.
+func (r *Repository) UpdateSubscription(ctx context.Context, sub models.Subscription) error {
+	owner, ownerErr := r.repo.GetSubscription(ctx, sub.ID)
+
+	if err := r.repo.UpdateSubscription(ctx, sub); err != nil {
+		return err
+	}
+
+	if ownerErr == nil && owner.UserID != sub.UserID {
+		r.invalidate(ctx, owner.UserID)
+	}
+
+	r.invalidate(ctx, sub.UserID)
+
+	return nil
+}
+
+func (r *Repository) DeleteSubscription(ctx context.Context, id int) error {
+	// A missing subscription fails the delete below as well.
+	owner, ownerErr := r.repo.GetSubscription(ctx, id)
+
+	if err := r.repo.DeleteSubscription(ctx, id); err != nil {
+		return err
+	}
+
+	if ownerErr == nil {
+		r.invalidate(ctx, owner.UserID)
+	}
+
+	return nil
+}
+
+// invalidate drops the entries of user here and, through the notifier, in the other replicas. A failed
+// notification leaves their entries until the TTL.
+func (r *Repository) invalidate(ctx context.Context, user uuid.UUID) {
+	r.cache.invalidate(user)
+
+	if r.notifier == nil {
+		return
+	}
+
+	if err := r.notifier.Notify(ctx, Channel, user.String()); err != nil {
+		r.logger.Warn("Couldn't spread cache invalidation", "user_id", user, slog.Any("error_details", err))
+	}
+}
+
+// Run listens for the invalidations of the other replicas until ctx is done. While it can't listen the
+// notifications are lost, so the whole cache is dropped before listening again.
+func (r *Repository) Run(ctx context.Context) {
+	if r.notifier == nil {
+		return
+	}
+
+	for {
+		err := r.notifier.Listen(ctx, Channel, r.handle)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			err = errors.New("listening ended")
+		}
+
+		r.logger.Error("Lost cache invalidations, dropping the cache", "retry_in", r.retryDelay.String(),
+			slog.Any("error_details", err))
+		r.cache.flush()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.retryDelay):
+		}
+	}
+}
+
+func (r *Repository) handle(payload string) {
+	user, err := uuid.Parse(payload)
+	if err != nil {
+		r.logger.Warn("Invalid cache invalidation", "payload", payload)
+
+		return
+	}
+
+	r.cache.invalidate(user)
+}
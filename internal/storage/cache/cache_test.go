@@ -0,0 +1,287 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage/memory"
+	"github.com/Ostmind/subscriptionservice/internal/storage/storagetest"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+var logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, New(memory.New(), nil, logger, 0, 0, 0))
+}
+
+// bus is a notification channel shared by the replicas in a test.
+type bus struct {
+	mu        sync.Mutex
+	listeners []func(payload string)
+	listening chan struct{}
+}
+
+func newBus() *bus {
+	return &bus{listening: make(chan struct{}, 10)}
+}
+
+func (b *bus) Notify(_ context.Context, _, payload string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, handle := range b.listeners {
+		handle(payload)
+	}
+
+	return nil
+}
+
+func (b *bus) Listen(ctx context.Context, _ string, handle func(payload string)) error {
+	b.mu.Lock()
+	b.listeners = append(b.listeners, handle)
+	b.mu.Unlock()
+
+	b.listening <- struct{}{}
+	<-ctx.Done()
+
+	return nil
+}
+
+func TestInvalidation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := memory.New()
+	notifications := newBus()
+
+	// Two replicas of the service over the same DB.
+	first := New(db, notifications, logger, 0, time.Hour, 0)
+	second := New(db, notifications, logger, 0, time.Hour, 0)
+
+	for _, replica := range []*Repository{first, second} {
+		go replica.Run(ctx)
+		<-notifications.listening
+	}
+
+	user := uuid.New()
+	sub := models.Subscription{UserID: user, ServiceName: "Yandex Plus", Price: 400, Currency: "RUB",
+		BillingPeriod: "monthly", StartDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)}
+
+	id, err := first.PostSubscription(ctx, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub.ID = id
+
+	list := func(replica *Repository) []models.Subscription {
+		t.Helper()
+
+		subs, err := replica.GetSubscriptionsByUserID(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return subs
+	}
+
+	if len(list(first)) != 1 || len(list(second)) != 1 {
+		t.Fatal("expected the posted subscription")
+	}
+
+	// A write behind the cache isn't seen until an invalidation.
+	sub.Price = 500
+	if err := db.UpdateSubscription(ctx, sub); err != nil {
+		t.Fatal(err)
+	}
+
+	if list(second)[0].Price != 400 {
+		t.Error("expected the cached list")
+	}
+
+	sub.Price = 600
+	if err := first.UpdateSubscription(ctx, sub); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := list(second)[0].Price; got != 600 {
+		t.Errorf("expected the update to reach the other replica, got price %d", got)
+	}
+
+	list(second)[0].Price = 0
+
+	if got := list(second)[0].Price; got != 600 {
+		t.Errorf("expected the callers not to share the cached list, got price %d", got)
+	}
+
+	if err := first.DeleteSubscription(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := list(second); len(got) != 0 {
+		t.Errorf("expected the delete to reach the other replica, got %v", got)
+	}
+}
+
+// laggingReplicas is a primary with read replicas that never replay its writes.
+type laggingReplicas struct {
+	*memory.Storage
+	replica    *memory.Storage
+	batchCalls *int
+}
+
+func (db laggingReplicas) GetSubscriptionsByUserID(ctx context.Context, id uuid.UUID) ([]models.Subscription, error) {
+	return db.replica.GetSubscriptionsByUserID(ctx, id)
+}
+
+func (db laggingReplicas) GetPrimarySubscriptionsByUserID(ctx context.Context, id uuid.UUID) ([]models.Subscription, error) {
+	return db.Storage.GetSubscriptionsByUserID(ctx, id)
+}
+
+func (db laggingReplicas) GetPrimarySubscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) ([]models.Subscription, error) {
+	*db.batchCalls++
+
+	var subs []models.Subscription
+
+	for _, id := range ids {
+		userSubs, err := db.Storage.GetSubscriptionsByUserID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		subs = append(subs, userSubs...)
+	}
+
+	return subs, nil
+}
+
+func TestInvalidationWithReplicas(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := laggingReplicas{Storage: memory.New(), replica: memory.New(), batchCalls: new(int)}
+	notifications := newBus()
+
+	first := New(db, notifications, logger, 0, time.Hour, 0)
+	second := New(db, notifications, logger, 0, time.Hour, 0)
+
+	for _, replica := range []*Repository{first, second} {
+		go replica.Run(ctx)
+		<-notifications.listening
+	}
+
+	user := uuid.New()
+
+	if subs, err := second.GetSubscriptionsByUserID(ctx, user); err != nil || len(subs) != 0 {
+		t.Fatalf("expected no subscriptions, got %v %v", subs, err)
+	}
+
+	sub := models.Subscription{UserID: user, ServiceName: "Yandex Plus", Price: 400, Currency: "RUB",
+		BillingPeriod: "monthly", StartDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)}
+
+	if _, err := first.PostSubscription(ctx, sub); err != nil {
+		t.Fatal(err)
+	}
+
+	// The notified replica of the service reloads the list while the DB replicas still lag.
+	if subs, err := second.GetSubscriptionsByUserID(ctx, user); err != nil || len(subs) != 1 {
+		t.Errorf("expected the list to be reloaded from the primary, got %v %v", subs, err)
+	}
+}
+
+func TestGetSubscriptionsByUserIDs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := laggingReplicas{Storage: memory.New(), replica: memory.New(), batchCalls: new(int)}
+	repo := New(db, nil, logger, 0, time.Hour, 0)
+
+	users := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, user := range users[:2] {
+		sub := models.Subscription{UserID: user, ServiceName: "Yandex Plus", Price: 400, Currency: "RUB",
+			BillingPeriod: "monthly", StartDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)}
+
+		if _, err := repo.PostSubscription(ctx, sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := repo.GetSubscriptionsByUserID(ctx, users[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	subs, err := repo.GetSubscriptionsByUserIDs(ctx, users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(subs) != 2 || *db.batchCalls != 1 {
+		t.Fatalf("expected the misses loaded together, got %d subscriptions in %d calls", len(subs), *db.batchCalls)
+	}
+
+	if subs, _ := repo.GetSubscriptionsByUserIDs(ctx, users); len(subs) != 2 || *db.batchCalls != 1 {
+		t.Errorf("expected the lists to be cached, got %d subscriptions in %d calls", len(subs), *db.batchCalls)
+	}
+}
+
+func TestLRU(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	cache := newLRU(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	alice, bob := uuid.New(), uuid.New()
+	first, second, third := key{alice, "first"}, key{alice, "second"}, key{bob, "first"}
+
+	set := func(k key, value any) {
+		_, generation, _ := cache.get(k)
+		cache.set(k, value, generation)
+	}
+
+	set(first, 1)
+	set(second, 2)
+
+	if _, _, ok := cache.get(first); !ok {
+		t.Fatal("expected a hit")
+	}
+
+	// first was used last, second is evicted.
+	set(third, 3)
+
+	if _, _, ok := cache.get(second); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+
+	cache.invalidate(alice)
+
+	if _, _, ok := cache.get(first); ok || cache.len() != 1 {
+		t.Errorf("expected only the entries of the other user to stay, got %d", cache.len())
+	}
+
+	now = now.Add(time.Minute)
+
+	if _, _, ok := cache.get(third); ok {
+		t.Error("expected the entry to expire")
+	}
+
+	_, generation, _ := cache.get(first)
+	cache.invalidate(bob)
+	cache.set(first, 1, generation)
+
+	if _, _, ok := cache.get(first); ok {
+		t.Error("expected a result loaded across an invalidation not to be kept")
+	}
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// key names a cached query result: the user it belongs to and the query with its parameters.
+type key struct {
+	user  uuid.UUID
+	query string
+}
+
+type entry struct {
+	key     key
+	value   any
+	expires time.Time
+}
+
+// lru keeps up to size entries for ttl each, evicting the least recently used one when full. The entries of
+// a user can be dropped together.
+type lru struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[key]*list.Element
+	users   map[uuid.UUID]map[key]struct{}
+	// generation changes on every invalidation, a result loaded across one may be stale and isn't kept.
+	generation uint64
+}
+
+func newLRU(size int, ttl time.Duration) *lru {
+	return &lru{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		order:   list.New(),
+		entries: make(map[key]*list.Element),
+		users:   make(map[uuid.UUID]map[key]struct{}),
+	}
+}
+
+// get returns the value of k, or the generation to pass to set when it is missing.
+func (c *lru) get(k key) (value any, generation uint64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[k]
+	if !ok {
+		return nil, c.generation, false
+	}
+
+	e := elem.Value.(*entry)
+	if !c.now().Before(e.expires) {
+		c.remove(elem)
+
+		return nil, c.generation, false
+	}
+
+	c.order.MoveToFront(elem)
+
+	return e.value, c.generation, true
+}
+
+// set keeps value under k unless there was an invalidation since get returned generation.
+func (c *lru) set(k key, value any, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if elem, ok := c.entries[k]; ok {
+		c.remove(elem)
+	}
+
+	c.entries[k] = c.order.PushFront(&entry{key: k, value: value, expires: c.now().Add(c.ttl)})
+
+	if c.users[k.user] == nil {
+		c.users[k.user] = make(map[key]struct{})
+	}
+
+	c.users[k.user][k] = struct{}{}
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// invalidate drops the entries of user.
+func (c *lru) invalidate(user uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	for k := range c.users[user] {
+		c.remove(c.entries[k])
+	}
+}
+
+// flush drops every entry.
+func (c *lru) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.order.Init()
+	c.entries = make(map[key]*list.Element)
+	c.users = make(map[uuid.UUID]map[key]struct{})
+}
+
+func (c *lru) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *lru) remove(elem *list.Element) {
+	e := c.order.Remove(elem).(*entry)
+	delete(c.entries, e.key)
+
+	delete(c.users[e.key.user], e.key)
+
+	if len(c.users[e.key.user]) == 0 {
+		delete(c.users, e.key.user)
+	}
+}
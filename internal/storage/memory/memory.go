@@ -0,0 +1,127 @@
+// Package memory keeps subscriptions in process memory. It follows the semantics of the Postgres storage,
+// so the service and its handlers can run without a database, as in frontend development and tests.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+type Storage struct {
+	mu            sync.RWMutex
+	lastID        int
+	subscriptions map[int]models.Subscription
+}
+
+func New() *Storage {
+	return &Storage{subscriptions: make(map[int]models.Subscription)}
+}
+
+// Close is a no-op, it lets Storage stand in wherever the Postgres storage is closed.
+func (store *Storage) Close() {}
+
+func (store *Storage) Ping(context.Context) error {
+	return nil
+}
+
+// GetMigrationVersion reports 0, memory has no schema to migrate.
+func (store *Storage) GetMigrationVersion(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (store *Storage) GetSubscriptionsByUserID(_ context.Context, id uuid.UUID) (subs []models.Subscription, err error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	for _, sub := range store.subscriptions {
+		if sub.UserID == id {
+			subs = append(subs, sub)
+		}
+	}
+
+	slices.SortFunc(subs, func(a, b models.Subscription) int {
+		if c := a.StartDate.Compare(b.StartDate); c != 0 {
+			return c
+		}
+
+		return a.ID - b.ID
+	})
+
+	return subs, nil
+}
+
+func (store *Storage) GetSubscription(_ context.Context, id int) (models.Subscription, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	sub, ok := store.subscriptions[id]
+	if !ok {
+		return sub, models.ErrNotFound
+	}
+
+	return sub, nil
+}
+
+func (store *Storage) PostSubscription(_ context.Context, sub models.Subscription) (int, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	sub.ID = 0
+	if store.conflicts(sub) {
+		return 0, models.ErrUnique
+	}
+
+	store.lastID++
+	sub.ID = store.lastID
+	store.subscriptions[sub.ID] = sub
+
+	return sub.ID, nil
+}
+
+func (store *Storage) DeleteSubscription(_ context.Context, id int) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, ok := store.subscriptions[id]; !ok {
+		return models.ErrNotFound
+	}
+
+	delete(store.subscriptions, id)
+
+	return nil
+}
+
+func (store *Storage) UpdateSubscription(_ context.Context, sub models.Subscription) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, ok := store.subscriptions[sub.ID]; !ok {
+		return models.ErrNotFound
+	}
+
+	if store.conflicts(sub) {
+		return fmt.Errorf("error updating DB %w", models.ErrUnique)
+	}
+
+	store.subscriptions[sub.ID] = sub
+
+	return nil
+}
+
+// conflicts reports whether another subscription has the same user, start date, price and service, the
+// unique key of the subscription table. The caller holds the lock.
+func (store *Storage) conflicts(row models.Subscription) bool {
+	for id, sub := range store.subscriptions {
+		if id != row.ID && sub.UserID == row.UserID && sub.StartDate.Equal(row.StartDate) &&
+			sub.Price == row.Price && sub.ServiceName == row.ServiceName {
+			return true
+		}
+	}
+
+	return false
+}
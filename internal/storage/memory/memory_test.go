@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage/memory"
+	"github.com/Ostmind/subscriptionservice/internal/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, memory.New())
+}
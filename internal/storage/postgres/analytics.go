@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+// analyticsColumns maps the public group-by dimensions to columns of the charges CTE.
+var analyticsColumns = map[string]string{
+	models.GroupByMonth:    "month",
+	models.GroupByService:  "service_name",
+	models.GroupByCategory: "category",
+	models.GroupByCurrency: "currency",
+}
+
+// GetSpendAnalytics expands the subscriptions of the user, and their parts of the shared ones, into their
+// monthly charges (see memberChargesCTE) and aggregates the charges by the requested dimensions.
+func (store *Storage) GetSpendAnalytics(ctx context.Context, filter models.SpendAnalyticsFilter) (res models.SpendAnalyticsDB, err error) {
+	sqlStatement, err := buildAnalyticsQuery(filter.GroupBy)
+	if err != nil {
+		return res, err
+	}
+
+	rows, err := store.reader(filter.UserID).Query(ctx, sqlStatement, filter.StartDate, filter.EndDate,
+		[]uuid.UUID{filter.UserID})
+	if err != nil {
+		return res, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var group models.SpendAnalyticsGroupDB
+
+		dest := make([]any, 0, len(filter.GroupBy)+6)
+
+		for _, dimension := range filter.GroupBy {
+			switch dimension {
+			case models.GroupByMonth:
+				dest = append(dest, &group.Month)
+			case models.GroupByService:
+				dest = append(dest, &group.ServiceName)
+			case models.GroupByCategory:
+				dest = append(dest, &group.Category)
+			case models.GroupByCurrency:
+				dest = append(dest, &group.Currency)
+			}
+		}
+
+		dest = append(dest, &group.Total, &group.Average, &group.Charges, &group.Delta, &res.Total, &res.MonthlyAverage)
+
+		if err := rows.Scan(dest...); err != nil {
+			return res, fmt.Errorf("scan Spend Analytics: %w", err)
+		}
+
+		res.Groups = append(res.Groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return res, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return res, nil
+}
+
+// GetMonthlySpendByUserIDs sums the monthly charges of several users, see memberChargesCTE, with a single query.
+// Every month of the period is returned, Delta is the change from the previous month of the user.
+func (store *Storage) GetMonthlySpendByUserIDs(ctx context.Context, ids []uuid.UUID, from, to time.Time) (
+	spend []models.MonthlySpendDB, err error,
+) {
+	sqlStatement := memberChargesCTE + `, grouped AS (
+	                     SELECT member, month, SUM(price) AS total
+	                     FROM member_charges
+	                     GROUP BY member, month
+	                 )
+	                 SELECT u.member, m.month, COALESCE(g.total, 0)::bigint,
+	                        COALESCE(g.total, 0) - LAG(COALESCE(g.total, 0)) OVER (PARTITION BY u.member ORDER BY m.month)
+	                 FROM months m
+	                 CROSS JOIN unnest($3::uuid[]) AS u(member)
+	                 LEFT JOIN grouped g ON g.member = u.member AND g.month = m.month
+	                 ORDER BY u.member, m.month;`
+
+	rows, err := store.reader(ids...).Query(ctx, sqlStatement, from, to, ids)
+	if err != nil {
+		return spend, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.MonthlySpendDB
+
+		if err := rows.Scan(&row.UserID, &row.Month, &row.Total, &row.Delta); err != nil {
+			return spend, fmt.Errorf("scan Monthly Spend: %w", err)
+		}
+
+		spend = append(spend, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return spend, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return spend, nil
+}
+
+func buildAnalyticsQuery(groupBy []string) (string, error) {
+	columns := make([]string, 0, len(groupBy))
+	partition := make([]string, 0, len(groupBy))
+	byMonth := false
+
+	for _, dimension := range groupBy {
+		column, ok := analyticsColumns[dimension]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", models.ErrInvalidGroupBy, dimension)
+		}
+
+		columns = append(columns, column)
+
+		if dimension == models.GroupByMonth {
+			byMonth = true
+		} else {
+			partition = append(partition, column)
+		}
+	}
+
+	if byMonth {
+		return buildMonthlyAnalyticsQuery(columns, partition), nil
+	}
+
+	selectList := ""
+	groupClause := ""
+
+	if len(columns) > 0 {
+		selectList = strings.Join(columns, ", ") + ", "
+		groupClause = " GROUP BY " + strings.Join(columns, ", ") + " ORDER BY " + strings.Join(columns, ", ")
+	}
+
+	return memberChargesCTE + `
+	         SELECT ` + selectList + `COALESCE(SUM(price), 0)::bigint, COALESCE(AVG(price), 0)::float8, COUNT(*)::bigint,
+	                NULL::bigint,
+	                COALESCE(SUM(SUM(price)) OVER (), 0)::bigint,
+	                COALESCE(SUM(SUM(price)) OVER (), 0)::float8 / (SELECT COUNT(*) FROM months)
+	         FROM member_charges` + groupClause, nil
+}
+
+// buildMonthlyAnalyticsQuery aggregates the charges by month and the partition columns over every month of the
+// period, a month without charges counts as zero. The delta is thus always taken from the previous calendar month,
+// not from the previous month with charges.
+func buildMonthlyAnalyticsQuery(columns, partition []string) string {
+	selectList := make([]string, 0, len(columns))
+	for _, column := range columns {
+		selectList = append(selectList, "grid."+column)
+	}
+
+	gridColumns := "m.month"
+	gridSource := "months m"
+	window := "ORDER BY grid.month"
+	join := "a.month = grid.month"
+
+	if len(partition) > 0 {
+		qualified := make([]string, 0, len(partition))
+		for _, column := range partition {
+			qualified = append(qualified, "grid."+column)
+			join += " AND a." + column + " IS NOT DISTINCT FROM grid." + column
+		}
+
+		gridColumns += ", p." + strings.Join(partition, ", p.")
+		gridSource += " CROSS JOIN (SELECT DISTINCT " + strings.Join(partition, ", ") + " FROM member_charges) p"
+		window = "PARTITION BY " + strings.Join(qualified, ", ") + " " + window
+	}
+
+	return memberChargesCTE + `, aggregated AS (
+	             SELECT ` + strings.Join(columns, ", ") + `, SUM(price) AS total, AVG(price) AS average, COUNT(*) AS charges
+	             FROM member_charges
+	             GROUP BY ` + strings.Join(columns, ", ") + `
+	         ), grid AS (
+	             SELECT ` + gridColumns + ` FROM ` + gridSource + `
+	         )
+	         SELECT ` + strings.Join(selectList, ", ") + `,
+	                COALESCE(a.total, 0)::bigint, COALESCE(a.average, 0)::float8, COALESCE(a.charges, 0)::bigint,
+	                COALESCE(a.total, 0) - LAG(COALESCE(a.total, 0)) OVER (` + window + `),
+	                COALESCE(SUM(a.total) OVER (), 0)::bigint,
+	                COALESCE(SUM(a.total) OVER (), 0)::float8 / (SELECT COUNT(*) FROM months)
+	         FROM grid
+	         LEFT JOIN aggregated a ON ` + join + `
+	         ORDER BY ` + strings.Join(selectList, ", ")
+}
+
+// memberChargesCTE adds to chargesCTE the member_charges CTE, the charges of the subscriptions of the users $3
+// and of the subscriptions shared with them, at the part of the price that falls on each of them, the member.
+// It splits the price as household.Split does: the members' parts are rounded down, fixed amounts are capped
+// at what is left of the price in the order of the members' ids, and the payer gets the rest.
+var memberChargesCTE = chargesCTE(`(s.user_id = ANY($3::uuid[]) OR s.id IN
+	             (SELECT subscription_id FROM public.subscription_share WHERE user_id = ANY($3::uuid[])))`) + `, member_charges AS (
+	             SELECT m.user_id AS member, ch.month, ch.service_name, ch.category, ch.currency,
+	                    (CASE
+	                         WHEN ss.subscription_id IS NULL THEN ch.price
+	                         WHEN ch.user_id = m.user_id THEN ch.price - COALESCE(parts.others, 0)
+	                         ELSE COALESCE(parts.mine, 0)
+	                     END)::integer AS price
+	             FROM charges ch
+	             JOIN unnest($3::uuid[]) AS m(user_id)
+	               ON ch.user_id = m.user_id
+	               OR EXISTS (SELECT 1 FROM public.subscription_share x
+	                          WHERE x.subscription_id = ch.subscription_id AND x.user_id = m.user_id)
+	             LEFT JOIN public.shared_subscription ss ON ss.subscription_id = ch.subscription_id
+	             LEFT JOIN LATERAL (
+	                 SELECT SUM(part) FILTER (WHERE p.user_id = m.user_id) AS mine, SUM(part) AS others
+	                 FROM (SELECT sh.user_id,
+	                              CASE ss.split
+	                                  WHEN 'equal' THEN ch.price / (COUNT(*) OVER () + 1)
+	                                  WHEN 'percentage' THEN ch.price * sh.value / 100
+	                                  ELSE GREATEST(LEAST(sh.value, ch.price - COALESCE(SUM(sh.value) OVER (
+	                                      ORDER BY sh.user_id ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0)), 0)
+	                              END AS part
+	                       FROM public.subscription_share sh
+	                       WHERE sh.subscription_id = ch.subscription_id) p
+	             ) parts ON TRUE
+	         )`
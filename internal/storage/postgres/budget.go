@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (store *Storage) PostBudget(ctx context.Context, budget models.BudgetJSON) error {
+	sqlStatement := `INSERT INTO budget
+    				 (user_id, scope, scope_value, amount)
+					 VALUES($1,$2,$3,$4);`
+
+	if _, err := store.DB.Exec(ctx, sqlStatement, budget.UserID, budget.Scope, budget.ScopeValue, budget.Amount); err != nil {
+		return fmt.Errorf("error adding to DB %w", translateError(err))
+	}
+
+	return nil
+}
+
+func (store *Storage) GetBudgetsByUserID(ctx context.Context, id uuid.UUID) (budgets []models.BudgetJSON, err error) {
+	sqlStatement := `SELECT id, user_id, scope, scope_value, amount FROM public.budget WHERE user_id = $1 ORDER BY id;`
+
+	rows, err := store.DB.Query(ctx, sqlStatement, id)
+	if err != nil {
+		return budgets, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b models.BudgetJSON
+
+		if err := rows.Scan(&b.ID, &b.UserID, &b.Scope, &b.ScopeValue, &b.Amount); err != nil {
+			return budgets, fmt.Errorf("scan Budget List: %w", err)
+		}
+
+		budgets = append(budgets, b)
+	}
+
+	if len(budgets) == 0 {
+		return budgets, models.ErrNotFound
+	}
+
+	return budgets, nil
+}
+
+// GetBudget returns the budget with the id, ErrNotFound when there is none.
+func (store *Storage) GetBudget(ctx context.Context, id int) (budget models.BudgetJSON, err error) {
+	sqlStatement := `SELECT id, user_id, scope, scope_value, amount FROM public.budget WHERE id = $1;`
+
+	err = store.DB.QueryRow(ctx, sqlStatement, id).
+		Scan(&budget.ID, &budget.UserID, &budget.Scope, &budget.ScopeValue, &budget.Amount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return budget, models.ErrNotFound
+	}
+
+	if err != nil {
+		return budget, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	return budget, nil
+}
+
+func (store *Storage) UpdateBudget(ctx context.Context, budget models.BudgetJSON, id int) error {
+	sqlStatement := `UPDATE public.budget SET
+                     user_id=$1,
+                     scope=$2,
+                     scope_value=$3,
+                     amount=$4,
+                     alerted_month=NULL
+                     WHERE id =$5;`
+
+	result, err := store.DB.Exec(ctx, sqlStatement, budget.UserID, budget.Scope, budget.ScopeValue, budget.Amount, id)
+	if err != nil {
+		return fmt.Errorf("error updating DB %w", translateError(err))
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (store *Storage) DeleteBudget(ctx context.Context, id int) error {
+	sqlStatement := `DELETE FROM public.budget WHERE id = $1;`
+
+	result, err := store.DB.Exec(ctx, sqlStatement, id)
+	if err != nil {
+		return fmt.Errorf("error deleting from DB %w", translateError(err))
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetBudgetUsage returns every budget with the cost of its scope in the given month.
+func (store *Storage) GetBudgetUsage(ctx context.Context, month time.Time) (usage []models.BudgetUsage, err error) {
+	sqlStatement := chargesCTE("TRUE") + `
+	                 SELECT b.id, b.user_id, b.scope, b.scope_value, b.amount, b.alerted_month,
+	                        COALESCE(SUM(c.price), 0)::bigint
+	                 FROM public.budget b
+	                 LEFT JOIN charges c
+	                   ON c.user_id = b.user_id
+	                  AND (b.scope = 'total'
+	                       OR (b.scope = 'category' AND c.category = b.scope_value)
+	                       OR (b.scope = 'service' AND c.service_name = b.scope_value))
+	                 GROUP BY b.id
+	                 ORDER BY b.id;`
+
+	rows, err := store.DB.Query(ctx, sqlStatement, month, month)
+	if err != nil {
+		return usage, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u models.BudgetUsage
+
+		if err := rows.Scan(&u.Budget.ID, &u.Budget.UserID, &u.Budget.Scope, &u.Budget.ScopeValue, &u.Budget.Amount,
+			&u.AlertedMonth, &u.Projected); err != nil {
+			return usage, fmt.Errorf("scan Budget Usage: %w", err)
+		}
+
+		usage = append(usage, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return usage, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return usage, nil
+}
+
+// SetBudgetAlertedMonth records the month the budget was last reported as overspent, nil re-arms the alert.
+func (store *Storage) SetBudgetAlertedMonth(ctx context.Context, id int, month *time.Time) error {
+	sqlStatement := `UPDATE public.budget SET alerted_month = $1 WHERE id = $2;`
+
+	if _, err := store.DB.Exec(ctx, sqlStatement, month, id); err != nil {
+		return fmt.Errorf("error updating DB %w", translateError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+// GetSubscriptionsByUserIDs loads the subscriptions of several users with a single query.
+func (store *Storage) GetSubscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) ([]models.Subscription, error) {
+	return store.getSubscriptionsByUserIDs(ctx, store.reader(ids...), ids)
+}
+
+// GetPrimarySubscriptionsByUserIDs is GetSubscriptionsByUserIDs reading from the primary, whatever the replicas.
+func (store *Storage) GetPrimarySubscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) ([]models.Subscription, error) {
+	return store.getSubscriptionsByUserIDs(ctx, store.DB, ids)
+}
+
+func (store *Storage) getSubscriptionsByUserIDs(ctx context.Context, reader querier, ids []uuid.UUID) (
+	subs []models.Subscription, err error,
+) {
+	sqlStatement := `SELECT ` + subscriptionColumns + `
+	                 FROM public.subscription
+	                 WHERE user_id = ANY($1)
+	                 ORDER BY user_id, start_date, id;`
+
+	rows, err := reader.Query(ctx, sqlStatement, ids)
+	if err != nil {
+		return subs, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return subs, err
+		}
+
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return subs, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return subs, nil
+}
+
+func (store *Storage) GetCatalogServices(ctx context.Context) ([]models.CatalogService, error) {
+	return store.queryCatalog(ctx, `SELECT service_name, category, price FROM public.service_catalog ORDER BY service_name;`)
+}
+
+// GetCatalogServicesByNames loads the catalog entries of several services with a single query. A service
+// matches its catalog entry whatever the case, as in the analytics and recommendation joins.
+func (store *Storage) GetCatalogServicesByNames(ctx context.Context, names []string) ([]models.CatalogService, error) {
+	return store.queryCatalog(ctx, `SELECT service_name, category, price FROM public.service_catalog
+	                                WHERE lower(service_name) IN (SELECT lower(name) FROM unnest($1::text[]) name)
+	                                ORDER BY service_name;`, names)
+}
+
+func (store *Storage) queryCatalog(ctx context.Context, sqlStatement string, args ...any) (services []models.CatalogService, err error) {
+	rows, err := store.DB.Query(ctx, sqlStatement, args...)
+	if err != nil {
+		return services, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var service models.CatalogService
+
+		if err := rows.Scan(&service.ServiceName, &service.Category, &service.Price); err != nil {
+			return services, fmt.Errorf("scan Catalog Service: %w", err)
+		}
+
+		services = append(services, service)
+	}
+
+	if err := rows.Err(); err != nil {
+		return services, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return services, nil
+}
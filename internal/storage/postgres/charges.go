@@ -0,0 +1,54 @@
+package postgres
+
+// chargesCTE is the period-cost logic shared by the analytics and budget queries. It declares the months CTE,
+// one row per month between $1 and $2, and the charges CTE, one row per subscription matching subscriptionCondition
+// and charged in that month: the subscription has started, has not ended, is out of its trial and the month falls
+// on its billing period. The price is the latest price change in effect times the seats held in the month, see
+// seatsInMonth.
+func chargesCTE(subscriptionCondition string) string {
+	return `WITH months AS (
+	             SELECT generate_series(date_trunc('month', $1::date),
+	                                    date_trunc('month', $2::date),
+	                                    interval '1 month')::date AS month
+	         ), charges AS (
+	             SELECT m.month, s.id AS subscription_id, s.user_id, s.service_name,
+	                    COALESCE(c.category, 'other') AS category, s.currency, s.organization_id, s.department,
+	                    ` + seatsInMonth + ` AS seats,
+	                    COALESCE((SELECT pc.price
+	                              FROM public.subscription_price_change pc
+	                              WHERE pc.subscription_id = s.id
+	                                AND date_trunc('month', pc.effective_date) <= m.month
+	                              ORDER BY pc.effective_date DESC
+	                              LIMIT 1), s.price) * ` + seatsInMonth + ` AS price
+	             FROM months m
+	             JOIN public.subscription s
+	               ON ` + subscriptionCondition + `
+	              AND date_trunc('month', s.start_date) <= m.month
+	              AND (s.end_date IS NULL OR date_trunc('month', s.end_date) >= m.month)
+	              AND (s.trial_end_date IS NULL OR date_trunc('month', s.trial_end_date) <= m.month)
+	              AND ((EXTRACT(YEAR FROM m.month) - EXTRACT(YEAR FROM s.start_date)) * 12
+	                   + EXTRACT(MONTH FROM m.month) - EXTRACT(MONTH FROM s.start_date))::int
+	                  % CASE s.billing_period
+	                        WHEN 'quarterly' THEN 3
+	                        WHEN 'semiannual' THEN 6
+	                        WHEN 'yearly' THEN 12
+	                        ELSE 1
+	                    END = 0
+	             LEFT JOIN public.service_catalog c ON lower(c.service_name) = lower(s.service_name)
+	         )`
+}
+
+// seatsInMonth is the number of seats of the subscription s in the month m.month: those set by the latest seat
+// change made by the end of the month, or before the first later change, or the current ones without changes.
+const seatsInMonth = `COALESCE((SELECT sc.seats
+	                              FROM public.subscription_seat_change sc
+	                              WHERE sc.subscription_id = s.id
+	                                AND date_trunc('month', sc.changed_at) <= m.month
+	                              ORDER BY sc.changed_at DESC, sc.id DESC
+	                              LIMIT 1),
+	                             (SELECT sc.previous_seats
+	                              FROM public.subscription_seat_change sc
+	                              WHERE sc.subscription_id = s.id
+	                                AND date_trunc('month', sc.changed_at) > m.month
+	                              ORDER BY sc.changed_at, sc.id
+	                              LIMIT 1), s.seats)`
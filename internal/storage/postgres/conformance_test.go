@@ -0,0 +1,36 @@
+package postgres_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage/postgres"
+	"github.com/Ostmind/subscriptionservice/internal/storage/storagetest"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+)
+
+// testDSNEnv names a migrated database for the conformance suite, such as the docker-compose one:
+// SUBSCRIPTION_TEST_DSN="host=localhost user=selectel password=selectel dbname=selectel sslmode=disable".
+const testDSNEnv = "SUBSCRIPTION_TEST_DSN"
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skip(testDSNEnv + " is not set")
+	}
+
+	store, err := postgres.New(context.Background(), config.DatabaseConfig{DSN: dsn},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	t.Cleanup(store.Close)
+
+	storagetest.Run(t, store)
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE codes of the errors the API reports to the client, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	uniqueViolation      = "23505"
+	foreignKeyViolation  = "23503"
+	checkViolation       = "23514"
+	serializationFailure = "40001"
+	queryCanceled        = "57014"
+)
+
+var errorCodes = map[string]error{
+	uniqueViolation:      models.ErrUnique,
+	foreignKeyViolation:  models.ErrForeignKey,
+	checkViolation:       models.ErrCheckViolation,
+	serializationFailure: models.ErrUnavailable,
+	queryCanceled:        models.ErrUnavailable,
+}
+
+// translateError turns the PostgreSQL errors the client can act on into a *models.DBError wrapping the
+// matching models error, along with the violated constraint. Other errors are returned unchanged.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	kind, ok := errorCodes[pgErr.Code]
+	if !ok {
+		return err
+	}
+
+	return &models.DBError{
+		Kind:       kind,
+		Table:      pgErr.TableName,
+		Column:     pgErr.ColumnName,
+		Constraint: pgErr.ConstraintName,
+		Cause:      err,
+	}
+}
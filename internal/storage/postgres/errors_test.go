@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestTranslateError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		wantKind error
+	}{
+		{name: "unique", err: &pgconn.PgError{Code: uniqueViolation}, wantKind: models.ErrUnique},
+		{name: "foreign key", err: &pgconn.PgError{Code: foreignKeyViolation}, wantKind: models.ErrForeignKey},
+		{name: "check", err: &pgconn.PgError{Code: checkViolation}, wantKind: models.ErrCheckViolation},
+		{name: "serialization", err: &pgconn.PgError{Code: serializationFailure}, wantKind: models.ErrUnavailable},
+		{name: "canceled", err: &pgconn.PgError{Code: queryCanceled}, wantKind: models.ErrUnavailable},
+		{name: "wrapped", err: fmt.Errorf("exec: %w", &pgconn.PgError{Code: uniqueViolation}), wantKind: models.ErrUnique},
+		{name: "other code", err: &pgconn.PgError{Code: "42P01"}},
+		{name: "connection", err: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := translateError(tt.err)
+
+			if tt.wantKind == nil {
+				if got != tt.err {
+					t.Errorf("expected the error unchanged, got %v", got)
+				}
+
+				return
+			}
+
+			if !errors.Is(got, tt.wantKind) || !errors.Is(got, tt.err) {
+				t.Errorf("expected %v wrapping the cause, got %v", tt.wantKind, got)
+			}
+		})
+	}
+}
+
+func TestTranslateErrorDetails(t *testing.T) {
+	t.Parallel()
+
+	err := translateError(&pgconn.PgError{
+		Code:           foreignKeyViolation,
+		TableName:      "subscription_price_change",
+		ColumnName:     "subscription_id",
+		ConstraintName: "subscription_price_change_subscription_id_fkey",
+	})
+
+	var dbErr *models.DBError
+	if !errors.As(err, &dbErr) {
+		t.Fatalf("expected a DBError, got %v", err)
+	}
+
+	if dbErr.Table != "subscription_price_change" || dbErr.Column != "subscription_id" ||
+		dbErr.Constraint != "subscription_price_change_subscription_id_fkey" {
+		t.Errorf("unexpected details %+v", dbErr)
+	}
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+// GetForecastSubscriptionsByUserID returns the subscriptions of the user that are still active on or after from,
+// together with their scheduled price changes ordered by effective date. The prices are those of all the seats
+// the subscription holds now.
+func (store *Storage) GetForecastSubscriptionsByUserID(ctx context.Context, id uuid.UUID, from time.Time) (subs []models.ForecastSubscription, err error) {
+	sqlStatement := `SELECT s.id, s.service_name, s.price * s.seats, s.currency, s.billing_period,
+	                        s.start_date, s.end_date, s.trial_end_date,
+	                        pc.effective_date, pc.price * s.seats
+	                 FROM public.subscription s
+	                 LEFT JOIN public.subscription_price_change pc ON pc.subscription_id = s.id
+	                 WHERE s.user_id = $1
+	                   AND (s.end_date IS NULL OR s.end_date >= date_trunc('month', $2::date))
+	                 ORDER BY s.id, pc.effective_date;`
+
+	rows, err := store.reader(id).Query(ctx, sqlStatement, id, from)
+	if err != nil {
+		return subs, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			sub         models.ForecastSubscription
+			changeDate  *time.Time
+			changePrice *int
+		)
+
+		if err := rows.Scan(&sub.ID, &sub.ServiceName, &sub.Price, &sub.Currency, &sub.BillingPeriod,
+			&sub.StartDate, &sub.EndDate, &sub.TrialEndDate, &changeDate, &changePrice); err != nil {
+			return subs, fmt.Errorf("scan Forecast Subscription: %w", err)
+		}
+
+		if len(subs) == 0 || subs[len(subs)-1].ID != sub.ID {
+			subs = append(subs, sub)
+		}
+
+		if changeDate != nil && changePrice != nil {
+			last := &subs[len(subs)-1]
+			last.PriceChanges = append(last.PriceChanges, models.PriceChange{EffectiveDate: *changeDate, Price: *changePrice})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return subs, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return subs, nil
+}
+
+func (store *Storage) PostPriceChange(ctx context.Context, subscriptionID int, change models.PriceChange) error {
+	sqlStatement := `WITH change AS (
+					 INSERT INTO subscription_price_change
+    				 (subscription_id, effective_date, price)
+					 VALUES($1,$2,$3)
+					 ON CONFLICT (subscription_id, effective_date) DO UPDATE SET price = EXCLUDED.price
+					 RETURNING subscription_id)
+					 SELECT s.user_id FROM change JOIN public.subscription s ON s.id = change.subscription_id;`
+
+	var userID uuid.UUID
+
+	err := store.DB.QueryRow(ctx, sqlStatement, subscriptionID, change.EffectiveDate, change.Price).Scan(&userID)
+	if err != nil {
+		return fmt.Errorf("error adding to DB %w", translateError(err))
+	}
+
+	// The forecasts of the owner read the price changes from the replicas.
+	store.sticky.mark(userID)
+
+	return nil
+}
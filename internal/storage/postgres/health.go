@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+func (store *Storage) Ping(ctx context.Context) error {
+	if err := store.DB.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping DB %w", err)
+	}
+
+	return nil
+}
+
+// GetMigrationVersion returns the highest goose migration whose latest record is applied, the same version
+// goose reports, without creating the version table when it is missing.
+func (store *Storage) GetMigrationVersion(ctx context.Context) (version int64, err error) {
+	sqlStatement := `SELECT COALESCE(MAX(version_id), 0)
+	                 FROM (SELECT DISTINCT ON (version_id) version_id, is_applied
+	                       FROM public.goose_db_version
+	                       ORDER BY version_id, id DESC) v
+	                 WHERE is_applied;`
+
+	if err := store.DB.QueryRow(ctx, sqlStatement).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	return version, nil
+}
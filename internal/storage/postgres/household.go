@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (store *Storage) PostHousehold(ctx context.Context, household models.HouseholdJSON) (id int, err error) {
+	err = pgx.BeginFunc(ctx, store.DB, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, `INSERT INTO household (name, owner_id) VALUES($1,$2) RETURNING id;`,
+			household.Name, household.OwnerID).Scan(&id); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, `INSERT INTO household_member (household_id, user_id)
+		                        SELECT $1, unnest($2::uuid[])
+		                        ON CONFLICT DO NOTHING;`, id, household.Members)
+
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error adding to DB %w", translateError(err))
+	}
+
+	return id, nil
+}
+
+func (store *Storage) GetHousehold(ctx context.Context, id int) (household models.HouseholdJSON, err error) {
+	err = store.DB.QueryRow(ctx, `SELECT id, name, owner_id FROM public.household WHERE id = $1;`, id).
+		Scan(&household.ID, &household.Name, &household.OwnerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return household, models.ErrNotFound
+	}
+
+	if err != nil {
+		return household, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	rows, err := store.DB.Query(ctx, `SELECT user_id FROM public.household_member
+	                                  WHERE household_id = $1
+	                                  ORDER BY created_at, user_id;`, id)
+	if err != nil {
+		return household, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	household.Members, err = pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return household, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return household, nil
+}
+
+func (store *Storage) AddHouseholdMember(ctx context.Context, member models.HouseholdMemberJSON) error {
+	sqlStatement := `INSERT INTO household_member (household_id, user_id) VALUES($1,$2);`
+
+	if _, err := store.DB.Exec(ctx, sqlStatement, member.HouseholdID, member.UserID); err != nil {
+		return fmt.Errorf("error adding to DB %w", translateError(err))
+	}
+
+	return nil
+}
+
+// DeleteHouseholdMember removes the member along with their shares and the sharing of the subscriptions
+// they pay for.
+func (store *Storage) DeleteHouseholdMember(ctx context.Context, member models.HouseholdMemberJSON) error {
+	sqlStatement := `DELETE FROM public.household_member WHERE household_id = $1 AND user_id = $2;`
+
+	result, err := store.DB.Exec(ctx, sqlStatement, member.HouseholdID, member.UserID)
+	if err != nil {
+		return fmt.Errorf("error deleting from DB %w", translateError(err))
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// PutSharedSubscription shares the subscription by the rule of shared, replacing the previous rule. The user
+// of the subscription pays for it and must be a member of the household, as must the users of the shares.
+func (store *Storage) PutSharedSubscription(ctx context.Context, shared models.SharedSubscriptionJSON) error {
+	var payer uuid.UUID
+
+	err := pgx.BeginFunc(ctx, store.DB, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `INSERT INTO shared_subscription (subscription_id, household_id, payer_id, split)
+		                         SELECT id, $2, user_id, $3 FROM public.subscription WHERE id = $1
+		                         ON CONFLICT (subscription_id) DO UPDATE SET
+		                             household_id = EXCLUDED.household_id,
+		                             payer_id = EXCLUDED.payer_id,
+		                             split = EXCLUDED.split
+		                         RETURNING payer_id;`,
+			shared.SubscriptionID, shared.HouseholdID, shared.Split).Scan(&payer)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM public.subscription_share WHERE subscription_id = $1;`,
+			shared.SubscriptionID); err != nil {
+			return err
+		}
+
+		for _, share := range shared.Shares {
+			if _, err := tx.Exec(ctx, `INSERT INTO subscription_share (subscription_id, household_id, user_id, value)
+			                           VALUES($1,$2,$3,$4);`,
+				shared.SubscriptionID, shared.HouseholdID, share.UserID, share.Value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("error adding to DB %w", translateError(err))
+	}
+
+	// The costs of everyone sharing it change.
+	store.sticky.mark(payer)
+
+	for _, share := range shared.Shares {
+		store.sticky.mark(share.UserID)
+	}
+
+	return nil
+}
+
+func (store *Storage) DeleteSharedSubscription(ctx context.Context, subscriptionID int) error {
+	sqlStatement := `DELETE FROM public.shared_subscription WHERE subscription_id = $1 RETURNING payer_id;`
+
+	var payer uuid.UUID
+
+	err := store.DB.QueryRow(ctx, sqlStatement, subscriptionID).Scan(&payer)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("error deleting from DB %w", translateError(err))
+	}
+
+	store.sticky.mark(payer)
+
+	return nil
+}
+
+// sharedSubscriptionsQuery selects the shared subscriptions matching condition, one row per share.
+func sharedSubscriptionsQuery(condition string) string {
+	return `SELECT s.id, s.user_id, s.service_name, s.price, s.currency, s.billing_period,
+	               s.start_date, s.end_date, s.trial_end_date, s.seats,
+	               ss.household_id, ss.split, sh.user_id, sh.value
+	        FROM public.shared_subscription ss
+	        JOIN public.subscription s ON s.id = ss.subscription_id
+	        LEFT JOIN public.subscription_share sh ON sh.subscription_id = ss.subscription_id
+	        WHERE ` + condition + `
+	        ORDER BY s.start_date, s.id, sh.user_id;`
+}
+
+// GetSharedSubscriptionsByUserID returns the subscriptions the user pays for and shares or has a share in.
+func (store *Storage) GetSharedSubscriptionsByUserID(ctx context.Context, id uuid.UUID) ([]models.SharedSubscription, error) {
+	sqlStatement := sharedSubscriptionsQuery(`ss.payer_id = $1 OR ss.subscription_id IN
+	               (SELECT subscription_id FROM public.subscription_share WHERE user_id = $1)`)
+
+	rows, err := store.reader(id).Query(ctx, sqlStatement, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	return scanSharedSubscriptions(rows)
+}
+
+// GetSharedSubscriptionsByUserIDs is GetSharedSubscriptionsByUserID for several users with a single query.
+func (store *Storage) GetSharedSubscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) ([]models.SharedSubscription, error) {
+	sqlStatement := sharedSubscriptionsQuery(`ss.payer_id = ANY($1) OR ss.subscription_id IN
+	               (SELECT subscription_id FROM public.subscription_share WHERE user_id = ANY($1))`)
+
+	rows, err := store.reader(ids...).Query(ctx, sqlStatement, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	return scanSharedSubscriptions(rows)
+}
+
+func (store *Storage) GetSharedSubscriptionsByHouseholdID(ctx context.Context, id int) ([]models.SharedSubscription, error) {
+	rows, err := store.DB.Query(ctx, sharedSubscriptionsQuery("ss.household_id = $1"), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	return scanSharedSubscriptions(rows)
+}
+
+func scanSharedSubscriptions(rows pgx.Rows) (subs []models.SharedSubscription, err error) {
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			shared     models.SharedSubscription
+			shareUser  *uuid.UUID
+			shareValue *int
+		)
+
+		sub := &shared.Subscription
+
+		if err := rows.Scan(&sub.ID, &sub.UserID, &sub.ServiceName, &sub.Price, &sub.Currency, &sub.BillingPeriod,
+			&sub.StartDate, &sub.EndDate, &sub.TrialEndDate, &sub.Seats,
+			&shared.HouseholdID, &shared.Split, &shareUser, &shareValue); err != nil {
+			return subs, fmt.Errorf("scan Shared Subscription: %w", err)
+		}
+
+		if len(subs) == 0 || subs[len(subs)-1].Subscription.ID != sub.ID {
+			subs = append(subs, shared)
+		}
+
+		if shareUser != nil && shareValue != nil {
+			last := &subs[len(subs)-1]
+			last.Shares = append(last.Shares, models.ShareJSON{UserID: *shareUser, Value: *shareValue})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return subs, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return subs, nil
+}
+
+// GetHouseholdCharges returns the monthly charges of the subscriptions shared in the household from from to
+// to, see chargesCTE.
+func (store *Storage) GetHouseholdCharges(ctx context.Context, id int, from, to time.Time) (charges []models.SharedCharge, err error) {
+	sqlStatement := chargesCTE(`s.id IN (SELECT subscription_id FROM public.shared_subscription WHERE household_id = $3)`) + `
+	                 SELECT subscription_id, month, price, currency
+	                 FROM charges
+	                 ORDER BY month, subscription_id;`
+
+	rows, err := store.DB.Query(ctx, sqlStatement, from, to, id)
+	if err != nil {
+		return charges, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var charge models.SharedCharge
+
+		if err := rows.Scan(&charge.SubscriptionID, &charge.Month, &charge.Price, &charge.Currency); err != nil {
+			return charges, fmt.Errorf("scan Shared Charge: %w", err)
+		}
+
+		charges = append(charges, charge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return charges, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return charges, nil
+}
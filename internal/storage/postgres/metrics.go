@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+)
+
+// GetServiceSpend returns the active subscriptions in month grouped by service and currency. Services missing from
+// the service catalog are reported as "other", so the result stays bounded whatever names the users type in.
+// Subscriptions still in their trial are counted but do not add to the monthly spend.
+func (store *Storage) GetServiceSpend(ctx context.Context, month time.Time) (spend []models.ServiceSpend, err error) {
+	sqlStatement := `SELECT COALESCE(c.service_name, 'other') AS service_name, s.currency, COUNT(*),
+	                        COALESCE(SUM(CASE
+	                            WHEN s.trial_end_date IS NOT NULL
+	                             AND date_trunc('month', s.trial_end_date) > date_trunc('month', $1::date) THEN 0
+	                            ELSE COALESCE((SELECT pc.price
+	                                           FROM public.subscription_price_change pc
+	                                           WHERE pc.subscription_id = s.id
+	                                             AND date_trunc('month', pc.effective_date) <= date_trunc('month', $1::date)
+	                                           ORDER BY pc.effective_date DESC
+	                                           LIMIT 1), s.price)::numeric * s.seats
+	                                 / CASE s.billing_period
+	                                       WHEN 'quarterly' THEN 3
+	                                       WHEN 'semiannual' THEN 6
+	                                       WHEN 'yearly' THEN 12
+	                                       ELSE 1
+	                                   END
+	                        END), 0)::float8
+	                 FROM public.subscription s
+	                 LEFT JOIN public.service_catalog c ON lower(c.service_name) = lower(s.service_name)
+	                 WHERE date_trunc('month', s.start_date) <= date_trunc('month', $1::date)
+	                   AND (s.end_date IS NULL OR date_trunc('month', s.end_date) >= date_trunc('month', $1::date))
+	                 GROUP BY 1, 2
+	                 ORDER BY 1, 2;`
+
+	rows, err := store.reader().Query(ctx, sqlStatement, month)
+	if err != nil {
+		return spend, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s models.ServiceSpend
+
+		if err := rows.Scan(&s.ServiceName, &s.Currency, &s.Subscriptions, &s.MonthlySpend); err != nil {
+			return spend, fmt.Errorf("scan Service Spend: %w", err)
+		}
+
+		spend = append(spend, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return spend, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return spend, nil
+}
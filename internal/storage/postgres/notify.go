@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Notify sends payload to the listeners of channel, in every replica of the service.
+func (store *Storage) Notify(ctx context.Context, channel, payload string) error {
+	if _, err := store.DB.Exec(ctx, `SELECT pg_notify($1, $2);`, channel, payload); err != nil {
+		return fmt.Errorf("error notifying DB %w", translateError(err))
+	}
+
+	return nil
+}
+
+// Listen calls handle with the payload of every notification on channel until ctx is done, when it returns nil,
+// or the connection fails. The notifications sent while nobody listens are lost.
+func (store *Storage) Listen(ctx context.Context, channel string, handle func(payload string)) error {
+	pooled, err := store.DB.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error listening to DB %w", translateError(err))
+	}
+
+	// The connection stays subscribed to the channel, it is closed rather than returned to the pool.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()+";"); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		return fmt.Errorf("error listening to DB %w", translateError(err))
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return fmt.Errorf("error listening to DB %w", translateError(err))
+		}
+
+		handle(notification.Payload)
+	}
+}
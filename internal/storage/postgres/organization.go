@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (store *Storage) PostOrganization(ctx context.Context, org models.OrganizationJSON) (id int, err error) {
+	err = pgx.BeginFunc(ctx, store.DB, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, `INSERT INTO organization (name) VALUES($1) RETURNING id;`, org.Name).
+			Scan(&id); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, `INSERT INTO organization_member (organization_id, user_id, role) VALUES($1,$2,$3);`,
+			id, org.AdminID, models.RoleAdmin)
+
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error adding to DB %w", translateError(err))
+	}
+
+	return id, nil
+}
+
+func (store *Storage) GetOrganization(ctx context.Context, id int) (org models.OrganizationJSON, err error) {
+	err = store.DB.QueryRow(ctx, `SELECT id, name FROM public.organization WHERE id = $1;`, id).
+		Scan(&org.ID, &org.Name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return org, models.ErrNotFound
+	}
+
+	if err != nil {
+		return org, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	rows, err := store.DB.Query(ctx, `SELECT organization_id, user_id, role, department
+	                                  FROM public.organization_member
+	                                  WHERE organization_id = $1
+	                                  ORDER BY created_at, user_id;`, id)
+	if err != nil {
+		return org, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	org.Members, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (member models.OrganizationMemberJSON, err error) {
+		err = row.Scan(&member.OrganizationID, &member.UserID, &member.Role, &member.Department)
+
+		return member, err
+	})
+	if err != nil {
+		return org, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	for _, member := range org.Members {
+		if member.Role == models.RoleAdmin {
+			org.AdminID = member.UserID
+
+			break
+		}
+	}
+
+	return org, nil
+}
+
+// PutOrganizationMember adds the member or changes their role and department.
+func (store *Storage) PutOrganizationMember(ctx context.Context, member models.OrganizationMemberJSON) error {
+	sqlStatement := `INSERT INTO organization_member (organization_id, user_id, role, department)
+	                 VALUES($1,$2,$3,$4)
+	                 ON CONFLICT (organization_id, user_id) DO UPDATE SET
+	                     role = EXCLUDED.role,
+	                     department = EXCLUDED.department;`
+
+	if _, err := store.DB.Exec(ctx, sqlStatement, member.OrganizationID, member.UserID, member.Role,
+		member.Department); err != nil {
+		return fmt.Errorf("error adding to DB %w", translateError(err))
+	}
+
+	// The department of the member attributes their subscriptions.
+	store.sticky.mark(member.UserID)
+
+	return nil
+}
+
+func (store *Storage) DeleteOrganizationMember(ctx context.Context, member models.OrganizationMemberJSON) error {
+	sqlStatement := `DELETE FROM public.organization_member WHERE organization_id = $1 AND user_id = $2;`
+
+	result, err := store.DB.Exec(ctx, sqlStatement, member.OrganizationID, member.UserID)
+	if err != nil {
+		return fmt.Errorf("error deleting from DB %w", translateError(err))
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	store.sticky.mark(member.UserID)
+
+	return nil
+}
+
+// GetOrganizationRole returns the role of the user in the organization, ErrNotFound when they aren't a member.
+func (store *Storage) GetOrganizationRole(ctx context.Context, organizationID int, userID uuid.UUID) (role string, err error) {
+	sqlStatement := `SELECT role FROM public.organization_member WHERE organization_id = $1 AND user_id = $2;`
+
+	err = store.DB.QueryRow(ctx, sqlStatement, organizationID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return role, models.ErrNotFound
+	}
+
+	if err != nil {
+		return role, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	return role, nil
+}
+
+// GetSeatChanges returns the seat history of the subscription, oldest first.
+func (store *Storage) GetSeatChanges(ctx context.Context, subscriptionID int) ([]models.SeatChangeJSON, error) {
+	sqlStatement := `SELECT subscription_id, previous_seats, seats, changed_at
+	                 FROM public.subscription_seat_change
+	                 WHERE subscription_id = $1
+	                 ORDER BY changed_at, id;`
+
+	rows, err := store.DB.Query(ctx, sqlStatement, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	changes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (change models.SeatChangeJSON, err error) {
+		err = row.Scan(&change.SubscriptionID, &change.PreviousSeats, &change.Seats, &change.ChangedAt)
+
+		return change, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return changes, nil
+}
+
+// GetOrganizationSpend sums the monthly charges of the organization subscriptions by department, service and
+// currency, see chargesCTE. A subscription is attributed to its department, or to the department of its user
+// when it has none. Seats is the largest number of seats charged in a month.
+func (store *Storage) GetOrganizationSpend(ctx context.Context, filter models.OrganizationSpendFilter) (spend []models.OrganizationSpendDB, err error) {
+	sqlStatement := chargesCTE(`s.organization_id = $3 AND ($4::uuid IS NULL OR s.user_id = $4)`) + `
+	                 SELECT COALESCE(NULLIF(ch.department, ''), NULLIF(om.department, ''), '` + models.DepartmentUnassigned + `'),
+	                        ch.service_name, ch.currency, MAX(ch.seats)::integer, SUM(ch.price)::bigint
+	                 FROM charges ch
+	                 LEFT JOIN public.organization_member om
+	                   ON om.organization_id = ch.organization_id AND om.user_id = ch.user_id
+	                 GROUP BY 1, 2, 3
+	                 ORDER BY 1, 2, 3;`
+
+	// The spend of the whole organization reads the primary, its admins expect the changes of every member in it.
+	var reader querier = store.DB
+	if filter.UserID != nil {
+		reader = store.reader(*filter.UserID)
+	}
+
+	rows, err := reader.Query(ctx, sqlStatement, filter.StartDate, filter.EndDate, filter.OrganizationID, filter.UserID)
+	if err != nil {
+		return spend, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.OrganizationSpendDB
+
+		if err := rows.Scan(&row.Department, &row.ServiceName, &row.Currency, &row.Seats, &row.Total); err != nil {
+			return spend, fmt.Errorf("scan Organization Spend: %w", err)
+		}
+
+		spend = append(spend, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return spend, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return spend, nil
+}
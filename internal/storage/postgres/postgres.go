@@ -3,28 +3,50 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/backoff"
 	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
 	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
+	"go.opentelemetry.io/otel"
 )
 
+const defaultPingTimeout = 5 * time.Second
+
+// Storage runs the queries on the primary DB, DB, except the heavy read-only ones that go to the replicas
+// when there are any (see reader).
 type Storage struct {
 	DB *pgxpool.Pool
-}
 
-func New(dbConfig config.DatabaseConfig) (*Storage, error) {
-	db := &Storage{}
+	pingTimeout time.Duration
 
-	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s "+
-		"password=%s dbname=%s sslmode=%s",
-		dbConfig.Host, dbConfig.Port, dbConfig.DBUser, dbConfig.DBPassword, dbConfig.DBName, dbConfig.DBSSLMode)
+	replicas    []*replica
+	nextReplica atomic.Uint64
+	sticky      *stickiness
+}
 
-	err := db.connect(psqlInfo)
+// New connects to the DB, retrying with backoff as dbConfig.ConnectRetry allows, so the service can start
+// before the DB accepts connections, and to the replicas in dbConfig.Replicas.
+func New(ctx context.Context, dbConfig config.DatabaseConfig, logger *slog.Logger) (*Storage, error) {
+	db := &Storage{pingTimeout: dbConfig.PingTimeout, sticky: newStickiness(dbConfig.ReplicaStickiness)}
+	if db.pingTimeout <= 0 {
+		db.pingTimeout = defaultPingTimeout
+	}
+
+	err := db.connect(ctx, dbConfig, logger)
 	if err != nil {
-		return nil, fmt.Errorf("error creating connection DB %w", models.ErrDBConnectionCreation)
+		return nil, fmt.Errorf("error creating connection DB %w: %w", models.ErrDBConnectionCreation, err)
+	}
+
+	if err := db.connectReplicas(ctx, dbConfig, logger); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("error creating connection DB replica %w: %w", models.ErrDBConnectionCreation, err)
 	}
 
 	return db, nil
@@ -32,20 +54,43 @@ func New(dbConfig config.DatabaseConfig) (*Storage, error) {
 
 func (store *Storage) Close() {
 	store.DB.Close()
+
+	for _, r := range store.replicas {
+		r.pool.Close()
+	}
 }
 
-func (store *Storage) connect(connStr string) error {
-	pool, err := pgxpool.New(context.Background(), connStr)
+func (store *Storage) connect(ctx context.Context, dbConfig config.DatabaseConfig, logger *slog.Logger) error {
+	poolConfig, err := newPoolConfig(dbConfig)
+	if err != nil {
+		return fmt.Errorf("db.connect parse config: %w", err)
+	}
+
+	// The pool dials lazily, creating it doesn't need the DB up.
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return fmt.Errorf("db.connect: %w", err)
 	}
 
-	timeout := 5 * time.Second
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	retry := dbConfig.ConnectRetry
+	b := backoff.Backoff{
+		Initial:    retry.InitialInterval,
+		Max:        retry.MaxInterval,
+		Multiplier: retry.Multiplier,
+		Jitter:     retry.Jitter,
+	}
 
-	err = pool.Ping(ctx)
+	err = backoff.Retry(ctx, b, retry.Attempts, func(attempt int) error {
+		logger.Info("Connecting to DB", "attempt", attempt, "attempts", retry.Attempts)
+
+		return store.ping(ctx, pool)
+	}, func(attempt int, err error, delay time.Duration) {
+		logger.Warn("DB is not available", "attempt", attempt, "attempts", retry.Attempts,
+			"retry_in", delay.String(), slog.Any("error_details", err))
+	})
 	if err != nil {
+		pool.Close()
+
 		return fmt.Errorf("db.connect pool ping: %w", err)
 	}
 
@@ -53,3 +98,86 @@ func (store *Storage) connect(connStr string) error {
 
 	return nil
 }
+
+func (store *Storage) ping(ctx context.Context, pool *pgxpool.Pool) error {
+	ctx, cancel := context.WithTimeout(ctx, store.pingTimeout)
+	defer cancel()
+
+	return pool.Ping(ctx)
+}
+
+// Watch pings the DB every interval until ctx is done. The pool drops broken connections and dials new ones
+// on the next ping, so the connection comes back without a request paying for it. Losing and regaining the
+// DB is logged once each. The replicas are health-checked along.
+func (store *Storage) Watch(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	reachable := true
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := store.ping(ctx, store.DB)
+
+		switch {
+		case err != nil && reachable && ctx.Err() == nil:
+			logger.Error("Lost connection to DB, reconnecting", slog.Any("error_details", err))
+		case err == nil && !reachable:
+			logger.Info("Reconnected to DB")
+		}
+
+		reachable = err == nil
+
+		store.checkReplicas(ctx, logger)
+	}
+}
+
+// newPoolConfig applies the pool settings of dbConfig over the connection string, the zero values keep
+// the pgxpool defaults.
+func newPoolConfig(dbConfig config.DatabaseConfig) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(dbConfig.ConnString())
+	if err != nil {
+		return nil, err
+	}
+
+	if dbConfig.MaxConns > 0 {
+		poolConfig.MaxConns = int32(dbConfig.MaxConns)
+	}
+
+	if dbConfig.MinConns > 0 {
+		poolConfig.MinConns = int32(dbConfig.MinConns)
+	}
+
+	if dbConfig.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = dbConfig.MaxConnLifetime
+	}
+
+	if dbConfig.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = dbConfig.MaxConnIdleTime
+	}
+
+	if dbConfig.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = dbConfig.HealthCheckPeriod
+	}
+
+	if dbConfig.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(dbConfig.StatementTimeout.Milliseconds(), 10)
+	}
+
+	if dbConfig.ApplicationName != "" {
+		poolConfig.ConnConfig.RuntimeParams["application_name"] = dbConfig.ApplicationName
+	}
+
+	poolConfig.ConnConfig.Tracer = newQueryTracer(otel.GetTracerProvider())
+
+	return poolConfig, nil
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+)
+
+func TestNewPoolConfig(t *testing.T) {
+	t.Parallel()
+
+	dbConfig := config.DatabaseConfig{
+		DSN:               "host=db port=5432 dbname=selectel user=selectel password=secret",
+		MaxConns:          12,
+		MinConns:          3,
+		MaxConnLifetime:   time.Hour,
+		MaxConnIdleTime:   10 * time.Minute,
+		HealthCheckPeriod: 30 * time.Second,
+		StatementTimeout:  1500 * time.Millisecond,
+		ApplicationName:   "subscriptionservice",
+	}
+
+	poolConfig, err := newPoolConfig(dbConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if poolConfig.MaxConns != 12 || poolConfig.MinConns != 3 {
+		t.Errorf("expected conns 3..12, got %d..%d", poolConfig.MinConns, poolConfig.MaxConns)
+	}
+
+	if poolConfig.MaxConnLifetime != time.Hour || poolConfig.MaxConnIdleTime != 10*time.Minute ||
+		poolConfig.HealthCheckPeriod != 30*time.Second {
+		t.Errorf("unexpected durations: lifetime %v, idle %v, health check %v",
+			poolConfig.MaxConnLifetime, poolConfig.MaxConnIdleTime, poolConfig.HealthCheckPeriod)
+	}
+
+	params := poolConfig.ConnConfig.RuntimeParams
+	if params["statement_timeout"] != "1500" {
+		t.Errorf("expected statement_timeout 1500, got %q", params["statement_timeout"])
+	}
+
+	if params["application_name"] != "subscriptionservice" {
+		t.Errorf("expected application_name subscriptionservice, got %q", params["application_name"])
+	}
+
+	if poolConfig.ConnConfig.Tracer == nil {
+		t.Error("expected query tracer to be set")
+	}
+}
+
+func TestNewPoolConfigKeepsDefaults(t *testing.T) {
+	t.Parallel()
+
+	defaults, err := newPoolConfig(config.DatabaseConfig{DSN: "host=db dbname=selectel"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := defaults.ConnConfig.RuntimeParams["statement_timeout"]; ok {
+		t.Error("expected no statement_timeout without config")
+	}
+
+	if defaults.MaxConns <= 0 || defaults.HealthCheckPeriod <= 0 {
+		t.Errorf("expected pgxpool defaults, got max conns %d, health check %v",
+			defaults.MaxConns, defaults.HealthCheckPeriod)
+	}
+}
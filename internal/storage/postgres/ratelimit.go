@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TakeRateLimitTokens refills the token buckets stored under keys for the time elapsed since their last update
+// and takes one token from each of them only if all of them have one. The row locks serialize the replicas sharing
+// the buckets, they are taken in the order of the keys so that two requests never wait on each other. It returns
+// whether each bucket had a token and the tokens left in it, in the order of keys.
+func (store *Storage) TakeRateLimitTokens(ctx context.Context, keys []string, rates []float64, bursts []int) (
+	allowed []bool, tokens []float64, err error,
+) {
+	sqlStatement := `WITH request AS (
+	                     SELECT * FROM unnest($1::text[], $2::float8[], $3::float8[]) AS r(key, rate, burst)
+	                 ), bucket AS (
+	                     SELECT b.key, b.tokens, b.updated_at
+	                     FROM public.rate_limit_bucket b
+	                     WHERE b.key = ANY($1::text[])
+	                     ORDER BY b.key
+	                     FOR UPDATE
+	                 ), refilled AS (
+	                     SELECT r.key, LEAST(r.burst, COALESCE(b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * r.rate,
+	                                                           r.burst)) AS tokens
+	                     FROM request r
+	                     LEFT JOIN bucket b ON b.key = r.key
+	                 ), decision AS (
+	                     SELECT bool_and(tokens >= 1) AS allowed FROM refilled
+	                 )
+	                 INSERT INTO public.rate_limit_bucket (key, tokens, updated_at)
+	                 SELECT key, CASE WHEN (SELECT allowed FROM decision) THEN tokens - 1 ELSE tokens END, now()
+	                 FROM refilled
+	                 ORDER BY key
+	                 ON CONFLICT (key) DO UPDATE SET tokens = EXCLUDED.tokens, updated_at = EXCLUDED.updated_at
+	                 RETURNING key, (SELECT f.tokens >= 1 FROM refilled f WHERE f.key = rate_limit_bucket.key), tokens;`
+
+	burstTokens := make([]float64, len(bursts))
+	for i, burst := range bursts {
+		burstTokens[i] = float64(burst)
+	}
+
+	rows, err := store.DB.Query(ctx, sqlStatement, keys, rates, burstTokens)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	index := make(map[string]int, len(keys))
+	for i, key := range keys {
+		index[key] = i
+	}
+
+	allowed = make([]bool, len(keys))
+	tokens = make([]float64, len(keys))
+
+	for rows.Next() {
+		var (
+			key      string
+			hasToken bool
+			left     float64
+		)
+
+		if err := rows.Scan(&key, &hasToken, &left); err != nil {
+			return nil, nil, fmt.Errorf("scan Rate Limit Bucket: %w", err)
+		}
+
+		allowed[index[key]], tokens[index[key]] = hasToken, left
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return allowed, tokens, nil
+}
+
+// DeleteRateLimitBuckets removes the buckets untouched since before.
+func (store *Storage) DeleteRateLimitBuckets(ctx context.Context, before time.Time) error {
+	sqlStatement := `DELETE FROM public.rate_limit_bucket WHERE updated_at < $1;`
+
+	if _, err := store.DB.Exec(ctx, sqlStatement, before); err != nil {
+		return fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+
+	return nil
+}
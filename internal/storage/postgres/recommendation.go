@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+// GetActiveSubscriptionsByUserID returns the subscriptions of the user that have not ended before month,
+// with the category and price of the matching service catalog entry.
+func (store *Storage) GetActiveSubscriptionsByUserID(ctx context.Context, id uuid.UUID, month time.Time) (subs []models.ActiveSubscription, err error) {
+	sqlStatement := `SELECT s.id, s.service_name, s.price, s.seats, s.currency, s.billing_period, c.category, c.price
+	                 FROM public.subscription s
+	                 LEFT JOIN public.service_catalog c ON lower(c.service_name) = lower(s.service_name)
+	                 WHERE s.user_id = $1
+	                   AND (s.end_date IS NULL OR s.end_date >= date_trunc('month', $2::date))
+	                 ORDER BY s.id;`
+
+	rows, err := store.reader(id).Query(ctx, sqlStatement, id, month)
+	if err != nil {
+		return subs, fmt.Errorf("failed to query DB %w", translateError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sub models.ActiveSubscription
+
+		if err := rows.Scan(&sub.ID, &sub.ServiceName, &sub.Price, &sub.Seats, &sub.Currency, &sub.BillingPeriod,
+			&sub.Category, &sub.CatalogPrice); err != nil {
+			return subs, fmt.Errorf("scan Active Subscription: %w", err)
+		}
+
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return subs, fmt.Errorf("failed to read DB %w", translateError(err))
+	}
+
+	return subs, nil
+}
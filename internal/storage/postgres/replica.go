@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// querier runs the read-only queries, it is the primary pool or replicaReader.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// replica is a read-only DB taking the heavy read queries while it answers the health checks.
+type replica struct {
+	pool    *pgxpool.Pool
+	host    string
+	healthy atomic.Bool
+}
+
+// connectReplicas creates the pools of the replicas in dbConfig.Replicas. A replica that doesn't answer the
+// first ping doesn't fail the startup, it waits for Watch to find it healthy.
+func (store *Storage) connectReplicas(ctx context.Context, dbConfig config.DatabaseConfig, logger *slog.Logger) error {
+	for _, dsn := range dbConfig.Replicas {
+		replicaConfig := dbConfig
+		replicaConfig.DSN = dsn
+
+		poolConfig, err := newPoolConfig(replicaConfig)
+		if err != nil {
+			return fmt.Errorf("db.connectReplicas parse config: %w", err)
+		}
+
+		pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+		if err != nil {
+			return fmt.Errorf("db.connectReplicas: %w", err)
+		}
+
+		r := &replica{pool: pool, host: poolConfig.ConnConfig.Host}
+
+		if err := store.ping(ctx, pool); err != nil {
+			logger.Warn("DB replica is not available, reading from the primary", "host", r.host,
+				slog.Any("error_details", err))
+		} else {
+			r.healthy.Store(true)
+		}
+
+		store.replicas = append(store.replicas, r)
+	}
+
+	return nil
+}
+
+// checkReplicas pings the replicas and updates their health, logging the changes.
+func (store *Storage) checkReplicas(ctx context.Context, logger *slog.Logger) {
+	for _, r := range store.replicas {
+		err := store.ping(ctx, r.pool)
+		if ctx.Err() != nil {
+			return
+		}
+
+		healthy := err == nil
+
+		switch {
+		case !healthy && r.healthy.Load():
+			logger.Error("DB replica is not available, reading from the primary", "host", r.host,
+				slog.Any("error_details", err))
+		case healthy && !r.healthy.Load():
+			logger.Info("DB replica is available again", "host", r.host)
+		}
+
+		r.healthy.Store(healthy)
+	}
+}
+
+// reader returns where the read-only queries for users go. They go to the replicas unless one of users has
+// written within the stickiness window, the replicas may not have replayed that write yet.
+func (store *Storage) reader(users ...uuid.UUID) querier {
+	if len(store.replicas) == 0 || store.sticky.active(users...) {
+		return store.DB
+	}
+
+	return replicaReader{store: store}
+}
+
+// replicaReader sends a query to the next healthy replica in turn. A replica failing to run it, rather than
+// rejecting it, is marked unhealthy until the next health check and the query moves on, to the primary last.
+type replicaReader struct {
+	store *Storage
+}
+
+func (r replicaReader) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	replicas := r.store.replicas
+	start := int(r.store.nextReplica.Add(1) % uint64(len(replicas)))
+
+	for i := range replicas {
+		replica := replicas[(start+i)%len(replicas)]
+		if !replica.healthy.Load() {
+			continue
+		}
+
+		rows, err := replica.pool.Query(ctx, sql, args...)
+
+		var pgErr *pgconn.PgError
+		if err == nil || errors.As(err, &pgErr) || ctx.Err() != nil {
+			return rows, err
+		}
+
+		replica.healthy.Store(false)
+	}
+
+	return r.store.DB.Query(ctx, sql, args...)
+}
+
+// stickiness remembers the users who have written recently, for window after every write. It is kept in
+// process memory, the other instances of the service don't know about the writes of this one.
+type stickiness struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	until     map[uuid.UUID]time.Time
+	lastSweep time.Time
+}
+
+func newStickiness(window time.Duration) *stickiness {
+	return &stickiness{window: window, now: time.Now, until: make(map[uuid.UUID]time.Time)}
+}
+
+// mark records a write of the user. The expired users are swept at most once per window.
+func (s *stickiness) mark(user uuid.UUID) {
+	if s == nil || s.window <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.until[user] = now.Add(s.window)
+
+	if now.Sub(s.lastSweep) < s.window {
+		return
+	}
+
+	for id, until := range s.until {
+		if !now.Before(until) {
+			delete(s.until, id)
+		}
+	}
+
+	s.lastSweep = now
+}
+
+// active reports whether one of users has written within the window.
+func (s *stickiness) active(users ...uuid.UUID) bool {
+	if s == nil || s.window <= 0 {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	for _, user := range users {
+		if until, ok := s.until[user]; ok && now.Before(until) {
+			return true
+		}
+	}
+
+	return false
+}
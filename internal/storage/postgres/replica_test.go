@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestStickiness(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	sticky := newStickiness(5 * time.Second)
+	sticky.now = func() time.Time { return now }
+
+	writer, reader := uuid.New(), uuid.New()
+	sticky.mark(writer)
+
+	if !sticky.active(writer) || !sticky.active(reader, writer) {
+		t.Error("expected the writer to stick to the primary")
+	}
+
+	if sticky.active(reader) {
+		t.Error("expected a user without writes to read from the replicas")
+	}
+
+	now = now.Add(5 * time.Second)
+
+	if sticky.active(writer) {
+		t.Error("expected the stickiness to expire after the window")
+	}
+
+	sticky.mark(reader)
+
+	if _, ok := sticky.until[writer]; ok {
+		t.Error("expected the expired writer to be swept")
+	}
+
+	var disabled *stickiness
+	disabled.mark(writer)
+
+	if disabled.active(writer) || newStickiness(0).active(writer) {
+		t.Error("expected no stickiness without a window")
+	}
+}
+
+// unreachablePool is a pool of a DB nothing listens on, its queries fail to connect.
+func unreachablePool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	pool, err := pgxpool.New(context.Background(), "host=127.0.0.1 port=1 user=selectel dbname=selectel connect_timeout=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	primary := unreachablePool(t)
+	user := uuid.New()
+
+	store := &Storage{DB: primary, sticky: newStickiness(time.Minute)}
+
+	if store.reader(user) != primary {
+		t.Error("expected the primary without replicas")
+	}
+
+	down := &replica{pool: unreachablePool(t), host: "down"}
+	down.healthy.Store(true)
+
+	store.replicas = []*replica{down}
+
+	if _, ok := store.reader(user).(replicaReader); !ok {
+		t.Error("expected the replicas for a user without writes")
+	}
+
+	store.sticky.mark(user)
+
+	if store.reader(user) != primary {
+		t.Error("expected the primary right after a write of the user")
+	}
+
+	if _, ok := store.reader().(replicaReader); !ok {
+		t.Error("expected the replicas for the queries of no user")
+	}
+
+	// The replica can't run the query, the primary gets it and the replica waits for a health check.
+	if _, err := store.reader().Query(context.Background(), "SELECT 1"); err == nil {
+		t.Error("expected the unreachable primary to fail the query")
+	}
+
+	if down.healthy.Load() {
+		t.Error("expected the failing replica to be marked unhealthy")
+	}
+}
@@ -2,140 +2,174 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
 	"github.com/google/uuid"
-	"time"
 
+	"github.com/jackc/pgx/v5"
 	_ "github.com/lib/pq"
 )
 
-func (store *Storage) GetSubscriptionListByUserID(ctx context.Context, id uuid.UUID) (subscription []models.SubscriptionListDB, err error) {
-	sqlStatement := `SELECT price, start_date, service_name FROM public.subscription WHERE user_id = $1;`
+const subscriptionColumns = `id, user_id, service_name, price, currency, billing_period, start_date, end_date, trial_end_date,
+                              seats, organization_id, department`
 
-	rows, err := store.DB.Query(ctx, sqlStatement, id)
+func (store *Storage) GetSubscriptionsByUserID(ctx context.Context, id uuid.UUID) ([]models.Subscription, error) {
+	return store.getSubscriptionsByUserID(ctx, store.reader(id), id)
+}
+
+// GetPrimarySubscriptionsByUserID reads the subscriptions of the user from the primary, whatever the replicas.
+func (store *Storage) GetPrimarySubscriptionsByUserID(ctx context.Context, id uuid.UUID) ([]models.Subscription, error) {
+	return store.getSubscriptionsByUserID(ctx, store.DB, id)
+}
+
+func (store *Storage) getSubscriptionsByUserID(ctx context.Context, reader querier, id uuid.UUID) (
+	subs []models.Subscription, err error,
+) {
+	sqlStatement := `SELECT ` + subscriptionColumns + `
+	                 FROM public.subscription
+	                 WHERE user_id = $1
+	                 ORDER BY start_date, id;`
+
+	rows, err := reader.Query(ctx, sqlStatement, id)
 	if err != nil {
-		return subscription, fmt.Errorf("failed to query DB %w", err)
+		return subs, fmt.Errorf("failed to query DB %w", translateError(err))
 	}
 	defer rows.Close()
 
-	found := false
-
 	for rows.Next() {
-		var t models.SubscriptionListDB
-
-		if err := rows.Scan(&t.Price, &t.StartDate, &t.ServiceName); err != nil {
-			return subscription, fmt.Errorf("scan Subscription List: %w", err)
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return subs, err
 		}
 
-		subscription = append(subscription, t)
-		found = true
+		subs = append(subs, sub)
 	}
 
-	if !found {
-		return subscription, models.ErrNotFound
+	if err := rows.Err(); err != nil {
+		return subs, fmt.Errorf("failed to read DB %w", translateError(err))
 	}
 
-	return subscription, nil
+	return subs, nil
 }
 
-func (store *Storage) PostSubscription(ctx context.Context, sub models.SubscriptionListJSON) error {
-	sqlStatement := `INSERT INTO subscription 
-    				 (user_id, start_date, price, service_name) 
-					 VALUES($1,$2,$3,$4);`
+func (store *Storage) GetSubscription(ctx context.Context, id int) (models.Subscription, error) {
+	sqlStatement := `SELECT ` + subscriptionColumns + ` FROM public.subscription WHERE id = $1;`
 
-	startDateDB, err := time.Parse("02-2006", sub.StartDate)
-	if err != nil {
-		return fmt.Errorf("error adding to DB %w", err)
+	sub, err := scanSubscription(store.DB.QueryRow(ctx, sqlStatement, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sub, models.ErrNotFound
 	}
 
-	result, err := store.DB.Exec(ctx, sqlStatement, sub.UserID, startDateDB, sub.Price, sub.ServiceName)
-	if err != nil {
-		if !result.Insert() {
-			return models.ErrUnique
-		}
-
-		return fmt.Errorf("error adding to DB %w", err)
-	}
-	return nil
+	return sub, err
 }
 
-func (store *Storage) DeleteSubscription(ctx context.Context, id int) error {
-	sqlStatement := `DELETE FROM public.subscription WHERE id = $1;`
+func (store *Storage) PostSubscription(ctx context.Context, sub models.Subscription) (id int, err error) {
+	sqlStatement := `INSERT INTO subscription
+    				 (user_id, start_date, price, service_name, end_date, currency, billing_period, trial_end_date,
+    				  seats, organization_id, department)
+					 VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+					 RETURNING id;`
 
-	result, err := store.DB.Exec(ctx, sqlStatement, id)
+	err = store.DB.QueryRow(ctx, sqlStatement, sub.UserID, sub.StartDate, sub.Price, sub.ServiceName,
+		sub.EndDate, sub.Currency, sub.BillingPeriod, sub.TrialEndDate,
+		seats(sub), sub.OrganizationID, sub.Department).Scan(&id)
 	if err != nil {
-		return fmt.Errorf("error deleting from DB %w", err)
+		return 0, fmt.Errorf("error adding to DB %w", translateError(err))
 	}
 
-	if result.RowsAffected() == 0 {
-		return models.ErrNotFound
-	}
+	store.sticky.mark(sub.UserID)
 
-	return nil
+	return id, nil
 }
 
-func (store *Storage) UpdateSubscription(ctx context.Context, sub models.SubscriptionListJSON, id int) error {
-	sqlStatement := `UPDATE public.subscription SET 
-                     user_id =$1, 
-                     start_date=$2, 
-                     price=$3, 
-                     service_name=$4
-                     WHERE id =$5;`
+func (store *Storage) DeleteSubscription(ctx context.Context, id int) error {
+	sqlStatement := `DELETE FROM public.subscription WHERE id = $1 RETURNING user_id;`
 
-	startDateDB, err := time.Parse("02-2006", sub.StartDate)
-	if err != nil {
-		return fmt.Errorf("error adding to DB %w", err)
+	var userID uuid.UUID
+
+	err := store.DB.QueryRow(ctx, sqlStatement, id).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound
 	}
 
-	result, err := store.DB.Exec(ctx, sqlStatement, sub.UserID, startDateDB, sub.Price, sub.ServiceName, id)
 	if err != nil {
-		return fmt.Errorf("error updating DB %w", err)
+		return fmt.Errorf("error deleting from DB %w", translateError(err))
 	}
 
-	if result.RowsAffected() == 0 {
-		return models.ErrNotFound
-	}
+	store.sticky.mark(userID)
 
 	return nil
 }
 
-func (store *Storage) GetTotalPeriodCostByDatesAndServiceName(ctx context.Context, subList models.SubscriptionListToCostJSON) (res int, err error) {
-	sqlStatement := `SELECT SUM(price) 
-				     FROM public.subscription 
-	                 where user_id = $1
-	                 and start_date >=$2
-	                 and start_date <=$3`
+// UpdateSubscription replaces the subscription and records the change of its seats in the seat history. The row
+// is locked before its seats are read, so concurrent updates record their changes one after the other.
+func (store *Storage) UpdateSubscription(ctx context.Context, sub models.Subscription) error {
+	sqlStatement := `UPDATE public.subscription SET
+                     user_id =$1,
+                     start_date=$2,
+                     price=$3,
+                     service_name=$4,
+                     end_date=$5,
+                     currency=$6,
+                     billing_period=$7,
+                     trial_end_date=$8,
+                     seats=$10,
+                     organization_id=$11,
+                     department=$12
+                     WHERE id =$9;`
+
+	err := pgx.BeginFunc(ctx, store.DB, func(tx pgx.Tx) error {
+		var previous int
+
+		if err := tx.QueryRow(ctx, `SELECT seats FROM public.subscription WHERE id = $1 FOR UPDATE;`, sub.ID).
+			Scan(&previous); err != nil {
+			return err
+		}
 
-	startDateDB, err := time.Parse("02-2006", subList.StartDate)
-	if err != nil {
-		return res, fmt.Errorf("error adding to DB %w", err)
+		if _, err := tx.Exec(ctx, sqlStatement, sub.UserID, sub.StartDate, sub.Price, sub.ServiceName,
+			sub.EndDate, sub.Currency, sub.BillingPeriod, sub.TrialEndDate, sub.ID,
+			seats(sub), sub.OrganizationID, sub.Department); err != nil {
+			return err
+		}
+
+		if previous == seats(sub) {
+			return nil
+		}
+
+		_, err := tx.Exec(ctx, `INSERT INTO subscription_seat_change (subscription_id, previous_seats, seats)
+		                        VALUES($1,$2,$3);`, sub.ID, previous, seats(sub))
+
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound
 	}
 
-	endDateDB, err := time.Parse("02-2006", subList.EndDate)
 	if err != nil {
-		return res, fmt.Errorf("error adding to DB %w", err)
+		return fmt.Errorf("error updating DB %w", translateError(err))
 	}
 
-	if len(subList.ServiceName) > 0 {
-		sqlStatement += " and service_name = ANY($4)"
+	store.sticky.mark(sub.UserID)
 
-		rows := store.DB.QueryRow(ctx, sqlStatement, subList.UserID, startDateDB, endDateDB, subList.ServiceName)
-
-		err = rows.Scan(&res)
-		if err != nil {
-			return res, fmt.Errorf("failed to parse DB %w", err)
-		}
-
-		return res, nil
-	}
+	return nil
+}
 
-	rows := store.DB.QueryRow(ctx, sqlStatement, subList.UserID, startDateDB, endDateDB)
+// seats returns the seats of sub, a subscription without seats set has one.
+func seats(sub models.Subscription) int {
+	return max(sub.Seats, 1)
+}
 
-	err = rows.Scan(&res)
+func scanSubscription(row pgx.Row) (sub models.Subscription, err error) {
+	err = row.Scan(&sub.ID, &sub.UserID, &sub.ServiceName, &sub.Price, &sub.Currency, &sub.BillingPeriod,
+		&sub.StartDate, &sub.EndDate, &sub.TrialEndDate, &sub.Seats, &sub.OrganizationID, &sub.Department)
 	if err != nil {
-		return res, fmt.Errorf("failed to parse DB %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sub, err
+		}
+
+		return sub, fmt.Errorf("scan Subscription: %w", err)
 	}
 
-	return res, nil
+	return sub, nil
 }
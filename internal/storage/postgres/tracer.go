@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"runtime"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName      = "github.com/Ostmind/subscriptionservice/internal/storage/postgres"
+	storageMethod   = tracerName + ".(*Storage)."
+	maxCallerFrames = 16
+)
+
+// queryTracer is the pgx hook starting a client span for every query. The span is named after the Storage method
+// running the query, so a slow request shows which repository call it was waiting on.
+type queryTracer struct {
+	tracer trace.Tracer
+}
+
+func newQueryTracer(provider trace.TracerProvider) *queryTracer {
+	return &queryTracer{tracer: provider.Tracer(tracerName)}
+}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, _ = t.tracer.Start(ctx, spanName(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBQueryText(data.SQL),
+		))
+
+	return ctx
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+
+		return
+	}
+
+	span.SetAttributes(semconv.DBOperationName(strings.SplitN(data.CommandTag.String(), " ", 2)[0]))
+}
+
+// spanName returns "Storage.<Method>" for the first Storage method on the call stack.
+func spanName() string {
+	pcs := make([]uintptr, maxCallerFrames)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+
+	for {
+		frame, more := frames.Next()
+		if method, ok := strings.CutPrefix(frame.Function, storageMethod); ok {
+			return "Storage." + strings.SplitN(method, ".", 2)[0]
+		}
+
+		if !more {
+			return "postgres.query"
+		}
+	}
+}
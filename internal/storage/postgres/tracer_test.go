@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// tracedQuery stands in for a Storage method, pgx calls the tracer the same way from inside Query.
+func (store *Storage) tracedQuery(ctx context.Context, tracer *queryTracer, err error) {
+	ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1"), Err: err})
+}
+
+func TestQueryTracerNamesSpansAfterStorageMethod(t *testing.T) {
+	t.Parallel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := newQueryTracer(tracing.NewProvider(sdktrace.NewSimpleSpanProcessor(exporter), "test", 1))
+
+	store := &Storage{}
+	store.tracedQuery(context.Background(), tracer, nil)
+	store.tracedQuery(context.Background(), tracer, errors.New("connection reset"))
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	for _, span := range spans {
+		if span.Name != "Storage.tracedQuery" {
+			t.Errorf("unexpected span name %q", span.Name)
+		}
+	}
+
+	if spans[0].Status.Code == codes.Error || spans[1].Status.Code != codes.Error {
+		t.Errorf("unexpected statuses %v, %v", spans[0].Status, spans[1].Status)
+	}
+}
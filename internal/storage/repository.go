@@ -2,14 +2,106 @@ package storage
 
 import (
 	"context"
+	"time"
+
 	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
 	"github.com/google/uuid"
 )
 
+// Repository persists subscriptions. The rules about them live in the service package, the backends only
+// report ErrNotFound for a missing id and ErrUnique for a duplicate (user, start date, price, service).
 type Repository interface {
-	GetSubscriptionListByUserID(ctx context.Context, id uuid.UUID) ([]models.SubscriptionListDB, error)
-	PostSubscription(ctx context.Context, sub models.SubscriptionListJSON) error
+	GetSubscriptionsByUserID(ctx context.Context, id uuid.UUID) ([]models.Subscription, error)
+	GetSubscription(ctx context.Context, id int) (models.Subscription, error)
+	PostSubscription(ctx context.Context, sub models.Subscription) (int, error)
+	UpdateSubscription(ctx context.Context, sub models.Subscription) error
 	DeleteSubscription(ctx context.Context, id int) error
-	UpdateSubscription(ctx context.Context, sub models.SubscriptionListJSON, id int) error
-	GetTotalPeriodCostByDatesAndServiceName(ctx context.Context, subList models.SubscriptionListToCostJSON) (int, error)
+}
+
+// PrimaryRepository is implemented by the backends with read replicas. It reads from the primary, which
+// has every write by the time a change is notified, while the replicas may still lag behind it.
+type PrimaryRepository interface {
+	GetPrimarySubscriptionsByUserID(ctx context.Context, id uuid.UUID) ([]models.Subscription, error)
+	GetPrimarySubscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) ([]models.Subscription, error)
+}
+
+type AnalyticsRepository interface {
+	GetSpendAnalytics(ctx context.Context, filter models.SpendAnalyticsFilter) (models.SpendAnalyticsDB, error)
+	GetMonthlySpendByUserIDs(ctx context.Context, ids []uuid.UUID, from, to time.Time) ([]models.MonthlySpendDB, error)
+}
+
+type ForecastRepository interface {
+	GetForecastSubscriptionsByUserID(ctx context.Context, id uuid.UUID, from time.Time) ([]models.ForecastSubscription, error)
+	PostPriceChange(ctx context.Context, subscriptionID int, change models.PriceChange) error
+}
+
+type BudgetRepository interface {
+	PostBudget(ctx context.Context, budget models.BudgetJSON) error
+	GetBudgetsByUserID(ctx context.Context, id uuid.UUID) ([]models.BudgetJSON, error)
+	GetBudget(ctx context.Context, id int) (models.BudgetJSON, error)
+	UpdateBudget(ctx context.Context, budget models.BudgetJSON, id int) error
+	DeleteBudget(ctx context.Context, id int) error
+	GetBudgetUsage(ctx context.Context, month time.Time) ([]models.BudgetUsage, error)
+	SetBudgetAlertedMonth(ctx context.Context, id int, month *time.Time) error
+}
+
+// HouseholdRepository keeps the households, their members and the subscriptions they share.
+type HouseholdRepository interface {
+	PostHousehold(ctx context.Context, household models.HouseholdJSON) (int, error)
+	GetHousehold(ctx context.Context, id int) (models.HouseholdJSON, error)
+	AddHouseholdMember(ctx context.Context, member models.HouseholdMemberJSON) error
+	DeleteHouseholdMember(ctx context.Context, member models.HouseholdMemberJSON) error
+	PutSharedSubscription(ctx context.Context, shared models.SharedSubscriptionJSON) error
+	DeleteSharedSubscription(ctx context.Context, subscriptionID int) error
+	GetSharedSubscriptionsByUserID(ctx context.Context, id uuid.UUID) ([]models.SharedSubscription, error)
+	GetSharedSubscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) ([]models.SharedSubscription, error)
+	GetSharedSubscriptionsByHouseholdID(ctx context.Context, id int) ([]models.SharedSubscription, error)
+	GetHouseholdCharges(ctx context.Context, id int, from, to time.Time) ([]models.SharedCharge, error)
+}
+
+// OrganizationRepository keeps the organizations, their members with roles and departments, the seat history of
+// the subscriptions and the spend of the organization subscriptions.
+type OrganizationRepository interface {
+	PostOrganization(ctx context.Context, org models.OrganizationJSON) (int, error)
+	GetOrganization(ctx context.Context, id int) (models.OrganizationJSON, error)
+	PutOrganizationMember(ctx context.Context, member models.OrganizationMemberJSON) error
+	DeleteOrganizationMember(ctx context.Context, member models.OrganizationMemberJSON) error
+	GetOrganizationRole(ctx context.Context, organizationID int, userID uuid.UUID) (string, error)
+	GetSeatChanges(ctx context.Context, subscriptionID int) ([]models.SeatChangeJSON, error)
+	GetOrganizationSpend(ctx context.Context, filter models.OrganizationSpendFilter) ([]models.OrganizationSpendDB, error)
+}
+
+type RecommendationRepository interface {
+	GetActiveSubscriptionsByUserID(ctx context.Context, id uuid.UUID, month time.Time) ([]models.ActiveSubscription, error)
+}
+
+// BatchRepository loads the subscriptions of several users with a single query.
+type BatchRepository interface {
+	GetSubscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) ([]models.Subscription, error)
+}
+
+type CatalogRepository interface {
+	GetSubscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) ([]models.Subscription, error)
+	GetCatalogServices(ctx context.Context) ([]models.CatalogService, error)
+	GetCatalogServicesByNames(ctx context.Context, names []string) ([]models.CatalogService, error)
+}
+
+type MetricsRepository interface {
+	GetServiceSpend(ctx context.Context, month time.Time) ([]models.ServiceSpend, error)
+}
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	GetMigrationVersion(ctx context.Context) (int64, error)
+}
+
+// NotifyRepository spreads messages across the replicas of the service.
+type NotifyRepository interface {
+	Notify(ctx context.Context, channel, payload string) error
+	Listen(ctx context.Context, channel string, handle func(payload string)) error
+}
+
+type RateLimitRepository interface {
+	TakeRateLimitTokens(ctx context.Context, keys []string, rates []float64, bursts []int) ([]bool, []float64, error)
+	DeleteRateLimitBuckets(ctx context.Context, before time.Time) error
 }
@@ -0,0 +1,137 @@
+// Package sqlite stores subscriptions in a SQLite file, for installs where running Postgres is overkill.
+// It implements storage.Repository with the semantics of the Postgres storage.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	defaultPingTimeout = 5 * time.Second
+
+	// busyTimeout is how long a statement waits for the write lock held by another connection.
+	busyTimeout = 5 * time.Second
+
+	// dateLayout is how the date columns are stored, text sorting the same as the dates.
+	dateLayout = "2006-01-02"
+)
+
+type Storage struct {
+	DB *sql.DB
+}
+
+// Open opens the SQLite database at path with the pragmas the service relies on, creating the file if needed.
+func Open(path string) (*sql.DB, error) {
+	pragmas := url.Values{}
+	pragmas.Add("_pragma", "foreign_keys(1)")
+	pragmas.Add("_pragma", "journal_mode(WAL)")
+	pragmas.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+pragmas.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite takes one writer at a time, a single connection queues the writes in the pool instead of
+	// failing them with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	return db, nil
+}
+
+func New(ctx context.Context, dbConfig config.DatabaseConfig, logger *slog.Logger) (*Storage, error) {
+	db, err := Open(dbConfig.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating connection DB %w: %w", models.ErrDBConnectionCreation, err)
+	}
+
+	timeout := dbConfig.PingTimeout
+	if timeout <= 0 {
+		timeout = defaultPingTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("error creating connection DB %w: %w", models.ErrDBConnectionCreation, err)
+	}
+
+	logger.Info("Opened SQLite DB", "Path", dbConfig.Path)
+
+	return &Storage{DB: db}, nil
+}
+
+func (store *Storage) Close() {
+	store.DB.Close()
+}
+
+func (store *Storage) Ping(ctx context.Context) error {
+	if err := store.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping DB %w", err)
+	}
+
+	return nil
+}
+
+// GetMigrationVersion returns the highest goose migration whose latest record is applied, as goose reports it.
+func (store *Storage) GetMigrationVersion(ctx context.Context) (version int64, err error) {
+	sqlStatement := `SELECT COALESCE(MAX(v.version_id), 0)
+	                 FROM goose_db_version v
+	                 WHERE v.is_applied
+	                   AND v.id = (SELECT MAX(l.id) FROM goose_db_version l WHERE l.version_id = v.version_id);`
+
+	if err := store.DB.QueryRowContext(ctx, sqlStatement).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to query DB %w", err)
+	}
+
+	return version, nil
+}
+
+// isUniqueViolation reports whether err comes from a UNIQUE constraint.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// formatOptionalDate formats a date that may be missing, returning nil for it.
+func formatOptionalDate(date *time.Time) *string {
+	if date == nil {
+		return nil
+	}
+
+	value := date.Format(dateLayout)
+
+	return &value
+}
+
+func parseDate(value string) (time.Time, error) {
+	return time.Parse(dateLayout, value)
+}
+
+func parseOptionalDate(value sql.NullString) (*time.Time, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+
+	date, err := parseDate(value.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return &date, nil
+}
@@ -0,0 +1,67 @@
+package sqlite_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage/sqlite"
+	"github.com/Ostmind/subscriptionservice/internal/storage/storagetest"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/migration"
+	"github.com/pressly/goose/v3"
+)
+
+// newStorage opens a database migrated to the latest schema in a temporary directory.
+func newStorage(t *testing.T) *sqlite.Storage {
+	t.Helper()
+
+	store, err := sqlite.New(context.Background(),
+		config.DatabaseConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "subscriptions.db")},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	t.Cleanup(store.Close)
+
+	provider, err := goose.NewProvider(goose.DialectSQLite3, store.DB,
+		os.DirFS(filepath.Join("..", "..", "subscription", "migration", migration.SQLiteDir)))
+	if err != nil {
+		t.Fatalf("goose: %v", err)
+	}
+
+	if _, err := provider.Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return store
+}
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, newStorage(t))
+}
+
+func TestMigrationVersion(t *testing.T) {
+	t.Parallel()
+
+	store := newStorage(t)
+
+	version, err := store.GetMigrationVersion(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if version != migration.SQLiteVersion {
+		t.Errorf("expected version %d, got %d", migration.SQLiteVersion, version)
+	}
+
+	if err := store.Ping(context.Background()); err != nil {
+		t.Errorf("unexpected ping error: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+const subscriptionColumns = `id, user_id, service_name, price, currency, billing_period, start_date, end_date, trial_end_date,
+                              seats, organization_id, department`
+
+func (store *Storage) GetSubscriptionsByUserID(ctx context.Context, id uuid.UUID) (subs []models.Subscription, err error) {
+	sqlStatement := `SELECT ` + subscriptionColumns + `
+	                 FROM subscription
+	                 WHERE user_id = ?
+	                 ORDER BY start_date, id;`
+
+	rows, err := store.DB.QueryContext(ctx, sqlStatement, id.String())
+	if err != nil {
+		return subs, fmt.Errorf("failed to query DB %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return subs, err
+		}
+
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return subs, fmt.Errorf("failed to read DB %w", err)
+	}
+
+	return subs, nil
+}
+
+func (store *Storage) GetSubscription(ctx context.Context, id int) (models.Subscription, error) {
+	sqlStatement := `SELECT ` + subscriptionColumns + ` FROM subscription WHERE id = ?;`
+
+	sub, err := scanSubscription(store.DB.QueryRowContext(ctx, sqlStatement, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return sub, models.ErrNotFound
+	}
+
+	return sub, err
+}
+
+func (store *Storage) PostSubscription(ctx context.Context, sub models.Subscription) (id int, err error) {
+	sqlStatement := `INSERT INTO subscription
+    				 (user_id, start_date, price, service_name, end_date, currency, billing_period, trial_end_date,
+    				  seats, organization_id, department)
+					 VALUES(?,?,?,?,?,?,?,?,?,?,?)
+					 RETURNING id;`
+
+	err = store.DB.QueryRowContext(ctx, sqlStatement, subscriptionArgs(sub)...).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, models.ErrUnique
+		}
+
+		return 0, fmt.Errorf("error adding to DB %w", err)
+	}
+
+	return id, nil
+}
+
+func (store *Storage) DeleteSubscription(ctx context.Context, id int) error {
+	sqlStatement := `DELETE FROM subscription WHERE id = ?;`
+
+	result, err := store.DB.ExecContext(ctx, sqlStatement, id)
+	if err != nil {
+		return fmt.Errorf("error deleting from DB %w", err)
+	}
+
+	return checkAffected(result)
+}
+
+func (store *Storage) UpdateSubscription(ctx context.Context, sub models.Subscription) error {
+	sqlStatement := `UPDATE subscription SET
+                     user_id = ?,
+                     start_date = ?,
+                     price = ?,
+                     service_name = ?,
+                     end_date = ?,
+                     currency = ?,
+                     billing_period = ?,
+                     trial_end_date = ?,
+                     seats = ?,
+                     organization_id = ?,
+                     department = ?
+                     WHERE id = ?;`
+
+	result, err := store.DB.ExecContext(ctx, sqlStatement, append(subscriptionArgs(sub), sub.ID)...)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("error updating DB %w", models.ErrUnique)
+		}
+
+		return fmt.Errorf("error updating DB %w", err)
+	}
+
+	return checkAffected(result)
+}
+
+// scanSubscription reads a row of subscriptionColumns, passing sql.ErrNoRows through unwrapped.
+func scanSubscription(row interface{ Scan(dest ...any) error }) (sub models.Subscription, err error) {
+	var (
+		startDate             string
+		endDate, trialEndDate sql.NullString
+	)
+
+	err = row.Scan(&sub.ID, &sub.UserID, &sub.ServiceName, &sub.Price, &sub.Currency, &sub.BillingPeriod,
+		&startDate, &endDate, &trialEndDate, &sub.Seats, &sub.OrganizationID, &sub.Department)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sub, err
+		}
+
+		return sub, fmt.Errorf("scan Subscription: %w", err)
+	}
+
+	if sub.StartDate, err = parseDate(startDate); err != nil {
+		return sub, fmt.Errorf("scan Subscription: %w", err)
+	}
+
+	if sub.EndDate, err = parseOptionalDate(endDate); err != nil {
+		return sub, fmt.Errorf("scan Subscription: %w", err)
+	}
+
+	if sub.TrialEndDate, err = parseOptionalDate(trialEndDate); err != nil {
+		return sub, fmt.Errorf("scan Subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+// subscriptionArgs returns the column values of sub in the order of the INSERT and UPDATE statements.
+func subscriptionArgs(sub models.Subscription) []any {
+	return []any{sub.UserID.String(), sub.StartDate.Format(dateLayout), sub.Price, sub.ServiceName,
+		formatOptionalDate(sub.EndDate), sub.Currency, sub.BillingPeriod, formatOptionalDate(sub.TrialEndDate),
+		max(sub.Seats, 1), sub.OrganizationID, sub.Department}
+}
+
+func checkAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read DB %w", err)
+	}
+
+	if affected == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
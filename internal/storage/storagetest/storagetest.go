@@ -0,0 +1,274 @@
+// Package storagetest is the conformance suite every storage backend must pass, so the service behaves
+// the same whichever backend it runs on.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+// missingID is an id no backend hands out.
+const missingID = -1
+
+// Run runs the suite against repo. Every test works on its own random user, so the backends don't need to
+// be empty and the tests run in parallel.
+func Run(t *testing.T, repo storage.Repository) {
+	t.Helper()
+
+	t.Run("PostAndGet", func(t *testing.T) { t.Parallel(); testPostAndGet(t, repo) })
+	t.Run("List", func(t *testing.T) { t.Parallel(); testList(t, repo) })
+	t.Run("ListEmpty", func(t *testing.T) { t.Parallel(); testListEmpty(t, repo) })
+	t.Run("PostUnique", func(t *testing.T) { t.Parallel(); testPostUnique(t, repo) })
+	t.Run("Update", func(t *testing.T) { t.Parallel(); testUpdate(t, repo) })
+	t.Run("UpdateUnique", func(t *testing.T) { t.Parallel(); testUpdateUnique(t, repo) })
+	t.Run("Delete", func(t *testing.T) { t.Parallel(); testDelete(t, repo) })
+	t.Run("NotFound", func(t *testing.T) { t.Parallel(); testNotFound(t, repo) })
+	t.Run("ConcurrentPosts", func(t *testing.T) { t.Parallel(); testConcurrentPosts(t, repo) })
+}
+
+func month(value string) time.Time {
+	date, err := time.Parse(models.MonthLayout, value)
+	if err != nil {
+		panic(err)
+	}
+
+	return date
+}
+
+func subscription(userID uuid.UUID, service string, price int, startDate string) models.Subscription {
+	return models.Subscription{
+		UserID: userID, ServiceName: service, Price: price, Currency: "RUB", BillingPeriod: "monthly",
+		StartDate: month(startDate), Seats: 1,
+	}
+}
+
+func mustPost(t *testing.T, repo storage.Repository, subs ...models.Subscription) []int {
+	t.Helper()
+
+	ids := make([]int, 0, len(subs))
+
+	for _, sub := range subs {
+		id, err := repo.PostSubscription(context.Background(), sub)
+		if err != nil {
+			t.Fatalf("post %+v: %v", sub, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func mustGet(t *testing.T, repo storage.Repository, id int) models.Subscription {
+	t.Helper()
+
+	sub, err := repo.GetSubscription(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get subscription %d: %v", id, err)
+	}
+
+	return sub
+}
+
+func sameDate(got, want *time.Time) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+
+	return got.Equal(*want)
+}
+
+func testPostAndGet(t *testing.T, repo storage.Repository) {
+	endDate, trialEndDate := month("12-2025"), month("10-2025")
+
+	withDates := subscription(uuid.New(), "Netflix", 400, "09-2025")
+	withDates.Currency, withDates.BillingPeriod = "USD", "yearly"
+	withDates.EndDate, withDates.TrialEndDate = &endDate, &trialEndDate
+	withDates.Seats = 5
+
+	for _, want := range []models.Subscription{subscription(uuid.New(), "Spotify", 200, "09-2025"), withDates} {
+		want.ID = mustPost(t, repo, want)[0]
+
+		got := mustGet(t, repo, want.ID)
+		if got.ID != want.ID || got.UserID != want.UserID || got.ServiceName != want.ServiceName ||
+			got.Price != want.Price || got.Currency != want.Currency || got.BillingPeriod != want.BillingPeriod ||
+			!got.StartDate.Equal(want.StartDate) || !sameDate(got.EndDate, want.EndDate) ||
+			!sameDate(got.TrialEndDate, want.TrialEndDate) || got.Seats != want.Seats {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	}
+}
+
+func testList(t *testing.T, repo storage.Repository) {
+	userID := uuid.New()
+	mustPost(t, repo,
+		subscription(userID, "Spotify", 200, "10-2025"),
+		subscription(userID, "Netflix", 400, "09-2025"),
+		subscription(uuid.New(), "Yandex Plus", 300, "09-2025"),
+	)
+
+	subs, err := repo.GetSubscriptionsByUserID(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		names = append(names, sub.ServiceName)
+	}
+
+	if !slices.Equal(names, []string{"Netflix", "Spotify"}) {
+		t.Errorf("expected Netflix and Spotify ordered by start date, got %v", names)
+	}
+}
+
+func testListEmpty(t *testing.T, repo storage.Repository) {
+	subs, err := repo.GetSubscriptionsByUserID(context.Background(), uuid.New())
+	if err != nil || len(subs) != 0 {
+		t.Errorf("expected no subscriptions and no error, got %+v and %v", subs, err)
+	}
+}
+
+func testPostUnique(t *testing.T, repo storage.Repository) {
+	userID := uuid.New()
+	sub := subscription(userID, "Netflix", 400, "09-2025")
+	mustPost(t, repo, sub)
+
+	if _, err := repo.PostSubscription(context.Background(), sub); !errors.Is(err, models.ErrUnique) {
+		t.Fatalf("expected ErrUnique, got %v", err)
+	}
+
+	// Any difference in the key makes another subscription.
+	mustPost(t, repo,
+		subscription(userID, "Netflix", 500, "09-2025"),
+		subscription(userID, "Netflix", 400, "10-2025"),
+		subscription(userID, "Spotify", 400, "09-2025"),
+		subscription(uuid.New(), "Netflix", 400, "09-2025"),
+	)
+}
+
+func testUpdate(t *testing.T, repo storage.Repository) {
+	userID := uuid.New()
+	id := mustPost(t, repo, subscription(userID, "Netflix", 400, "09-2025"))[0]
+
+	update := subscription(userID, "Netflix", 600, "10-2025")
+	update.ID, update.BillingPeriod, update.Seats = id, "yearly", 3
+
+	if err := repo.UpdateSubscription(context.Background(), update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := mustGet(t, repo, id)
+	if got.Price != 600 || got.BillingPeriod != "yearly" || got.Seats != 3 || !got.StartDate.Equal(month("10-2025")) {
+		t.Errorf("update not applied, got %+v", got)
+	}
+}
+
+func testUpdateUnique(t *testing.T, repo storage.Repository) {
+	userID := uuid.New()
+	ids := mustPost(t, repo,
+		subscription(userID, "Netflix", 400, "09-2025"),
+		subscription(userID, "Spotify", 200, "09-2025"),
+	)
+
+	update := subscription(userID, "Netflix", 400, "09-2025")
+	update.ID = ids[1]
+
+	if err := repo.UpdateSubscription(context.Background(), update); !errors.Is(err, models.ErrUnique) {
+		t.Fatalf("expected ErrUnique updating into an existing subscription, got %v", err)
+	}
+
+	// Updating a subscription to itself doesn't conflict.
+	update = subscription(userID, "Spotify", 200, "09-2025")
+	update.ID = ids[1]
+
+	if err := repo.UpdateSubscription(context.Background(), update); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func testDelete(t *testing.T, repo storage.Repository) {
+	id := mustPost(t, repo, subscription(uuid.New(), "Netflix", 400, "09-2025"))[0]
+
+	if err := repo.DeleteSubscription(context.Background(), id); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.DeleteSubscription(context.Background(), id); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting twice, got %v", err)
+	}
+
+	if _, err := repo.GetSubscription(context.Background(), id); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func testNotFound(t *testing.T, repo storage.Repository) {
+	if _, err := repo.GetSubscription(context.Background(), missingID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("get: expected ErrNotFound, got %v", err)
+	}
+
+	if err := repo.DeleteSubscription(context.Background(), missingID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("delete: expected ErrNotFound, got %v", err)
+	}
+
+	update := subscription(uuid.New(), "Netflix", 400, "09-2025")
+	update.ID = missingID
+
+	if err := repo.UpdateSubscription(context.Background(), update); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("update: expected ErrNotFound, got %v", err)
+	}
+}
+
+func testConcurrentPosts(t *testing.T, repo storage.Repository) {
+	const posts = 20
+
+	userID := uuid.New()
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 2*posts)
+
+	// Every subscription is posted twice at once, exactly one of each pair must win.
+	for i := range 2 * posts {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := repo.PostSubscription(context.Background(), subscription(userID, "Netflix", 100+i%posts, "09-2025"))
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	duplicates := 0
+
+	for err := range errs {
+		switch {
+		case errors.Is(err, models.ErrUnique):
+			duplicates++
+		case err != nil:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	subs, err := repo.GetSubscriptionsByUserID(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(subs) != posts || duplicates != posts {
+		t.Errorf("expected %d subscriptions and %d duplicates, got %d and %d", posts, posts, len(subs), duplicates)
+	}
+}
@@ -3,56 +3,169 @@ package app
 import (
 	"context"
 	"fmt"
-	"github.com/Ostmind/subscriptionservice/internal/storage/postgres"
+	"github.com/Ostmind/subscriptionservice/internal/storage"
+	"github.com/Ostmind/subscriptionservice/internal/storage/cache"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/budget"
 	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/health"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/lifecycle"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/notification"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/grpcserver"
 	srv "github.com/Ostmind/subscriptionservice/internal/subscription/server/server"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/service"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/tracing"
 	"log/slog"
+	"net"
+	"time"
 )
 
+// App is the container of the service. New builds the components from config, each registering a lifecycle
+// hook after the components it depends on, so Start brings them up in dependency order and Stop tears them
+// down in reverse.
 type App struct {
-	server *srv.Server
-	logger *slog.Logger
-	db     *postgres.Storage
+	lifecycle *lifecycle.Lifecycle
+	logger    *slog.Logger
+	server    *srv.Server
 }
 
-func New(logger *slog.Logger, cfg *config.AppConfig) (*App, error) {
-	db, err := postgres.New(cfg.DB)
+// New connects to the storage, waiting for it as cfg.DB.ConnectRetry allows, and migrates it as
+// cfg.DB.AutoMigrate says. Cancelling ctx gives up the wait.
+func New(ctx context.Context, logger *slog.Logger, cfg *config.AppConfig) (*App, error) {
+	lc := lifecycle.New(logger)
+
+	stopTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
 	if err != nil {
+		return nil, fmt.Errorf("couldn't set up tracing %w", err)
+	}
+
+	// Traces are flushed last, after every component that could still record spans.
+	lc.Append(lifecycle.Hook{Name: "tracing", OnStop: stopTracing})
+
+	backend, err := newBackend(ctx, cfg.DB, logger)
+	if err != nil {
+		_ = stopTracing(context.Background())
+
 		return nil, fmt.Errorf("couldn't establish db connection %w", err)
 	}
 
-	server := srv.New(db, logger)
+	db := backend.storage
+	lc.Append(lifecycle.Hook{Name: "storage", OnStop: func(context.Context) error {
+		db.Close()
 
-	return &App{
-		server: server,
-		logger: logger,
-		db:     db,
-	}, nil
-}
+		return nil
+	}})
 
-func (a App) Run(serverHost string, serverPort int) {
-	a.logger.Info("Starting app...")
+	if err := migrate(ctx, cfg.DB, cfg.Srv.MigrationPath, backend, logger); err != nil {
+		_ = lc.Stop(context.Background())
+
+		return nil, err
+	}
+
+	for _, hook := range backend.hooks {
+		lc.Append(hook)
+	}
+
+	checker := health.NewChecker(db, backend.version, cfg.Srv.ReadinessTimeout)
+
+	if repo, ok := db.(budget.UsageRepository); ok {
+		evaluator := budget.NewEvaluator(repo, notification.NewLogNotifier(logger), logger,
+			cfg.Budget.EvaluationInterval, cfg.Budget.Hysteresis)
+		checker.AddWorker("budget-evaluator", evaluator)
+		lc.Append(lifecycle.Worker("budget-evaluator", evaluator.Run))
+	}
+
+	var repo storage.Repository = db
+
+	if cfg.Cache.Enabled {
+		notifier, _ := db.(storage.NotifyRepository)
+		cached := cache.New(db, notifier, logger, cfg.Cache.Size, cfg.Cache.TTL, cfg.DB.ReconnectInterval)
+		lc.Append(lifecycle.Worker("cache-invalidation", cached.Run))
+
+		repo = cached
+	}
+
+	subscriptions := service.New(repo, service.NewLogPublisher(logger))
+
+	if shares, ok := db.(storage.HouseholdRepository); ok {
+		subscriptions.SetShares(shares)
+	}
+
+	if organizations, ok := db.(storage.OrganizationRepository); ok {
+		subscriptions.SetOrganizations(organizations)
+	}
+
+	server, err := srv.New(subscriptions, db, logger, cfg.Srv, cfg.RateLimit, checker, backend.collectors...)
+	if err != nil {
+		_ = lc.Stop(context.Background())
 
-	a.server.Run(serverHost, serverPort)
+		return nil, fmt.Errorf("couldn't create server %w", err)
+	}
+
+	lc.Append(lifecycle.Hook{
+		Name:    "http-server",
+		OnStart: func(context.Context) error { return server.Start() },
+		OnStop:  server.Stop,
+	})
+
+	if cfg.Srv.GRPCPort != 0 {
+		grpcServer := grpcserver.New(subscriptions, logger)
+
+		lc.Append(lifecycle.Hook{
+			Name:    "grpc-server",
+			OnStart: func(context.Context) error { return grpcServer.Start(cfg.Srv.Host, cfg.Srv.GRPCPort) },
+			OnStop:  grpcServer.Stop,
+		})
+	}
+
+	// Stopped first: readiness fails and the load balancers get drainDelay to notice before the servers go away.
+	lc.Append(lifecycle.Hook{Name: "readiness", OnStop: func(ctx context.Context) error {
+		checker.Shutdown()
+
+		return drain(ctx, logger, cfg.Srv.DrainDelay)
+	}})
+
+	return &App{lifecycle: lc, logger: logger, server: server}, nil
 }
 
-func (a App) Stop(ctx context.Context) {
-	a.logger.Info("Stopping app...")
+func drain(ctx context.Context, logger *slog.Logger, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
 
-	doneCh := make(chan error)
-	go func() {
-		doneCh <- a.server.Stop(ctx)
-	}()
+	logger.Info("Draining traffic", "Delay", delay)
 
 	select {
-	case err := <-doneCh:
-		if err != nil {
-			a.logger.Error("Error while stopping server: %v", slog.Any("error_details", err))
-		}
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
 
-		a.logger.Info("App has been stopped gracefully")
+// Start starts the components, undoing the started ones when one fails.
+func (a *App) Start(ctx context.Context) error {
+	a.logger.Info("Starting app...")
 
-	case <-ctx.Done():
-		a.logger.Warn("App stopped forced")
+	if err := a.lifecycle.Start(ctx); err != nil {
+		return fmt.Errorf("couldn't start app %w", err)
+	}
+
+	return nil
+}
+
+// Addr returns the address of the HTTP API, once started.
+func (a *App) Addr() net.Addr {
+	return a.server.Addr()
+}
+
+func (a *App) Stop(ctx context.Context) {
+	a.logger.Info("Stopping app...")
+
+	if err := a.lifecycle.Stop(ctx); err != nil {
+		a.logger.Error("Error while stopping app: %v", slog.Any("error_details", err))
+
+		return
 	}
+
+	a.logger.Info("App has been stopped gracefully")
 }
@@ -0,0 +1,126 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/app"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+)
+
+func TestAppOnMemoryStorage(t *testing.T) {
+	t.Parallel()
+
+	cfg, _, err := config.Load(nil, func(string) (string, bool) { return "", false })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.DB.Driver = config.DriverMemory
+	cfg.Srv.Host = "127.0.0.1"
+	cfg.Srv.Port = 0
+
+	application, err := app.New(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := application.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	base := "http://" + application.Addr().String()
+
+	resp, err := http.Post(base+"/subscription", "application/json", strings.NewReader(
+		`{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","start_date":"09-2025","price":400,"service_name":"Netflix"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected subscription to be created, got status %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(base + "/readyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected ready, got status %d", resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	application.Stop(ctx)
+
+	if _, err := http.Get(base + "/healthz"); err == nil {
+		t.Error("expected the server to be stopped")
+	}
+}
+
+func TestAppAutoMigrate(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "subscriptions.db")
+
+	newApp := func(mode string) (*app.App, error) {
+		cfg, _, err := config.Load(nil, func(string) (string, bool) { return "", false })
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cfg.DB.Driver = config.DriverSQLite
+		cfg.DB.Path = path
+		cfg.DB.AutoMigrate = mode
+		cfg.Srv.Host = "127.0.0.1"
+		cfg.Srv.Port = 0
+
+		return app.New(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), cfg)
+	}
+
+	if _, err := newApp(config.AutoMigrateCheck); !errors.Is(err, app.ErrSchemaBehind) {
+		t.Fatalf("expected an empty database to be refused, got %v", err)
+	}
+
+	application, err := newApp(config.AutoMigrateApply)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := application.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get("http://" + application.Addr().String() + "/readyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected ready after migrating, got status %d", resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	application.Stop(ctx)
+
+	application, err = newApp(config.AutoMigrateCheck)
+	if err != nil {
+		t.Fatalf("expected the migrated database to pass the check, got %v", err)
+	}
+
+	application.Stop(ctx)
+}
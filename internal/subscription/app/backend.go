@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage"
+	"github.com/Ostmind/subscriptionservice/internal/storage/memory"
+	"github.com/Ostmind/subscriptionservice/internal/storage/postgres"
+	"github.com/Ostmind/subscriptionservice/internal/storage/sqlite"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/lifecycle"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/metrics"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/migration"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Storage is what every backend provides. The optional features check for their own repositories.
+type Storage interface {
+	storage.Repository
+	storage.HealthRepository
+	Close()
+}
+
+// backend is an open storage with what comes along with it.
+type backend struct {
+	storage Storage
+	// version is the schema version the readiness check expects.
+	version    int64
+	collectors []prometheus.Collector
+	// hooks run the backend's own background work, started after and stopped before the storage closes.
+	hooks []lifecycle.Hook
+	// openSQL opens the connection the migrations run on in the dialect together with its release, it is
+	// nil for the backends without a schema.
+	openSQL func() (*sql.DB, func() error, error)
+	dialect goose.Dialect
+}
+
+type backendFactory func(ctx context.Context, cfg config.DatabaseConfig, logger *slog.Logger) (backend, error)
+
+// backends are the storages db.driver selects from.
+var backends = map[string]backendFactory{
+	config.DriverPostgres: newPostgresBackend,
+	config.DriverSQLite:   newSQLiteBackend,
+	config.DriverMemory:   newMemoryBackend,
+}
+
+func newBackend(ctx context.Context, cfg config.DatabaseConfig, logger *slog.Logger) (backend, error) {
+	factory, ok := backends[cfg.Driver]
+	if !ok {
+		return backend{}, fmt.Errorf("%w: %q", config.ErrUnknownDriver, cfg.Driver)
+	}
+
+	return factory(ctx, cfg, logger)
+}
+
+func newPostgresBackend(ctx context.Context, cfg config.DatabaseConfig, logger *slog.Logger) (backend, error) {
+	db, err := postgres.New(ctx, cfg, logger)
+	if err != nil {
+		return backend{}, err
+	}
+
+	return backend{
+		storage:    db,
+		version:    migration.Version,
+		collectors: []prometheus.Collector{metrics.NewPoolCollector(db.DB)},
+		hooks: []lifecycle.Hook{lifecycle.Worker("db-watcher", func(ctx context.Context) {
+			db.Watch(ctx, cfg.ReconnectInterval, logger)
+		})},
+		openSQL: func() (*sql.DB, func() error, error) {
+			return openPostgresSQL(cfg)
+		},
+		dialect: goose.DialectPostgres,
+	}, nil
+}
+
+// openPostgresSQL connects apart from the pool, as the migrator does, so the pool's statement timeout
+// doesn't cut a long migration short.
+func openPostgresSQL(cfg config.DatabaseConfig) (*sql.DB, func() error, error) {
+	connConfig, err := pgx.ParseConfig(cfg.ConnString())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sqlDB := stdlib.OpenDB(*connConfig)
+
+	return sqlDB, sqlDB.Close, nil
+}
+
+func newSQLiteBackend(ctx context.Context, cfg config.DatabaseConfig, logger *slog.Logger) (backend, error) {
+	db, err := sqlite.New(ctx, cfg, logger)
+	if err != nil {
+		return backend{}, err
+	}
+
+	// The migrations share the storage's connection, it stays open after them.
+	openSQL := func() (*sql.DB, func() error, error) {
+		return db.DB, func() error { return nil }, nil
+	}
+
+	return backend{storage: db, version: migration.SQLiteVersion, openSQL: openSQL, dialect: goose.DialectSQLite3}, nil
+}
+
+func newMemoryBackend(_ context.Context, _ config.DatabaseConfig, logger *slog.Logger) (backend, error) {
+	logger.Warn("Running on in-memory storage, the data is lost on restart")
+
+	return backend{storage: memory.New()}, nil
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/migration"
+)
+
+// ErrSchemaBehind is returned on startup with the check auto-migrate mode when migrations are pending.
+var ErrSchemaBehind = errors.New("database schema is behind the service")
+
+// migrate brings the schema of the backend to the version the binary expects as cfg.AutoMigrate says.
+func migrate(ctx context.Context, cfg config.DatabaseConfig, migrationPath string, backend backend,
+	logger *slog.Logger,
+) error {
+	if cfg.AutoMigrate == config.AutoMigrateOff || backend.openSQL == nil {
+		return nil
+	}
+
+	files, err := migration.Files(backend.dialect, migrationPath)
+	if err != nil {
+		return fmt.Errorf("couldn't read migrations %w", err)
+	}
+
+	sqlDB, closeSQL, err := backend.openSQL()
+	if err != nil {
+		return fmt.Errorf("couldn't open migration connection %w", err)
+	}
+
+	defer func() {
+		if err := closeSQL(); err != nil {
+			logger.Warn("Couldn't close migration connection", slog.Any("error_details", err))
+		}
+	}()
+
+	migrator, err := migration.NewMigrator(sqlDB, backend.dialect, files, cfg.MigrationLockTimeout)
+	if err != nil {
+		return fmt.Errorf("couldn't read migrations %w", err)
+	}
+
+	version, err := migrator.Version(ctx)
+	if err != nil {
+		return fmt.Errorf("couldn't read schema version %w", err)
+	}
+
+	if version >= backend.version {
+		return nil
+	}
+
+	if cfg.AutoMigrate == config.AutoMigrateCheck {
+		return fmt.Errorf("%w: version %d, expected %d, run the migrator or set db.auto-migrate to %s",
+			ErrSchemaBehind, version, backend.version, config.AutoMigrateApply)
+	}
+
+	logger.Info("Applying migrations", "From", version, "To", backend.version)
+
+	results, err := migrator.UpTo(ctx, backend.version)
+	if err != nil {
+		return fmt.Errorf("couldn't apply migrations %w", err)
+	}
+
+	for _, result := range results {
+		logger.Info("Migration applied", "Migration", result.Source.Path, "Duration", result.Duration)
+	}
+
+	return nil
+}
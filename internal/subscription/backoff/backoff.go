@@ -0,0 +1,73 @@
+package backoff
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff grows the delay between attempts from Initial by Multiplier up to Max. Jitter spreads every delay
+// randomly by up to that fraction either way, so replicas starting together don't retry in lockstep.
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	Jitter     float64
+
+	// Rand returns a number in [0, 1), rand.Float64 when nil.
+	Rand func() float64
+}
+
+// Delay returns the wait after the failed attempt, counted from 1.
+func (b Backoff) Delay(attempt int) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(b.Initial) * math.Pow(multiplier, float64(attempt-1))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+
+	if b.Jitter > 0 {
+		random := b.Rand
+		if random == nil {
+			random = rand.Float64
+		}
+
+		delay *= 1 + b.Jitter*(2*random()-1)
+	}
+
+	return time.Duration(delay)
+}
+
+// Retry calls fn until it succeeds, attempts calls have failed or ctx is done, and returns the last error.
+// notify, when set, is told about every failure followed by a retry and the delay before it.
+func Retry(ctx context.Context, b Backoff, attempts int, fn func(attempt int) error,
+	notify func(attempt int, err error, delay time.Duration),
+) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		if err = fn(attempt); err == nil || attempt == attempts {
+			return err
+		}
+
+		delay := b.Delay(attempt)
+		if notify != nil {
+			notify(attempt, err, delay)
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
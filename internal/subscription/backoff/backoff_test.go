@@ -0,0 +1,135 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/backoff"
+)
+
+func TestDelay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		backoff backoff.Backoff
+		attempt int
+		want    time.Duration
+	}{
+		{
+			name:    "first attempt waits initial",
+			backoff: backoff.Backoff{Initial: time.Second, Max: time.Minute, Multiplier: 2},
+			attempt: 1,
+			want:    time.Second,
+		},
+		{
+			name:    "grows exponentially",
+			backoff: backoff.Backoff{Initial: time.Second, Max: time.Minute, Multiplier: 2},
+			attempt: 4,
+			want:    8 * time.Second,
+		},
+		{
+			name:    "capped at max",
+			backoff: backoff.Backoff{Initial: time.Second, Max: 10 * time.Second, Multiplier: 2},
+			attempt: 10,
+			want:    10 * time.Second,
+		},
+		{
+			name: "jitter lowers the delay",
+			backoff: backoff.Backoff{Initial: time.Second, Max: time.Minute, Multiplier: 2, Jitter: 0.5,
+				Rand: func() float64 { return 0 }},
+			attempt: 2,
+			want:    time.Second,
+		},
+		{
+			name: "jitter raises the delay",
+			backoff: backoff.Backoff{Initial: time.Second, Max: time.Minute, Multiplier: 2, Jitter: 0.5,
+				Rand: func() float64 { return 0.75 }},
+			attempt: 2,
+			want:    2500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.backoff.Delay(tt.attempt); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	errUnavailable := errors.New("unavailable")
+	b := backoff.Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 2}
+
+	tests := []struct {
+		name         string
+		attempts     int
+		failures     int
+		wantErr      error
+		wantCalls    int
+		wantNotified int
+	}{
+		{name: "succeeds first time", attempts: 3, failures: 0, wantCalls: 1},
+		{name: "succeeds after retries", attempts: 3, failures: 2, wantCalls: 3, wantNotified: 2},
+		{name: "gives up", attempts: 3, failures: 5, wantErr: errUnavailable, wantCalls: 3, wantNotified: 2},
+		{name: "no retries", attempts: 0, failures: 5, wantErr: errUnavailable, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls, notified := 0, 0
+
+			err := backoff.Retry(context.Background(), b, tt.attempts, func(attempt int) error {
+				calls++
+				if attempt != calls {
+					t.Errorf("expected attempt %d, got %d", calls, attempt)
+				}
+
+				if calls <= tt.failures {
+					return errUnavailable
+				}
+
+				return nil
+			}, func(int, error, time.Duration) { notified++ })
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if calls != tt.wantCalls || notified != tt.wantNotified {
+				t.Errorf("expected %d calls and %d notifications, got %d and %d",
+					tt.wantCalls, tt.wantNotified, calls, notified)
+			}
+		})
+	}
+}
+
+func TestRetryStopsOnContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := backoff.Retry(ctx, backoff.Backoff{Initial: time.Hour}, 5, func(int) error {
+		calls++
+
+		return errors.New("unavailable")
+	}, nil)
+
+	if err == nil || calls != 1 {
+		t.Errorf("expected one failed call, got %d calls and error %v", calls, err)
+	}
+}
@@ -0,0 +1,126 @@
+package budget
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/notification"
+)
+
+const (
+	defaultInterval   = time.Hour
+	defaultHysteresis = 0.1
+)
+
+type UsageRepository interface {
+	GetBudgetUsage(ctx context.Context, month time.Time) ([]models.BudgetUsage, error)
+	SetBudgetAlertedMonth(ctx context.Context, id int, month *time.Time) error
+}
+
+// Evaluator periodically compares every budget with the projected cost of the current month.
+// An alert fires once per budget and month when the cost goes over the budget, and is re-armed
+// only after the cost drops below the budget reduced by the hysteresis fraction.
+type Evaluator struct {
+	repo       UsageRepository
+	notifier   notification.Notifier
+	logger     *slog.Logger
+	interval   time.Duration
+	hysteresis float64
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+func NewEvaluator(repo UsageRepository, notifier notification.Notifier, logger *slog.Logger,
+	interval time.Duration, hysteresis float64,
+) *Evaluator {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	if hysteresis <= 0 || hysteresis >= 1 {
+		hysteresis = defaultHysteresis
+	}
+
+	return &Evaluator{
+		repo:       repo,
+		notifier:   notifier,
+		logger:     logger,
+		interval:   interval,
+		hysteresis: hysteresis,
+	}
+}
+
+// Run evaluates the budgets right away and then on every interval until ctx is cancelled.
+func (e *Evaluator) Run(ctx context.Context) {
+	e.logger.Info("Starting budget evaluator", "Interval", e.interval)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		err := e.Evaluate(ctx, time.Now())
+		if err != nil {
+			e.logger.Error("Budget evaluation error", slog.Any("error_details", err))
+		}
+
+		e.mu.Lock()
+		e.lastErr = err
+		e.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			e.logger.Info("Budget evaluator stopped")
+
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Healthy returns the error of the last scheduled evaluation, nil once an evaluation succeeds again.
+func (e *Evaluator) Healthy() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.lastErr
+}
+
+// Evaluate runs a single pass over all budgets for the month of now.
+func (e *Evaluator) Evaluate(ctx context.Context, now time.Time) error {
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	usage, err := e.repo.GetBudgetUsage(ctx, month)
+	if err != nil {
+		return fmt.Errorf("budget evaluator: %w", err)
+	}
+
+	for _, u := range usage {
+		alerted := u.AlertedMonth != nil && u.AlertedMonth.Year() == month.Year() && u.AlertedMonth.Month() == month.Month()
+
+		switch {
+		case !alerted && u.Projected > u.Budget.Amount:
+			alert := models.BudgetAlert{Budget: u.Budget, Month: month, Projected: u.Projected}
+
+			if err := e.notifier.NotifyBudgetExceeded(ctx, alert); err != nil {
+				e.logger.Error("Budget alert notification error", "BudgetID", u.Budget.ID, slog.Any("error_details", err))
+
+				continue
+			}
+
+			if err := e.repo.SetBudgetAlertedMonth(ctx, u.Budget.ID, &month); err != nil {
+				return fmt.Errorf("budget evaluator: %w", err)
+			}
+		case alerted && float64(u.Projected) <= float64(u.Budget.Amount)*(1-e.hysteresis):
+			if err := e.repo.SetBudgetAlertedMonth(ctx, u.Budget.ID, nil); err != nil {
+				return fmt.Errorf("budget evaluator: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
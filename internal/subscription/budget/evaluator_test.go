@@ -0,0 +1,78 @@
+package budget_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/budget"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+)
+
+type fakeRepository struct {
+	projected    int
+	alertedMonth *time.Time
+}
+
+func (r *fakeRepository) GetBudgetUsage(_ context.Context, _ time.Time) ([]models.BudgetUsage, error) {
+	return []models.BudgetUsage{{
+		Budget:       models.BudgetJSON{ID: 1, Scope: models.BudgetScopeTotal, Amount: 1000},
+		Projected:    r.projected,
+		AlertedMonth: r.alertedMonth,
+	}}, nil
+}
+
+func (r *fakeRepository) SetBudgetAlertedMonth(_ context.Context, _ int, month *time.Time) error {
+	r.alertedMonth = month
+
+	return nil
+}
+
+type fakeNotifier struct {
+	alerts []models.BudgetAlert
+}
+
+func (n *fakeNotifier) NotifyBudgetExceeded(_ context.Context, alert models.BudgetAlert) error {
+	n.alerts = append(n.alerts, alert)
+
+	return nil
+}
+
+func TestEvaluatorHysteresis(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeRepository{}
+	notifier := &fakeNotifier{}
+	evaluator := budget.NewEvaluator(repo, notifier, slog.Default(), time.Minute, 0.1)
+
+	now := time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name       string
+		projected  int
+		now        time.Time
+		wantAlerts int
+	}{
+		{name: "UnderBudget", projected: 900, now: now, wantAlerts: 0},
+		{name: "OverBudget", projected: 1200, now: now, wantAlerts: 1},
+		{name: "StillOverBudget", projected: 1300, now: now, wantAlerts: 1},
+		{name: "InsideHysteresisBand", projected: 950, now: now, wantAlerts: 1},
+		{name: "OverBudgetAgainWithoutRearm", projected: 1100, now: now, wantAlerts: 1},
+		{name: "BelowHysteresisBand", projected: 800, now: now, wantAlerts: 1},
+		{name: "OverBudgetAfterRearm", projected: 1100, now: now, wantAlerts: 2},
+		{name: "NextMonth", projected: 1100, now: now.AddDate(0, 1, 0), wantAlerts: 3},
+	}
+
+	for _, step := range steps {
+		repo.projected = step.projected
+
+		if err := evaluator.Evaluate(context.Background(), step.now); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(notifier.alerts) != step.wantAlerts {
+			t.Fatalf("%s: expected %d alerts, got %d", step.name, step.wantAlerts, len(notifier.alerts))
+		}
+	}
+}
@@ -5,60 +5,207 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"os"
+	"strings"
 	"time"
-
-	"gopkg.in/yaml.v3"
 )
 
 type AppConfig struct {
-	Srv      ServerConfig   `yaml:"server"`
-	DB       DatabaseConfig `yaml:"db"`
-	LogLevel string         `yaml:"env"`
+	Srv       ServerConfig    `yaml:"server"`
+	DB        DatabaseConfig  `yaml:"db"`
+	Budget    BudgetConfig    `yaml:"budget"`
+	Cache     CacheConfig     `yaml:"cache"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	RateLimit RateLimitConfig `yaml:"rate-limit"`
+	LogLevel  string          `yaml:"env"`
 }
 
 type ServerConfig struct {
 	Host                  string        `yaml:"host"`
 	Port                  int           `yaml:"port"`
+	GRPCPort              int           `yaml:"grpc-port"`
+	AdminPort             int           `yaml:"admin-port"`
 	ServerReadTimeout     time.Duration `yaml:"read-timeout"`
 	ServerWriteTimeout    time.Duration `yaml:"write-timeout"`
 	ServerShutdownTimeout time.Duration `yaml:"shutdown-timeout"`
+	ServerIdleTimeout     time.Duration `yaml:"idle-timeout"`
+	ReadHeaderTimeout     time.Duration `yaml:"read-header-timeout"`
+	MaxHeaderBytes        int           `yaml:"max-header-bytes"`
+	MaxBodyBytes          int64         `yaml:"max-body-bytes"`
+	ReadinessTimeout      time.Duration `yaml:"readiness-timeout"`
+	DrainDelay            time.Duration `yaml:"drain-delay"`
+	QueryTimeout          time.Duration `yaml:"query-timeout"`
 	MigrationPath         string        `yaml:"migration"`
+	GraphQLMaxDepth       int           `yaml:"graphql-max-depth"`
+	GraphQLMaxComplexity  int           `yaml:"graphql-max-complexity"`
+	TLS                   TLSConfig     `yaml:"tls"`
+	// TrustedProxies are the CIDRs of the proxies in front of the service. The client IP is taken from the
+	// X-Forwarded-For header set by them, without them it is the address of the connection.
+	TrustedProxies []string `yaml:"trusted-proxies"`
+}
+
+// TLSConfig turns on HTTPS when CertFile and KeyFile are set. With ClientCAFile every client must present
+// a certificate signed by that CA. RedirectPort opens a plain HTTP listener redirecting to HTTPS.
+type TLSConfig struct {
+	CertFile     string `yaml:"cert-file"`
+	KeyFile      string `yaml:"key-file"`
+	ClientCAFile string `yaml:"client-ca-file"`
+	RedirectPort int    `yaml:"redirect-port"`
+}
+
+func (cfg TLSConfig) Enabled() bool {
+	return cfg.CertFile != "" && cfg.KeyFile != ""
 }
 
+// Storage backends supported in DatabaseConfig.Driver.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// DatabaseConfig is either a complete DSN, in key=value or postgres:// URL form, or the separate connection fields.
 type DatabaseConfig struct {
+	// Driver selects the storage backend. The memory one keeps the subscriptions in process memory and
+	// loses them on restart, it lets the service run without a database.
+	Driver string `yaml:"driver"`
+	// Path is the database file of the sqlite driver.
+	Path       string `yaml:"path"`
+	DSN        string `yaml:"dsn"`
 	Host       string `yaml:"host"`
 	Port       string `yaml:"port"`
 	DBName     string `yaml:"db-name"`
 	DBUser     string `yaml:"db-user"`
 	DBPassword string `yaml:"db-password"`
 	DBSSLMode  string `yaml:"db-ssl-mode"`
+
+	MaxConns          int           `yaml:"max-conns"`
+	MinConns          int           `yaml:"min-conns"`
+	MaxConnLifetime   time.Duration `yaml:"max-conn-lifetime"`
+	MaxConnIdleTime   time.Duration `yaml:"max-conn-idle-time"`
+	HealthCheckPeriod time.Duration `yaml:"health-check-period"`
+	StatementTimeout  time.Duration `yaml:"statement-timeout"`
+	ApplicationName   string        `yaml:"application-name"`
+	PingTimeout       time.Duration `yaml:"ping-timeout"`
+
+	ConnectRetry RetryConfig `yaml:"connect-retry"`
+	// ReconnectInterval is how often the DB is pinged in the background, so a lost connection is
+	// re-established before requests need it.
+	ReconnectInterval time.Duration `yaml:"reconnect-interval"`
+
+	// AutoMigrate is what the service does about the pending migrations on startup, one of the AutoMigrate
+	// modes. MigrationLockTimeout is how long it waits for another replica migrating.
+	AutoMigrate          string        `yaml:"auto-migrate"`
+	MigrationLockTimeout time.Duration `yaml:"migration-lock-timeout"`
+
+	// Replicas are the DSNs of read-only replicas taking the heavy read queries off the primary. They are
+	// health-checked every ReconnectInterval and the primary serves the reads while none is healthy. For
+	// ReplicaStickiness after a user's write the reads of that user stay on the primary, as the replicas lag.
+	// The writes are remembered by the instance serving them only: with several instances the load balancer
+	// must keep a user on one of them, or the user may not see their writes for the replication lag.
+	Replicas          []string      `yaml:"replicas"`
+	ReplicaStickiness time.Duration `yaml:"replica-stickiness"`
+}
+
+// Modes of DatabaseConfig.AutoMigrate.
+const (
+	// AutoMigrateOff leaves the migrations to the migrator, the readiness check fails until they are applied.
+	AutoMigrateOff = "off"
+	// AutoMigrateCheck refuses to start when the schema is behind the binary.
+	AutoMigrateCheck = "check"
+	// AutoMigrateApply applies the pending migrations before serving.
+	AutoMigrateApply = "apply"
+)
+
+// RetryConfig retries the first DB connection up to Attempts times, waiting InitialInterval and then
+// Multiplier times longer after every failure, up to MaxInterval. Jitter spreads every wait by that fraction.
+type RetryConfig struct {
+	Attempts        int           `yaml:"attempts"`
+	InitialInterval time.Duration `yaml:"initial-interval"`
+	MaxInterval     time.Duration `yaml:"max-interval"`
+	Multiplier      float64       `yaml:"multiplier"`
+	Jitter          float64       `yaml:"jitter"`
 }
 
+type BudgetConfig struct {
+	EvaluationInterval time.Duration `yaml:"evaluation-interval"`
+	Hysteresis         float64       `yaml:"hysteresis"`
+}
+
+// CacheConfig caches the subscription lists, and so the costs, of the users in process memory. Up to Size
+// lists are kept for TTL each, a change to the subscriptions of a user drops theirs in every replica.
+type CacheConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Size    int           `yaml:"size"`
+	TTL     time.Duration `yaml:"ttl"`
+}
+
+// TracingConfig selects where the spans are exported: "otlp" (gRPC collector at Endpoint), "stdout" or "none".
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	ServiceName string  `yaml:"service-name"`
+	SampleRatio float64 `yaml:"sample-ratio"`
+}
+
+// RateLimitConfig sets the token buckets applied to every API request. A client is limited by its IP and, when
+// the request carries the userId cookie, by that user. Routes, keyed by "METHOD /path/template", get a bucket shared by all
+// clients. Backend is "memory" for per-replica limits or "postgres" for limits shared by all replicas.
+type RateLimitConfig struct {
+	Enabled bool                   `yaml:"enabled"`
+	Backend string                 `yaml:"backend"`
+	PerIP   LimitConfig            `yaml:"per-ip"`
+	PerUser LimitConfig            `yaml:"per-user"`
+	Routes  map[string]LimitConfig `yaml:"routes"`
+}
+
+// LimitConfig is a bucket of Burst requests refilled with Rate requests per second, zero disables it.
+type LimitConfig struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+// MustNew loads the config from the defaults, the YAML file, the SUBSCRIPTION_* environment variables and the
+// command line flags, each layer overriding the previous one. With --print-config it prints the effective config
+// with the secrets redacted and exits, with a non-zero code when the config is invalid.
 func MustNew() *AppConfig {
-	configPath, err := fetchConfigPath()
-	if err != nil {
-		log.Fatalf("error fetching config file: %v", err)
+	cfg, _ := MustNewWithArgs()
+
+	return cfg
+}
+
+// MustNewWithArgs is MustNew for the commands taking arguments, it also returns the command line arguments
+// left after the flags.
+func MustNewWithArgs() (*AppConfig, []string) {
+	cfg, printConfig, args, err := load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
 	}
 
-	data, err := os.ReadFile(configPath)
 	if err != nil {
-		log.Fatalf("error reading config file: %v", err)
+		log.Fatalf("error loading config: %v", err)
 	}
 
-	var cfg AppConfig
+	errs := cfg.Validate()
 
-	err = yaml.Unmarshal(data, &cfg)
-	if err != nil {
-		log.Fatalf("error unmarshaling YAML: %v", err)
+	if printConfig {
+		fmt.Print(cfg.Redacted())
+
+		if errs != nil {
+			fmt.Fprintf(os.Stderr, "config is invalid: %s\n", errs.Error())
+			os.Exit(1)
+		}
+
+		os.Exit(0)
 	}
 
-	errs := cfg.Validate()
 	if errs != nil {
 		log.Fatalf("err validating config: %s", errs.Error())
 	}
 
-	return &cfg
+	return cfg, args
 }
 
 func (cfg *AppConfig) Validate() (result error) {
@@ -70,6 +217,53 @@ func (cfg *AppConfig) Validate() (result error) {
 		result = errors.Join(result, ErrNoServerPort)
 	}
 
+	if (cfg.Srv.TLS.CertFile == "") != (cfg.Srv.TLS.KeyFile == "") {
+		result = errors.Join(result, ErrIncompleteTLS)
+	}
+
+	if !cfg.Srv.TLS.Enabled() && (cfg.Srv.TLS.ClientCAFile != "" || cfg.Srv.TLS.RedirectPort != 0) {
+		result = errors.Join(result, ErrNoTLS)
+	}
+
+	for _, proxy := range cfg.Srv.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			result = errors.Join(result, fmt.Errorf("%w: %q", ErrInvalidTrustedProxy, proxy))
+		}
+	}
+
+	if cfg.DB.Driver != DriverPostgres && cfg.RateLimit.Enabled && cfg.RateLimit.Backend == DriverPostgres {
+		result = errors.Join(result, ErrRateLimitNeedsPostgres)
+	}
+
+	if cfg.DB.Driver != DriverPostgres && len(cfg.DB.Replicas) > 0 {
+		result = errors.Join(result, ErrReplicasNeedPostgres)
+	}
+
+	switch cfg.DB.AutoMigrate {
+	case AutoMigrateOff, AutoMigrateCheck, AutoMigrateApply:
+	default:
+		result = errors.Join(result, fmt.Errorf("%w: %q", ErrUnknownAutoMigrate, cfg.DB.AutoMigrate))
+	}
+
+	switch cfg.DB.Driver {
+	case DriverPostgres:
+	case DriverSQLite:
+		if cfg.DB.Path == "" {
+			result = errors.Join(result, ErrNoDBPath)
+		}
+
+		return result
+	case DriverMemory:
+		return result
+	default:
+		return errors.Join(result, fmt.Errorf("%w: %q", ErrUnknownDriver, cfg.DB.Driver))
+	}
+
+	// A DSN carries all the connection settings, the separate fields are ignored.
+	if cfg.DB.DSN != "" {
+		return result
+	}
+
 	if cfg.DB.Host == "" {
 		result = errors.Join(result, ErrNoDBHost)
 	}
@@ -93,25 +287,27 @@ func (cfg *AppConfig) Validate() (result error) {
 	return result
 }
 
-func fetchConfigPath() (string, error) {
-	var path string
-
-	flag.StringVar(&path, "config", "", "path to config file")
-	flag.Parse()
-
-	if path == "" {
-		path = os.Getenv("CONFIG_PATH")
-
-		if path == "" {
-			return "", errors.New("config path is required")
-		}
+// ConnString returns the DSN when set, otherwise the key=value connection string built from the separate fields.
+func (db DatabaseConfig) ConnString() string {
+	if db.DSN != "" {
+		return db.DSN
 	}
 
-	return path, nil
+	return GetConnStr(db.Host, db.Port, db.DBUser, db.DBPassword, db.DBName, db.DBSSLMode)
 }
 
 func GetConnStr(host, port, user, password, dbName, sslMode string) string {
 	return fmt.Sprintf("host=%s port=%s user=%s "+
 		"password=%s dbname=%s sslmode=%s",
-		host, port, user, password, dbName, sslMode)
+		quoteConnValue(host), quoteConnValue(port), quoteConnValue(user),
+		quoteConnValue(password), quoteConnValue(dbName), quoteConnValue(sslMode))
+}
+
+// quoteConnValue quotes the values libpq would otherwise split or misread, such as passwords with spaces.
+func quoteConnValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
 }
@@ -3,11 +3,22 @@ package config
 import "errors"
 
 var (
-	ErrNoServerHost = errors.New("no server host provided")
-	ErrNoServerPort = errors.New("no server port provided")
-	ErrNoDBHost     = errors.New("no DB host provided")
-	ErrNoDBPort     = errors.New("no DB port provided")
-	ErrNoDBName     = errors.New("no DB name provided")
-	ErrNoDBUser     = errors.New("no DB user provided")
-	ErrNoDBPassword = errors.New("no DB password provided")
+	ErrNoServerHost  = errors.New("no server host provided")
+	ErrNoServerPort  = errors.New("no server port provided")
+	ErrNoDBHost      = errors.New("no DB host provided")
+	ErrNoDBPort      = errors.New("no DB port provided")
+	ErrNoDBName      = errors.New("no DB name provided")
+	ErrNoDBUser      = errors.New("no DB user provided")
+	ErrNoDBPassword  = errors.New("no DB password provided")
+	ErrIncompleteTLS = errors.New("TLS needs both cert file and key file")
+	ErrNoTLS         = errors.New("client CA and redirect port need TLS cert and key files")
+	ErrNoDBPath      = errors.New("no DB path provided")
+	ErrUnknownDriver = errors.New("unknown DB driver")
+
+	ErrInvalidTrustedProxy = errors.New("trusted proxy must be a CIDR")
+
+	ErrUnknownAutoMigrate = errors.New("unknown auto-migrate mode")
+
+	ErrRateLimitNeedsPostgres = errors.New("postgres rate limit backend needs the postgres DB driver")
+	ErrReplicasNeedPostgres   = errors.New("read replicas need the postgres DB driver")
 )
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the name of every environment variable overriding a config field. The rest of the name is
+// the YAML path of the field, upper-cased with dashes turned into underscores: server.read-timeout is set by
+// SUBSCRIPTION_SERVER_READ_TIMEOUT. With the _FILE suffix the variable names a file holding the value instead,
+// as Docker secrets are mounted.
+const EnvPrefix = "SUBSCRIPTION_"
+
+const fileEnvSuffix = "_FILE"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func defaults() AppConfig {
+	return AppConfig{
+		Srv: ServerConfig{
+			Host:                  "0.0.0.0",
+			Port:                  8080,
+			ServerReadTimeout:     5 * time.Second,
+			ServerWriteTimeout:    5 * time.Second,
+			ServerShutdownTimeout: 10 * time.Second,
+			ServerIdleTimeout:     60 * time.Second,
+			ReadHeaderTimeout:     2 * time.Second,
+			MaxHeaderBytes:        1 << 20,
+			MaxBodyBytes:          1 << 20,
+			ReadinessTimeout:      2 * time.Second,
+		},
+		DB: DatabaseConfig{
+			Driver:          DriverPostgres,
+			Port:            "5432",
+			DBSSLMode:       "prefer",
+			ApplicationName: "subscriptionservice",
+			PingTimeout:     5 * time.Second,
+			ConnectRetry: RetryConfig{
+				Attempts:        10,
+				InitialInterval: 500 * time.Millisecond,
+				MaxInterval:     10 * time.Second,
+				Multiplier:      2,
+				Jitter:          0.2,
+			},
+			ReconnectInterval:    5 * time.Second,
+			AutoMigrate:          AutoMigrateOff,
+			MigrationLockTimeout: time.Minute,
+			ReplicaStickiness:    5 * time.Second,
+		},
+		Cache: CacheConfig{
+			Size: 10000,
+			TTL:  time.Minute,
+		},
+		Tracing: TracingConfig{
+			Exporter: "none",
+		},
+		LogLevel: "prod",
+	}
+}
+
+// Load builds the config from args and the environment. Every field has a flag named after its YAML path,
+// such as -server.port, next to -config, the YAML file path, and -print-config.
+func Load(args []string, lookupEnv func(string) (string, bool)) (cfg *AppConfig, printConfig bool, err error) {
+	cfg, printConfig, _, err = load(args, lookupEnv)
+
+	return cfg, printConfig, err
+}
+
+// load is Load also returning the arguments left after the flags.
+func load(args []string, lookupEnv func(string) (string, bool)) (
+	cfg *AppConfig, printConfig bool, rest []string, err error,
+) {
+	var configPath string
+
+	flags := flag.NewFlagSet("subscriptionservice", flag.ContinueOnError)
+	flags.StringVar(&configPath, "config", "", "path to config file, CONFIG_PATH by default")
+	flags.BoolVar(&printConfig, "print-config", false, "print the effective config with secrets redacted and exit")
+
+	cfg = &AppConfig{}
+	*cfg = defaults()
+
+	overrides := make(map[string]string)
+
+	walkFields(reflect.ValueOf(cfg).Elem(), nil, func(path []string, field reflect.Value) {
+		name := strings.Join(path, ".")
+		flags.Func(name, fmt.Sprintf("overrides %s (%s)", name, field.Type()), func(value string) error {
+			overrides[name] = value
+
+			return nil
+		})
+	})
+
+	if err := flags.Parse(args); err != nil {
+		return nil, false, nil, err
+	}
+
+	if configPath == "" {
+		configPath, _ = lookupEnv("CONFIG_PATH")
+	}
+
+	if configPath != "" {
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			return nil, false, nil, fmt.Errorf("error reading config file: %w", err)
+		}
+
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, false, nil, fmt.Errorf("error unmarshaling YAML: %w", err)
+		}
+	}
+
+	var errs error
+
+	walkFields(reflect.ValueOf(cfg).Elem(), nil, func(path []string, field reflect.Value) {
+		value, ok, err := lookupValue(envName(path), lookupEnv)
+		if err == nil && ok {
+			err = setField(field, value)
+		}
+
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("%s: %w", envName(path), err))
+		}
+	})
+
+	walkFields(reflect.ValueOf(cfg).Elem(), nil, func(path []string, field reflect.Value) {
+		name := strings.Join(path, ".")
+		if value, ok := overrides[name]; ok {
+			if err := setField(field, value); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("-%s: %w", name, err))
+			}
+		}
+	})
+
+	if errs != nil {
+		return nil, false, nil, errs
+	}
+
+	return cfg, printConfig, flags.Args(), nil
+}
+
+func envName(path []string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(strings.Join(path, "_"), "-", "_"))
+}
+
+// lookupValue reads the variable name, or the file named by name_FILE. The file wins when both are set.
+func lookupValue(name string, lookupEnv func(string) (string, bool)) (string, bool, error) {
+	if path, ok := lookupEnv(name + fileEnvSuffix); ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("error reading secret file: %w", err)
+		}
+
+		return strings.TrimRight(string(data), "\r\n"), true, nil
+	}
+
+	value, ok := lookupEnv(name)
+
+	return value, ok, nil
+}
+
+// walkFields calls fn for every scalar or list field of the struct v with the YAML path of the field.
+// Maps, such as the per-route rate limits, can only be set from the file.
+func walkFields(v reflect.Value, path []string, fn func(path []string, field reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		tag := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		field := v.Field(i)
+		fieldPath := append(append([]string(nil), path...), tag)
+
+		switch {
+		case field.Kind() == reflect.Struct:
+			walkFields(field, fieldPath, fn)
+		case field.Kind() == reflect.Map:
+		default:
+			fn(fieldPath, field)
+		}
+	}
+}
+
+func setField(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config field type %s", field.Type())
+		}
+
+		// Lists are comma-separated, an empty value clears the list.
+		var items []string
+
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported config field type %s", field.Type())
+	}
+
+	return nil
+}
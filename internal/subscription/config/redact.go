@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "REDACTED"
+
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('(\\.|[^'])*'|\S+)`)
+
+// Redacted is the config as YAML with the database password hidden, including the ones inside the DSNs.
+func (cfg AppConfig) Redacted() string {
+	if cfg.DB.DBPassword != "" {
+		cfg.DB.DBPassword = redacted
+	}
+
+	cfg.DB.DSN = redactDSN(cfg.DB.DSN)
+
+	// The slice is shared with the caller's config, the redacted DSNs go to a copy.
+	if len(cfg.DB.Replicas) > 0 {
+		replicas := make([]string, len(cfg.DB.Replicas))
+		for i, dsn := range cfg.DB.Replicas {
+			replicas[i] = redactDSN(dsn)
+		}
+
+		cfg.DB.Replicas = replicas
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Sprintf("error marshaling config: %v", err)
+	}
+
+	return string(data)
+}
+
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+
+		query := u.Query()
+		if query.Has("password") {
+			query.Set("password", redacted)
+			u.RawQuery = query.Encode()
+		}
+
+		return u.String()
+	}
+
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+redacted)
+}
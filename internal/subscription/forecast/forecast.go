@@ -0,0 +1,177 @@
+package forecast
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+)
+
+const (
+	DefaultMonths = 12
+	MaxMonths     = 60
+)
+
+// Project calculates the charges of every subscription for the given number of months starting with from.
+// A subscription is charged every billing period counted from its start date, never before its trial ends
+// and never after its end date, at the price of the latest price change that is already in effect.
+func Project(subs []models.ForecastSubscription, from time.Time, months int) models.Forecast {
+	from = monthStart(from)
+
+	res := models.Forecast{
+		StartDate: from,
+		EndDate:   from.AddDate(0, months-1, 0),
+		Months:    make([]models.ForecastMonth, months),
+		Lines:     make([]models.ForecastLine, 0, len(subs)),
+	}
+
+	for i := range res.Months {
+		res.Months[i].Month = from.AddDate(0, i, 0)
+	}
+
+	for _, sub := range subs {
+		line := models.ForecastLine{
+			SubscriptionID: sub.ID,
+			ServiceName:    sub.ServiceName,
+			Currency:       sub.Currency,
+			Hypothetical:   sub.Hypothetical,
+			Charges:        []models.ForecastMonth{},
+		}
+
+		for i := range res.Months {
+			month := res.Months[i].Month
+
+			price, charged := chargeFor(sub, month)
+			if !charged {
+				continue
+			}
+
+			line.Charges = append(line.Charges, models.ForecastMonth{Month: month, Total: price})
+			line.Total += price
+			res.Months[i].Total += price
+			res.Total += price
+		}
+
+		if len(line.Charges) > 0 {
+			res.Lines = append(res.Lines, line)
+		}
+	}
+
+	sort.SliceStable(res.Lines, func(i, j int) bool {
+		return res.Lines[i].ServiceName < res.Lines[j].ServiceName
+	})
+
+	return res
+}
+
+// ApplyWhatIf removes the cancelled subscriptions and appends the hypothetical ones.
+func ApplyWhatIf(subs []models.ForecastSubscription, cancelIDs []int, cancelServices []string,
+	additions []models.ForecastSubscription,
+) []models.ForecastSubscription {
+	res := make([]models.ForecastSubscription, 0, len(subs)+len(additions))
+
+	for _, sub := range subs {
+		if slices.Contains(cancelIDs, sub.ID) || slices.Contains(cancelServices, sub.ServiceName) {
+			continue
+		}
+
+		res = append(res, sub)
+	}
+
+	for _, sub := range additions {
+		sub.Hypothetical = true
+		res = append(res, sub)
+	}
+
+	return res
+}
+
+func chargeFor(sub models.ForecastSubscription, month time.Time) (int, bool) {
+	start := monthStart(sub.StartDate)
+
+	if month.Before(start) {
+		return 0, false
+	}
+
+	if sub.EndDate != nil && month.After(monthStart(*sub.EndDate)) {
+		return 0, false
+	}
+
+	if sub.TrialEndDate != nil && month.Before(monthStart(*sub.TrialEndDate)) {
+		return 0, false
+	}
+
+	interval, ok := models.BillingPeriodMonths[sub.BillingPeriod]
+	if !ok {
+		interval = 1
+	}
+
+	if monthsBetween(start, month)%interval != 0 {
+		return 0, false
+	}
+
+	price := sub.Price
+
+	var effective time.Time
+
+	for _, change := range sub.PriceChanges {
+		changeMonth := monthStart(change.EffectiveDate)
+		if changeMonth.After(month) || changeMonth.Before(effective) {
+			continue
+		}
+
+		effective = changeMonth
+		price = change.Price
+	}
+
+	return price, true
+}
+
+func monthStart(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+// FromJSON converts a hypothetical subscription from a what-if request into a projectable one.
+func FromJSON(sub models.SubscriptionListJSON) (res models.ForecastSubscription, err error) {
+	res = models.ForecastSubscription{
+		ServiceName:   sub.ServiceName,
+		Price:         sub.Price,
+		Currency:      sub.Currency,
+		BillingPeriod: sub.BillingPeriod,
+	}
+
+	if res.Currency == "" {
+		res.Currency = "RUB"
+	}
+
+	if res.BillingPeriod == "" {
+		res.BillingPeriod = models.BillingMonthly
+	}
+
+	if _, ok := models.BillingPeriodMonths[res.BillingPeriod]; !ok {
+		return res, fmt.Errorf("unknown billing period %q", res.BillingPeriod)
+	}
+
+	res.StartDate, err = time.Parse(models.MonthLayout, sub.StartDate)
+	if err != nil {
+		return res, fmt.Errorf("parse start date: %w", err)
+	}
+
+	res.EndDate, err = models.ParseOptionalMonth(sub.EndDate)
+	if err != nil {
+		return res, fmt.Errorf("parse end date: %w", err)
+	}
+
+	res.TrialEndDate, err = models.ParseOptionalMonth(sub.TrialEndDate)
+	if err != nil {
+		return res, fmt.Errorf("parse trial end date: %w", err)
+	}
+
+	return res, nil
+}
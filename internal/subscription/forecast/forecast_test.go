@@ -0,0 +1,110 @@
+package forecast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/forecast"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+)
+
+func month(m time.Month, y int) time.Time {
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestProject(t *testing.T) {
+	t.Parallel()
+
+	end := month(time.March, 2026)
+	trialEnd := month(time.February, 2026)
+
+	tests := []struct {
+		name       string
+		sub        models.ForecastSubscription
+		wantTotal  int
+		wantMonths []int
+	}{
+		{
+			name:       "Monthly",
+			sub:        models.ForecastSubscription{ID: 1, Price: 100, BillingPeriod: models.BillingMonthly, StartDate: month(time.June, 2025)},
+			wantTotal:  600,
+			wantMonths: []int{100, 100, 100, 100, 100, 100},
+		},
+		{
+			name:       "Quarterly",
+			sub:        models.ForecastSubscription{ID: 1, Price: 300, BillingPeriod: models.BillingQuarterly, StartDate: month(time.November, 2025)},
+			wantTotal:  600,
+			wantMonths: []int{0, 300, 0, 0, 300, 0},
+		},
+		{
+			name: "EndDate",
+			sub: models.ForecastSubscription{ID: 1, Price: 100, BillingPeriod: models.BillingMonthly, StartDate: month(time.June, 2025),
+				EndDate: &end},
+			wantTotal:  300,
+			wantMonths: []int{100, 100, 100, 0, 0, 0},
+		},
+		{
+			name: "Trial",
+			sub: models.ForecastSubscription{ID: 1, Price: 100, BillingPeriod: models.BillingMonthly, StartDate: month(time.January, 2026),
+				TrialEndDate: &trialEnd},
+			wantTotal:  500,
+			wantMonths: []int{0, 100, 100, 100, 100, 100},
+		},
+		{
+			name: "PriceChange",
+			sub: models.ForecastSubscription{ID: 1, Price: 100, BillingPeriod: models.BillingMonthly, StartDate: month(time.June, 2025),
+				PriceChanges: []models.PriceChange{{EffectiveDate: month(time.April, 2026), Price: 150}}},
+			wantTotal:  750,
+			wantMonths: []int{100, 100, 100, 150, 150, 150},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := forecast.Project([]models.ForecastSubscription{tt.sub}, month(time.January, 2026), 6)
+
+			if res.Total != tt.wantTotal {
+				t.Errorf("expected total %d, got %d", tt.wantTotal, res.Total)
+			}
+
+			for i, want := range tt.wantMonths {
+				if res.Months[i].Total != want {
+					t.Errorf("month %d: expected %d, got %d", i, want, res.Months[i].Total)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyWhatIf(t *testing.T) {
+	t.Parallel()
+
+	subs := []models.ForecastSubscription{
+		{ID: 1, ServiceName: "Netflix", Price: 100, StartDate: month(time.January, 2025)},
+		{ID: 2, ServiceName: "Spotify", Price: 200, StartDate: month(time.January, 2025)},
+		{ID: 3, ServiceName: "iCloud", Price: 50, StartDate: month(time.January, 2025)},
+	}
+
+	added, err := forecast.FromJSON(models.SubscriptionListJSON{ServiceName: "Kinopoisk", Price: 300, StartDate: "03-2026"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := forecast.Project(forecast.ApplyWhatIf(subs, []int{1}, []string{"Spotify"}, []models.ForecastSubscription{added}),
+		month(time.January, 2026), 3)
+
+	if res.Total != 450 {
+		t.Errorf("expected total 450, got %d", res.Total)
+	}
+
+	if len(res.Lines) != 2 || !res.Lines[0].Hypothetical || res.Lines[0].ServiceName != "Kinopoisk" {
+		t.Errorf("unexpected lines %+v", res.Lines)
+	}
+
+	if len(subs) != 3 {
+		t.Errorf("what-if must not modify the original subscriptions")
+	}
+}
@@ -0,0 +1,113 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+)
+
+const defaultTimeout = 2 * time.Second
+
+// Worker is a background worker that can report the outcome of its last run.
+type Worker interface {
+	Healthy() error
+}
+
+// Checker decides whether the service should receive traffic. The database must answer a ping within the
+// timeout and be migrated at least to the version the binary expects. A failing background worker only degrades the
+// service, since the API keeps working without it.
+type Checker struct {
+	repo            storage.HealthRepository
+	expectedVersion int64
+	timeout         time.Duration
+
+	mu      sync.RWMutex
+	workers map[string]Worker
+
+	shuttingDown atomic.Bool
+}
+
+func NewChecker(repo storage.HealthRepository, expectedVersion int64, timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Checker{
+		repo:            repo,
+		expectedVersion: expectedVersion,
+		timeout:         timeout,
+		workers:         make(map[string]Worker),
+	}
+}
+
+func (c *Checker) AddWorker(name string, worker Worker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.workers[name] = worker
+}
+
+// Shutdown makes every following readiness check fail, so load balancers stop routing new requests.
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready runs the checks and reports whether the service is ready along with the result of every check.
+func (c *Checker) Ready(ctx context.Context) (models.HealthJSON, bool) {
+	if c.shuttingDown.Load() {
+		return models.HealthJSON{Status: models.HealthStatusShuttingDown}, false
+	}
+
+	res := models.HealthJSON{Status: models.HealthStatusOK, Checks: make(map[string]string)}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	ready := true
+
+	if err := c.repo.Ping(ctx); err != nil {
+		res.Checks["database"] = err.Error()
+		ready = false
+	} else {
+		res.Checks["database"] = models.HealthStatusOK
+	}
+
+	version, err := c.repo.GetMigrationVersion(ctx)
+
+	switch {
+	case err != nil:
+		res.Checks["migrations"] = err.Error()
+		ready = false
+	// A schema ahead of the binary is fine, as for the startup check: during a rolling deploy the migrator
+	// upgrades it while the instances of the previous build still serve.
+	case version < c.expectedVersion:
+		res.Checks["migrations"] = fmt.Sprintf("schema version %d, expected at least %d", version, c.expectedVersion)
+		ready = false
+	default:
+		res.Checks["migrations"] = models.HealthStatusOK
+	}
+
+	c.mu.RLock()
+	for name, worker := range c.workers {
+		if err := worker.Healthy(); err != nil {
+			res.Checks[name] = err.Error()
+			res.Status = models.HealthStatusDegraded
+
+			continue
+		}
+
+		res.Checks[name] = models.HealthStatusOK
+	}
+	c.mu.RUnlock()
+
+	if !ready {
+		res.Status = models.HealthStatusUnavailable
+	}
+
+	return res, ready
+}
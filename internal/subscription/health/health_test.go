@@ -0,0 +1,128 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/health"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+)
+
+type fakeRepository struct {
+	pingErr    error
+	version    int64
+	versionErr error
+	delay      time.Duration
+}
+
+func (r fakeRepository) Ping(ctx context.Context) error {
+	select {
+	case <-time.After(r.delay):
+		return r.pingErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r fakeRepository) GetMigrationVersion(_ context.Context) (int64, error) {
+	return r.version, r.versionErr
+}
+
+type fakeWorker struct {
+	err error
+}
+
+func (w fakeWorker) Healthy() error {
+	return w.err
+}
+
+func TestReady(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		repo       fakeRepository
+		worker     fakeWorker
+		wantReady  bool
+		wantStatus string
+		wantChecks map[string]string
+	}{
+		{
+			name:       "Ready",
+			repo:       fakeRepository{version: 6},
+			wantReady:  true,
+			wantStatus: models.HealthStatusOK,
+			wantChecks: map[string]string{"database": "ok", "migrations": "ok", "worker": "ok"},
+		},
+		{
+			name:       "DegradedWorker",
+			repo:       fakeRepository{version: 6},
+			worker:     fakeWorker{err: errors.New("budget evaluator: timeout")},
+			wantReady:  true,
+			wantStatus: models.HealthStatusDegraded,
+			wantChecks: map[string]string{"database": "ok", "migrations": "ok", "worker": "budget evaluator: timeout"},
+		},
+		{
+			name:       "SchemaBehind",
+			repo:       fakeRepository{version: 5},
+			wantReady:  false,
+			wantStatus: models.HealthStatusUnavailable,
+			wantChecks: map[string]string{"database": "ok", "migrations": "schema version 5, expected at least 6", "worker": "ok"},
+		},
+		{
+			name:       "SchemaAhead",
+			repo:       fakeRepository{version: 7},
+			wantReady:  true,
+			wantStatus: models.HealthStatusOK,
+			wantChecks: map[string]string{"database": "ok", "migrations": "ok", "worker": "ok"},
+		},
+		{
+			name:       "PingTimeout",
+			repo:       fakeRepository{version: 6, delay: time.Second},
+			wantReady:  false,
+			wantStatus: models.HealthStatusUnavailable,
+			wantChecks: map[string]string{"database": context.DeadlineExceeded.Error(), "migrations": "ok", "worker": "ok"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			checker := health.NewChecker(tt.repo, 6, 50*time.Millisecond)
+			checker.AddWorker("worker", tt.worker)
+
+			res, ready := checker.Ready(context.Background())
+
+			if ready != tt.wantReady || res.Status != tt.wantStatus {
+				t.Errorf("expected ready %v with status %s, got %v with %s", tt.wantReady, tt.wantStatus, ready, res.Status)
+			}
+
+			for name, want := range tt.wantChecks {
+				if res.Checks[name] != want {
+					t.Errorf("check %s: expected %q, got %q", name, want, res.Checks[name])
+				}
+			}
+		})
+	}
+}
+
+func TestReadyFailsAfterShutdown(t *testing.T) {
+	t.Parallel()
+
+	checker := health.NewChecker(fakeRepository{version: 6}, 6, time.Second)
+
+	if _, ready := checker.Ready(context.Background()); !ready {
+		t.Fatal("expected ready before shutdown")
+	}
+
+	checker.Shutdown()
+
+	res, ready := checker.Ready(context.Background())
+	if ready || res.Status != models.HealthStatusShuttingDown {
+		t.Errorf("expected shutting down, got ready %v with status %s", ready, res.Status)
+	}
+}
@@ -0,0 +1,210 @@
+// Package household splits the costs of the subscriptions shared within a household and settles them up.
+package household
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+const maxNameLength = 64
+
+// ValidateHousehold checks the name and adds the owner to the members when missing.
+func ValidateHousehold(household *models.HouseholdJSON) error {
+	if household.Name == "" || len(household.Name) > maxNameLength {
+		return fmt.Errorf("%w: name must be 1 to %d characters", models.ErrInvalidHousehold, maxNameLength)
+	}
+
+	if household.OwnerID == uuid.Nil {
+		return fmt.Errorf("%w: no owner_id", models.ErrInvalidHousehold)
+	}
+
+	for _, member := range household.Members {
+		if member == household.OwnerID {
+			return nil
+		}
+	}
+
+	household.Members = append(household.Members, household.OwnerID)
+
+	return nil
+}
+
+// ValidateShare checks the split rule of shared for a subscription of the price paid by payer. The shares
+// name every member but the payer once, the percentages add up to at most 100 and the fixed amounts to at
+// most the price.
+func ValidateShare(shared models.SharedSubscriptionJSON, payer uuid.UUID, price int) error {
+	if len(shared.Shares) == 0 {
+		return fmt.Errorf("%w: no shares", models.ErrInvalidShare)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(shared.Shares))
+	sum := 0
+
+	for _, share := range shared.Shares {
+		switch {
+		case share.UserID == uuid.Nil:
+			return fmt.Errorf("%w: no user_id", models.ErrInvalidShare)
+		case share.UserID == payer:
+			return fmt.Errorf("%w: the payer gets what the shares leave", models.ErrInvalidShare)
+		case seen[share.UserID]:
+			return fmt.Errorf("%w: user %s has more than one share", models.ErrInvalidShare, share.UserID)
+		case share.Value < 0:
+			return fmt.Errorf("%w: negative value", models.ErrInvalidShare)
+		}
+
+		seen[share.UserID] = true
+		sum += share.Value
+	}
+
+	switch shared.Split {
+	case models.SplitEqual:
+	case models.SplitPercentage:
+		if sum > 100 {
+			return fmt.Errorf("%w: percentages add up to %d", models.ErrInvalidShare, sum)
+		}
+	case models.SplitFixed:
+		if sum > price {
+			return fmt.Errorf("%w: amounts add up to %d, more than the price %d", models.ErrInvalidShare, sum, price)
+		}
+	default:
+		return fmt.Errorf("%w: unknown split %q", models.ErrInvalidShare, shared.Split)
+	}
+
+	return nil
+}
+
+// Split divides price between the payer and the members of shared. The parts of the members are rounded
+// down, the payer gets the rest. Fixed amounts validated against an earlier, higher price can add up to more
+// than price, so the members, ordered by user id, get at most what is left of it. The analytics query splits
+// the charges the same way.
+func Split(price int, shared models.SharedSubscription) map[uuid.UUID]int {
+	parts := make(map[uuid.UUID]int, len(shared.Shares)+1)
+	rest := price
+
+	shares := append([]models.ShareJSON(nil), shared.Shares...)
+	sort.Slice(shares, func(i, j int) bool { return bytes.Compare(shares[i].UserID[:], shares[j].UserID[:]) < 0 })
+
+	for _, share := range shares {
+		var part int
+
+		switch shared.Split {
+		case models.SplitEqual:
+			part = price / (len(shared.Shares) + 1)
+		case models.SplitPercentage:
+			part = price * share.Value / 100
+		case models.SplitFixed:
+			part = max(min(share.Value, rest), 0)
+		}
+
+		parts[share.UserID] = part
+		rest -= part
+	}
+
+	parts[shared.Subscription.UserID] += rest
+
+	return parts
+}
+
+// SettleUp returns the payments evening out what every member paid for the charges against their shares,
+// for every currency separately. Who owes the most pays who is owed the most first, so there are fewer
+// payments than members.
+func SettleUp(shared []models.SharedSubscription, charges []models.SharedCharge) []models.SettlementJSON {
+	rules := make(map[int]models.SharedSubscription, len(shared))
+	for _, s := range shared {
+		rules[s.Subscription.ID] = s
+	}
+
+	balances := make(map[string]map[uuid.UUID]int)
+
+	for _, charge := range charges {
+		rule, ok := rules[charge.SubscriptionID]
+		if !ok {
+			continue
+		}
+
+		if balances[charge.Currency] == nil {
+			balances[charge.Currency] = make(map[uuid.UUID]int)
+		}
+
+		balance := balances[charge.Currency]
+		balance[rule.Subscription.UserID] += charge.Price
+
+		for user, part := range Split(charge.Price, rule) {
+			balance[user] -= part
+		}
+	}
+
+	currencies := make([]string, 0, len(balances))
+	for currency := range balances {
+		currencies = append(currencies, currency)
+	}
+
+	sort.Strings(currencies)
+
+	settlements := []models.SettlementJSON{}
+
+	for _, currency := range currencies {
+		settlements = append(settlements, settle(balances[currency], currency)...)
+	}
+
+	return settlements
+}
+
+type balance struct {
+	user   uuid.UUID
+	amount int
+}
+
+func settle(balances map[uuid.UUID]int, currency string) (settlements []models.SettlementJSON) {
+	var creditors, debtors []balance
+
+	for user, amount := range balances {
+		switch {
+		case amount > 0:
+			creditors = append(creditors, balance{user, amount})
+		case amount < 0:
+			debtors = append(debtors, balance{user, -amount})
+		}
+	}
+
+	byAmount := func(b []balance) {
+		sort.Slice(b, func(i, j int) bool {
+			if b[i].amount != b[j].amount {
+				return b[i].amount > b[j].amount
+			}
+
+			return bytes.Compare(b[i].user[:], b[j].user[:]) < 0
+		})
+	}
+
+	byAmount(creditors)
+	byAmount(debtors)
+
+	for len(creditors) > 0 && len(debtors) > 0 {
+		amount := min(creditors[0].amount, debtors[0].amount)
+
+		settlements = append(settlements, models.SettlementJSON{
+			From:     debtors[0].user,
+			To:       creditors[0].user,
+			Amount:   amount,
+			Currency: currency,
+		})
+
+		creditors[0].amount -= amount
+		debtors[0].amount -= amount
+
+		if creditors[0].amount == 0 {
+			creditors = creditors[1:]
+		}
+
+		if debtors[0].amount == 0 {
+			debtors = debtors[1:]
+		}
+	}
+
+	return settlements
+}
@@ -0,0 +1,199 @@
+package household
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+var (
+	payer  = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	second = uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	third  = uuid.MustParse("33333333-3333-3333-3333-333333333333")
+)
+
+func shared(id int, split string, shares ...models.ShareJSON) models.SharedSubscription {
+	return models.SharedSubscription{
+		Subscription: models.Subscription{ID: id, UserID: payer},
+		Split:        split,
+		Shares:       shares,
+	}
+}
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		price  int
+		shared models.SharedSubscription
+		want   map[uuid.UUID]int
+	}{
+		{
+			name:   "equal, the payer gets the remainder",
+			price:  1000,
+			shared: shared(1, models.SplitEqual, models.ShareJSON{UserID: second}, models.ShareJSON{UserID: third}),
+			want:   map[uuid.UUID]int{payer: 334, second: 333, third: 333},
+		},
+		{
+			name:   "percentage",
+			price:  999,
+			shared: shared(1, models.SplitPercentage, models.ShareJSON{UserID: second, Value: 30}),
+			want:   map[uuid.UUID]int{payer: 700, second: 299},
+		},
+		{
+			name:   "fixed",
+			price:  1000,
+			shared: shared(1, models.SplitFixed, models.ShareJSON{UserID: second, Value: 150}, models.ShareJSON{UserID: third, Value: 250}),
+			want:   map[uuid.UUID]int{payer: 600, second: 150, third: 250},
+		},
+		{
+			name:   "fixed above a lowered price, the members get what is left in the order of their ids",
+			price:  300,
+			shared: shared(1, models.SplitFixed, models.ShareJSON{UserID: third, Value: 250}, models.ShareJSON{UserID: second, Value: 150}),
+			want:   map[uuid.UUID]int{payer: 0, second: 150, third: 150},
+		},
+		{
+			name:   "fixed on a free charge",
+			price:  0,
+			shared: shared(1, models.SplitFixed, models.ShareJSON{UserID: second, Value: 150}),
+			want:   map[uuid.UUID]int{payer: 0, second: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Split(tt.price, tt.shared)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+
+			for user, part := range tt.want {
+				if got[user] != part {
+					t.Errorf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateShare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		shared  models.SharedSubscriptionJSON
+		wantErr error
+	}{
+		{
+			name:   "percentage",
+			shared: models.SharedSubscriptionJSON{Split: models.SplitPercentage, Shares: []models.ShareJSON{{UserID: second, Value: 100}}},
+		},
+		{
+			name:    "percentages over 100",
+			shared:  models.SharedSubscriptionJSON{Split: models.SplitPercentage, Shares: []models.ShareJSON{{UserID: second, Value: 60}, {UserID: third, Value: 50}}},
+			wantErr: models.ErrInvalidShare,
+		},
+		{
+			name:    "fixed over the price",
+			shared:  models.SharedSubscriptionJSON{Split: models.SplitFixed, Shares: []models.ShareJSON{{UserID: second, Value: 1001}}},
+			wantErr: models.ErrInvalidShare,
+		},
+		{
+			name:    "payer share",
+			shared:  models.SharedSubscriptionJSON{Split: models.SplitEqual, Shares: []models.ShareJSON{{UserID: payer}}},
+			wantErr: models.ErrInvalidShare,
+		},
+		{
+			name:    "duplicate member",
+			shared:  models.SharedSubscriptionJSON{Split: models.SplitEqual, Shares: []models.ShareJSON{{UserID: second}, {UserID: second}}},
+			wantErr: models.ErrInvalidShare,
+		},
+		{
+			name:    "no shares",
+			shared:  models.SharedSubscriptionJSON{Split: models.SplitEqual},
+			wantErr: models.ErrInvalidShare,
+		},
+		{
+			name:    "unknown split",
+			shared:  models.SharedSubscriptionJSON{Split: "half", Shares: []models.ShareJSON{{UserID: second}}},
+			wantErr: models.ErrInvalidShare,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := ValidateShare(tt.shared, payer, 1000); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSettleUp(t *testing.T) {
+	t.Parallel()
+
+	july := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	// second pays for a subscription shared equally with payer, so both pay for something.
+	music := shared(2, models.SplitEqual, models.ShareJSON{UserID: payer})
+	music.Subscription.UserID = second
+
+	rules := []models.SharedSubscription{
+		shared(1, models.SplitPercentage, models.ShareJSON{UserID: second, Value: 50}, models.ShareJSON{UserID: third, Value: 25}),
+		music,
+		shared(3, models.SplitFixed, models.ShareJSON{UserID: third, Value: 5}),
+	}
+
+	charges := []models.SharedCharge{
+		{SubscriptionID: 1, Month: july, Price: 1000, Currency: "RUB"},
+		{SubscriptionID: 1, Month: july.AddDate(0, 1, 0), Price: 1000, Currency: "RUB"},
+		{SubscriptionID: 2, Month: july, Price: 300, Currency: "RUB"},
+		{SubscriptionID: 3, Month: july, Price: 10, Currency: "USD"},
+		{SubscriptionID: 4, Month: july, Price: 999, Currency: "RUB"},
+	}
+
+	// RUB: payer paid 2000 and owes 500+150, second paid 300 and owes 1000+150, third owes 500.
+	want := []models.SettlementJSON{
+		{From: second, To: payer, Amount: 850, Currency: "RUB"},
+		{From: third, To: payer, Amount: 500, Currency: "RUB"},
+		{From: third, To: payer, Amount: 5, Currency: "USD"},
+	}
+
+	got := SettleUp(rules, charges)
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestValidateHousehold(t *testing.T) {
+	t.Parallel()
+
+	household := models.HouseholdJSON{Name: "Семья", OwnerID: payer, Members: []uuid.UUID{second}}
+	if err := ValidateHousehold(&household); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(household.Members) != 2 || household.Members[1] != payer {
+		t.Errorf("expected the owner to be added to the members, got %v", household.Members)
+	}
+
+	if err := ValidateHousehold(&models.HouseholdJSON{OwnerID: payer}); !errors.Is(err, models.ErrInvalidHousehold) {
+		t.Errorf("expected %v, got %v", models.ErrInvalidHousehold, err)
+	}
+}
@@ -0,0 +1,124 @@
+// Package lifecycle starts the components of the app in dependency order and stops them in reverse.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Hook starts and stops one component. OnStart must not block, long running work goes to a goroutine that
+// OnStop ends. Both are optional.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle runs hooks in the order they were appended, so a component is appended after the ones it uses.
+type Lifecycle struct {
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+}
+
+func New(logger *slog.Logger) *Lifecycle {
+	return &Lifecycle{logger: logger}
+}
+
+func (l *Lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, hook)
+}
+
+// Start runs the OnStart hooks in order. When one fails, the components started before it are stopped and
+// the error is returned.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for ; l.started < len(l.hooks); l.started++ {
+		hook := l.hooks[l.started]
+		if hook.OnStart == nil {
+			continue
+		}
+
+		l.logger.Debug("Starting component", "component", hook.Name)
+
+		if err := hook.OnStart(ctx); err != nil {
+			return errors.Join(fmt.Errorf("start %s: %w", hook.Name, err), l.stop(ctx))
+		}
+	}
+
+	return nil
+}
+
+// Stop runs the OnStop hooks of the started components in reverse order. Every hook runs even when an
+// earlier one fails, the errors are joined.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.stop(ctx)
+}
+
+func (l *Lifecycle) stop(ctx context.Context) (result error) {
+	for ; l.started > 0; l.started-- {
+		hook := l.hooks[l.started-1]
+		if hook.OnStop == nil {
+			continue
+		}
+
+		l.logger.Debug("Stopping component", "component", hook.Name)
+
+		if err := hook.OnStop(ctx); err != nil {
+			result = errors.Join(result, fmt.Errorf("stop %s: %w", hook.Name, err))
+		}
+	}
+
+	return result
+}
+
+// Worker makes a hook of a background worker. run gets a context cancelled on stop, stopping waits for run
+// to return until ctx expires.
+func Worker(name string, run func(ctx context.Context)) Hook {
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+
+	return Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			var ctx context.Context
+
+			// The worker outlives the start context, it ends on stop only.
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan struct{})
+
+			go func() {
+				defer close(done)
+
+				run(ctx)
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
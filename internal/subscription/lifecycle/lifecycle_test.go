@@ -0,0 +1,153 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/lifecycle"
+)
+
+var errFailed = errors.New("failed")
+
+func newLifecycle() *lifecycle.Lifecycle {
+	return lifecycle.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// recorder appends the hook calls to calls, failing the ones named in fail.
+type recorder struct {
+	calls []string
+	fail  map[string]bool
+}
+
+func (r *recorder) hook(name string) lifecycle.Hook {
+	call := func(event string) func(context.Context) error {
+		return func(context.Context) error {
+			r.calls = append(r.calls, event+" "+name)
+			if r.fail[event+" "+name] {
+				return errFailed
+			}
+
+			return nil
+		}
+	}
+
+	return lifecycle.Hook{Name: name, OnStart: call("start"), OnStop: call("stop")}
+}
+
+func TestLifecycle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		fail         map[string]bool
+		wantStartErr bool
+		wantStopErr  bool
+		want         []string
+	}{
+		{
+			name: "starts in order and stops in reverse",
+			want: []string{"start storage", "start worker", "start server", "stop server", "stop worker", "stop storage"},
+		},
+		{
+			name:         "failed start stops the started components",
+			fail:         map[string]bool{"start server": true},
+			wantStartErr: true,
+			want:         []string{"start storage", "start worker", "start server", "stop worker", "stop storage"},
+		},
+		{
+			name:        "failed stop still stops the rest",
+			fail:        map[string]bool{"stop worker": true},
+			wantStopErr: true,
+			want:        []string{"start storage", "start worker", "start server", "stop server", "stop worker", "stop storage"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := &recorder{fail: tt.fail}
+			lc := newLifecycle()
+
+			for _, name := range []string{"storage", "worker", "server"} {
+				lc.Append(rec.hook(name))
+			}
+
+			if err := lc.Start(context.Background()); (err != nil) != tt.wantStartErr {
+				t.Fatalf("unexpected start error: %v", err)
+			}
+
+			if err := lc.Stop(context.Background()); (err != nil) != tt.wantStopErr {
+				t.Fatalf("unexpected stop error: %v", err)
+			}
+
+			if !slices.Equal(rec.calls, tt.want) {
+				t.Errorf("expected calls %v, got %v", tt.want, rec.calls)
+			}
+
+			// Stopping again finds nothing started.
+			if err := lc.Stop(context.Background()); err != nil || len(rec.calls) != len(tt.want) {
+				t.Errorf("expected a second stop to do nothing, got %v and calls %v", err, rec.calls)
+			}
+		})
+	}
+}
+
+func TestWorker(t *testing.T) {
+	t.Parallel()
+
+	stopped := make(chan struct{})
+	lc := newLifecycle()
+	lc.Append(lifecycle.Worker("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	}))
+
+	startCtx, cancel := context.WithCancel(context.Background())
+	if err := lc.Start(startCtx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The worker doesn't end with the start context.
+	cancel()
+
+	select {
+	case <-stopped:
+		t.Fatal("worker stopped with the start context")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := lc.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-stopped:
+	default:
+		t.Error("expected the worker to have returned")
+	}
+}
+
+func TestWorkerStopTimeout(t *testing.T) {
+	t.Parallel()
+
+	lc := newLifecycle()
+	lc.Append(lifecycle.Worker("stuck", func(context.Context) { select {} }))
+
+	if err := lc.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := lc.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+}
@@ -34,5 +34,5 @@ func SetupLogger(envType string) *slog.Logger {
 		)
 	}
 
-	return log
+	return slog.New(traceHandler{log.Handler()})
 }
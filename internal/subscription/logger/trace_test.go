@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceHandlerAddsTraceIDs(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	log := slog.New(traceHandler{slog.NewJSONHandler(&buf, nil)}).With("component", "test")
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	log.InfoContext(trace.ContextWithSpanContext(context.Background(), spanCtx), "traced")
+	log.Info("untraced")
+
+	dec := json.NewDecoder(&buf)
+
+	var traced, untraced map[string]any
+	if err := dec.Decode(&traced); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dec.Decode(&untraced); err != nil {
+		t.Fatal(err)
+	}
+
+	if traced["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || traced["span_id"] != "00f067aa0ba902b7" {
+		t.Errorf("missing trace ids in %v", traced)
+	}
+
+	if traced["component"] != "test" {
+		t.Errorf("lost attributes in %v", traced)
+	}
+
+	if _, ok := untraced["trace_id"]; ok {
+		t.Errorf("unexpected trace id in %v", untraced)
+	}
+}
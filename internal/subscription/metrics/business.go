@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const businessQueryTimeout = 5 * time.Second
+
+type businessCollector struct {
+	repo   storage.MetricsRepository
+	logger *slog.Logger
+	now    func() time.Time
+
+	activeSubscriptions *prometheus.Desc
+	monthlySpend        *prometheus.Desc
+	scrapeErrors        prometheus.Counter
+}
+
+// NewBusinessCollector exposes the active subscriptions and the monthly recurring spend per service. The values
+// are queried on every scrape, a failed query is logged and counted instead of failing the whole scrape.
+func NewBusinessCollector(repo storage.MetricsRepository, logger *slog.Logger) prometheus.Collector {
+	return newBusinessCollector(repo, logger, time.Now)
+}
+
+func newBusinessCollector(repo storage.MetricsRepository, logger *slog.Logger, now func() time.Time) *businessCollector {
+	return &businessCollector{
+		repo:   repo,
+		logger: logger,
+		now:    now,
+		activeSubscriptions: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "active_subscriptions"),
+			"Number of active subscriptions in the current month.", []string{"service_name"}, nil),
+		monthlySpend: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "monthly_recurring_spend"),
+			"Spend of the active subscriptions normalized to one month.", []string{"service_name", "currency"}, nil),
+		scrapeErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "business_metrics_errors_total",
+			Help:      "Number of failed business metrics queries.",
+		}),
+	}
+}
+
+func (c *businessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.activeSubscriptions
+	ch <- c.monthlySpend
+	c.scrapeErrors.Describe(ch)
+}
+
+func (c *businessCollector) Collect(ch chan<- prometheus.Metric) {
+	defer c.scrapeErrors.Collect(ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), businessQueryTimeout)
+	defer cancel()
+
+	spend, err := c.repo.GetServiceSpend(ctx, c.now())
+	if err != nil {
+		c.logger.Error("Error collecting business metrics", slog.Any("error_details", err))
+		c.scrapeErrors.Inc()
+
+		return
+	}
+
+	active := make(map[string]int, len(spend))
+
+	for _, s := range spend {
+		active[s.ServiceName] += s.Subscriptions
+		ch <- prometheus.MustNewConstMetric(c.monthlySpend, prometheus.GaugeValue, s.MonthlySpend, s.ServiceName, s.Currency)
+	}
+
+	for service, count := range active {
+		ch <- prometheus.MustNewConstMetric(c.activeSubscriptions, prometheus.GaugeValue, float64(count), service)
+	}
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type fakeRepository struct {
+	spend []models.ServiceSpend
+	err   error
+	month time.Time
+}
+
+func (r *fakeRepository) GetServiceSpend(_ context.Context, month time.Time) ([]models.ServiceSpend, error) {
+	r.month = month
+
+	return r.spend, r.err
+}
+
+func TestBusinessCollector(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	repo := &fakeRepository{spend: []models.ServiceSpend{
+		{ServiceName: "Netflix", Currency: "RUB", Subscriptions: 2, MonthlySpend: 1598},
+		{ServiceName: "Netflix", Currency: "USD", Subscriptions: 1, MonthlySpend: 15.5},
+		{ServiceName: "other", Currency: "RUB", Subscriptions: 3, MonthlySpend: 250},
+	}}
+
+	collector := newBusinessCollector(repo, slog.Default(), func() time.Time { return now })
+
+	expected := `
+# HELP subscription_active_subscriptions Number of active subscriptions in the current month.
+# TYPE subscription_active_subscriptions gauge
+subscription_active_subscriptions{service_name="Netflix"} 3
+subscription_active_subscriptions{service_name="other"} 3
+# HELP subscription_monthly_recurring_spend Spend of the active subscriptions normalized to one month.
+# TYPE subscription_monthly_recurring_spend gauge
+subscription_monthly_recurring_spend{currency="RUB",service_name="Netflix"} 1598
+subscription_monthly_recurring_spend{currency="USD",service_name="Netflix"} 15.5
+subscription_monthly_recurring_spend{currency="RUB",service_name="other"} 250
+`
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"subscription_active_subscriptions", "subscription_monthly_recurring_spend"); err != nil {
+		t.Error(err)
+	}
+
+	if !repo.month.Equal(now) {
+		t.Errorf("expected query for %s, got %s", now, repo.month)
+	}
+}
+
+func TestBusinessCollectorQueryError(t *testing.T) {
+	t.Parallel()
+
+	collector := newBusinessCollector(&fakeRepository{err: errors.New("connection refused")}, slog.Default(), time.Now)
+
+	expected := `
+# HELP subscription_business_metrics_errors_total Number of failed business metrics queries.
+# TYPE subscription_business_metrics_errors_total counter
+subscription_business_metrics_errors_total 1
+`
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE subscription
+    ADD COLUMN end_date DATE,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+CREATE TABLE service_catalog (
+                       service_name VARCHAR(64) PRIMARY KEY,
+                       category VARCHAR(64) NOT NULL
+);
+
+INSERT INTO service_catalog (service_name, category) VALUES
+    ('Netflix', 'video'),
+    ('Kinopoisk', 'video'),
+    ('YouTube Premium', 'video'),
+    ('Spotify', 'music'),
+    ('Apple Music', 'music'),
+    ('Yandex Music', 'music'),
+    ('Yandex Plus', 'bundle'),
+    ('iCloud', 'cloud-storage'),
+    ('Google One', 'cloud-storage'),
+    ('Dropbox', 'cloud-storage');
+
+-- +goose Down
+DROP TABLE service_catalog;
+
+ALTER TABLE subscription
+    DROP COLUMN currency,
+    DROP COLUMN end_date;
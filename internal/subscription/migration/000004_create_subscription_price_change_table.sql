@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE subscription
+    ADD COLUMN billing_period VARCHAR(16) NOT NULL DEFAULT 'monthly'
+        CHECK (billing_period IN ('monthly', 'quarterly', 'semiannual', 'yearly')),
+    ADD COLUMN trial_end_date DATE;
+
+CREATE TABLE subscription_price_change (
+                       id BIGSERIAL PRIMARY KEY,
+                       subscription_id BIGINT NOT NULL REFERENCES subscription (id) ON DELETE CASCADE,
+                       effective_date DATE NOT NULL,
+                       price INTEGER NOT NULL,
+                       created_at TIMESTAMP DEFAULT NOW(),
+                       UNIQUE (subscription_id, effective_date)
+);
+
+-- +goose Down
+DROP TABLE subscription_price_change;
+
+ALTER TABLE subscription
+    DROP COLUMN trial_end_date,
+    DROP COLUMN billing_period;
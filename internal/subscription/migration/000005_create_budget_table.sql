@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE budget (
+                       id BIGSERIAL PRIMARY KEY,
+                       user_id UUID NOT NULL,
+                       scope VARCHAR(16) NOT NULL DEFAULT 'total'
+                           CHECK (scope IN ('total', 'category', 'service')),
+                       scope_value VARCHAR(64) NOT NULL DEFAULT '',
+                       amount INTEGER NOT NULL CHECK (amount >= 0),
+                       alerted_month DATE,
+                       created_at TIMESTAMP DEFAULT NOW(),
+                       CONSTRAINT budget_constrain UNIQUE (user_id, scope, scope_value)
+);
+
+-- +goose Down
+DROP TABLE budget;
@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE service_catalog
+    ADD COLUMN price INTEGER;
+
+UPDATE service_catalog SET price = 799 WHERE service_name = 'Netflix';
+UPDATE service_catalog SET price = 399 WHERE service_name = 'Kinopoisk';
+UPDATE service_catalog SET price = 299 WHERE service_name = 'YouTube Premium';
+UPDATE service_catalog SET price = 199 WHERE service_name = 'Spotify';
+UPDATE service_catalog SET price = 169 WHERE service_name = 'Apple Music';
+UPDATE service_catalog SET price = 299 WHERE service_name = 'Yandex Music';
+UPDATE service_catalog SET price = 399 WHERE service_name = 'Yandex Plus';
+UPDATE service_catalog SET price = 149 WHERE service_name = 'iCloud';
+UPDATE service_catalog SET price = 139 WHERE service_name = 'Google One';
+UPDATE service_catalog SET price = 999 WHERE service_name = 'Dropbox';
+
+-- +goose Down
+ALTER TABLE service_catalog
+    DROP COLUMN price;
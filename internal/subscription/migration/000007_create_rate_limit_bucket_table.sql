@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE rate_limit_bucket (
+                       key VARCHAR(256) PRIMARY KEY,
+                       tokens DOUBLE PRECISION NOT NULL,
+                       updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX rate_limit_bucket_updated_at_idx ON rate_limit_bucket (updated_at);
+
+-- +goose Down
+DROP TABLE rate_limit_bucket;
@@ -0,0 +1,44 @@
+-- +goose Up
+CREATE TABLE household (
+                       id BIGSERIAL PRIMARY KEY,
+                       name VARCHAR(64) NOT NULL,
+                       owner_id UUID NOT NULL,
+                       created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE household_member (
+                       household_id BIGINT NOT NULL REFERENCES household (id) ON DELETE CASCADE,
+                       user_id UUID NOT NULL,
+                       created_at TIMESTAMP DEFAULT NOW(),
+                       PRIMARY KEY (household_id, user_id)
+);
+
+-- The payer is the user of the subscription. Sharing ends when the payer leaves the household.
+CREATE TABLE shared_subscription (
+                       subscription_id BIGINT PRIMARY KEY REFERENCES subscription (id) ON DELETE CASCADE,
+                       household_id BIGINT NOT NULL,
+                       payer_id UUID NOT NULL,
+                       split VARCHAR(16) NOT NULL CHECK (split IN ('equal', 'percentage', 'fixed')),
+                       created_at TIMESTAMP DEFAULT NOW(),
+                       FOREIGN KEY (household_id, payer_id)
+                           REFERENCES household_member (household_id, user_id) ON DELETE CASCADE
+);
+
+-- The shares of the members other than the payer, a member leaving the household loses them.
+CREATE TABLE subscription_share (
+                       subscription_id BIGINT NOT NULL REFERENCES shared_subscription (subscription_id) ON DELETE CASCADE,
+                       household_id BIGINT NOT NULL,
+                       user_id UUID NOT NULL,
+                       value INTEGER NOT NULL DEFAULT 0 CHECK (value >= 0),
+                       PRIMARY KEY (subscription_id, user_id),
+                       FOREIGN KEY (household_id, user_id)
+                           REFERENCES household_member (household_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX subscription_share_user_id_idx ON subscription_share (user_id);
+
+-- +goose Down
+DROP TABLE subscription_share;
+DROP TABLE shared_subscription;
+DROP TABLE household_member;
+DROP TABLE household;
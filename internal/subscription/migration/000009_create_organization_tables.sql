@@ -0,0 +1,45 @@
+-- +goose Up
+CREATE TABLE organization (
+                       id BIGSERIAL PRIMARY KEY,
+                       name VARCHAR(64) NOT NULL,
+                       created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE organization_member (
+                       organization_id BIGINT NOT NULL REFERENCES organization (id) ON DELETE CASCADE,
+                       user_id UUID NOT NULL,
+                       role VARCHAR(16) NOT NULL DEFAULT 'member' CHECK (role IN ('admin', 'member')),
+                       department VARCHAR(64) NOT NULL DEFAULT '',
+                       created_at TIMESTAMP DEFAULT NOW(),
+                       PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX organization_member_user_id_idx ON organization_member (user_id);
+
+-- The cost of a subscription is seats times its price. An organization-owned subscription is attributed to
+-- its department, or to the department of its user when it has none.
+ALTER TABLE subscription
+    ADD COLUMN seats INTEGER NOT NULL DEFAULT 1 CHECK (seats > 0),
+    ADD COLUMN organization_id BIGINT REFERENCES organization (id) ON DELETE SET NULL,
+    ADD COLUMN department VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX subscription_organization_id_idx ON subscription (organization_id);
+
+CREATE TABLE subscription_seat_change (
+                       id BIGSERIAL PRIMARY KEY,
+                       subscription_id BIGINT NOT NULL REFERENCES subscription (id) ON DELETE CASCADE,
+                       previous_seats INTEGER NOT NULL,
+                       seats INTEGER NOT NULL,
+                       changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX subscription_seat_change_subscription_id_idx ON subscription_seat_change (subscription_id, changed_at);
+
+-- +goose Down
+DROP TABLE subscription_seat_change;
+ALTER TABLE subscription
+    DROP COLUMN department,
+    DROP COLUMN organization_id,
+    DROP COLUMN seats;
+DROP TABLE organization_member;
+DROP TABLE organization;
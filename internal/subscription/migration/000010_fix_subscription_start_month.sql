@@ -0,0 +1,18 @@
+-- +goose Up
+-- The start dates were first parsed with the day-year layout 02-2006, so "09-2025" was stored as 2025-01-09.
+-- Every date is the first day of its month since MM-YYYY is parsed as such, move the old ones to the month they
+-- meant. A date whose move would duplicate another subscription of the user is left as is.
+UPDATE subscription s
+SET start_date = make_date(EXTRACT(YEAR FROM s.start_date)::int, EXTRACT(DAY FROM s.start_date)::int, 1)
+WHERE EXTRACT(MONTH FROM s.start_date) = 1
+  AND EXTRACT(DAY FROM s.start_date) BETWEEN 2 AND 12
+  AND NOT EXISTS (SELECT 1
+                  FROM subscription d
+                  WHERE d.user_id = s.user_id
+                    AND d.price = s.price
+                    AND d.service_name = s.service_name
+                    AND d.start_date = make_date(EXTRACT(YEAR FROM s.start_date)::int,
+                                                 EXTRACT(DAY FROM s.start_date)::int, 1));
+
+-- +goose Down
+-- The moved dates can't be told from the ones stored as MM-YYYY, they stay the first days of their months.
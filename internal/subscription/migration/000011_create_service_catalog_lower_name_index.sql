@@ -0,0 +1,7 @@
+-- +goose Up
+-- Subscriptions match their catalog entry whatever the case of the service name, so two entries differing only
+-- in case would both match and double the charges. The index also serves the lower() joins.
+CREATE UNIQUE INDEX service_catalog_lower_service_name_idx ON service_catalog (lower(service_name));
+
+-- +goose Down
+DROP INDEX service_catalog_lower_service_name_idx;
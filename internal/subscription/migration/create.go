@@ -0,0 +1,52 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+)
+
+const migrationTemplate = `-- +goose Up
+
+-- +goose Down
+`
+
+var nameSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create writes an empty migration named after name to dir, numbered after the latest one, and returns its
+// path. Version has to be bumped along with it.
+func Create(dir, name string) (string, error) {
+	name = strings.Trim(nameSeparators.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", errors.New("migration name is empty")
+	}
+
+	migrations, err := goose.CollectMigrations(dir, 0, goose.MaxVersion)
+	if err != nil && !errors.Is(err, goose.ErrNoMigrationFiles) {
+		return "", fmt.Errorf("collect migrations: %w", err)
+	}
+
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%06d_%s.sql", version, name))
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("create migration: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(migrationTemplate); err != nil {
+		return "", fmt.Errorf("create migration: %w", err)
+	}
+
+	return path, nil
+}
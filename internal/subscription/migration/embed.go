@@ -0,0 +1,34 @@
+package migration
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/pressly/goose/v3"
+)
+
+// files are the migrations built into the binary, so the service and the migrator don't need them on disk.
+//
+//go:embed *.sql sqlite/*.sql
+var files embed.FS
+
+// Files returns the migrations of dialect. They are read from dir when it is set, to try migrations out
+// without rebuilding, and are the embedded ones otherwise. dir holds the Postgres migrations, the SQLite ones
+// are in its SQLiteDir.
+func Files(dialect goose.Dialect, dir string) (fs.FS, error) {
+	if dir != "" {
+		if dialect == goose.DialectSQLite3 {
+			dir = filepath.Join(dir, SQLiteDir)
+		}
+
+		return os.DirFS(dir), nil
+	}
+
+	if dialect == goose.DialectSQLite3 {
+		return fs.Sub(files, SQLiteDir)
+	}
+
+	return files, nil
+}
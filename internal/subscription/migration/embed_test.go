@@ -0,0 +1,45 @@
+package migration_test
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/migration"
+	"github.com/pressly/goose/v3"
+)
+
+func TestEmbeddedFiles(t *testing.T) {
+	t.Parallel()
+
+	for _, dialect := range []goose.Dialect{goose.DialectPostgres, goose.DialectSQLite3} {
+		dialect := dialect
+		t.Run(string(dialect), func(t *testing.T) {
+			t.Parallel()
+
+			embedded, err := migration.Files(dialect, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			onDisk, err := migration.Files(dialect, ".")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, err := fs.Glob(onDisk, "*.sql")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := fs.Glob(embedded, "*.sql")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(want) == 0 || !slices.Equal(got, want) {
+				t.Errorf("expected the embedded migrations %v, got %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LockID is the key of the Postgres advisory lock held while migrating. Every replica uses it, so only one
+// of them migrates at a time.
+const LockID int64 = 7_328_401_975_112_633_821
+
+// lockPollInterval is how often a busy lock is tried again.
+const lockPollInterval = time.Second
+
+// ErrLocked is returned when another process held the migration lock for the whole timeout.
+var ErrLocked = errors.New("migration lock is held by another process")
+
+// lock takes the advisory lock on a connection of its own and returns the function releasing it. It gives up
+// with ErrLocked after timeout.
+func lock(ctx context.Context, db *sql.DB, timeout time.Duration) (unlock func() error, err error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migration lock: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var locked bool
+		if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, LockID).Scan(&locked); err != nil {
+			conn.Close()
+
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+				return nil, ErrLocked
+			}
+
+			return nil, fmt.Errorf("migration lock: %w", err)
+		}
+
+		if locked {
+			break
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			conn.Close()
+
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ErrLocked
+			}
+
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() error {
+		defer conn.Close()
+
+		// The lock belongs to the session, closing the connection would release it too, but the pool may keep
+		// the session open.
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, LockID); err != nil {
+			return fmt.Errorf("migration unlock: %w", err)
+		}
+
+		return nil
+	}, nil
+}
@@ -0,0 +1,13 @@
+// Package migration holds the goose SQL migrations of the subscription schema, the Postgres ones in this
+// directory and the SQLite ones in SQLiteDir.
+package migration
+
+// Version is the schema version this binary is built against, the number of the latest migration file.
+// Bump it together with every new migration, the readiness check compares it with the database.
+const Version int64 = 11
+
+// SQLiteDir is the directory of the SQLite migrations, relative to the Postgres ones.
+const SQLiteDir = "sqlite"
+
+// SQLiteVersion is Version for the SQLite schema.
+const SQLiteVersion int64 = 2
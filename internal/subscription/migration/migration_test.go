@@ -0,0 +1,38 @@
+package migration_test
+
+import (
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/migration"
+	"github.com/pressly/goose/v3"
+)
+
+func TestVersionMatchesLatestMigration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		dir     string
+		version int64
+	}{
+		{name: "postgres", dir: ".", version: migration.Version},
+		{name: "sqlite", dir: migration.SQLiteDir, version: migration.SQLiteVersion},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			migrations, err := goose.CollectMigrations(tt.dir, 0, goose.MaxVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			latest := migrations[len(migrations)-1].Version
+			if latest != tt.version {
+				t.Errorf("schema version is %d, but the latest migration is %d", tt.version, latest)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+// ErrNoMigrations is returned by Down and Redo when no migration is applied.
+var ErrNoMigrations = errors.New("no migration is applied")
+
+// Migrator runs the migrations of a directory against a database. On Postgres every change runs under the
+// advisory lock LockID, the SQLite database is a local file locking itself.
+type Migrator struct {
+	db          *sql.DB
+	dialect     goose.Dialect
+	fsys        fs.FS
+	provider    *goose.Provider
+	lockTimeout time.Duration
+}
+
+func NewMigrator(db *sql.DB, dialect goose.Dialect, fsys fs.FS, lockTimeout time.Duration) (*Migrator, error) {
+	provider, err := goose.NewProvider(dialect, db, fsys)
+	if err != nil {
+		return nil, fmt.Errorf("goose provider: %w", err)
+	}
+
+	return &Migrator{db: db, dialect: dialect, fsys: fsys, provider: provider, lockTimeout: lockTimeout}, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return m.UpTo(ctx, goose.MaxVersion)
+}
+
+// UpTo applies the pending migrations up to version, included.
+func (m *Migrator) UpTo(ctx context.Context, version int64) (results []*goose.MigrationResult, err error) {
+	err = m.locked(ctx, func() error {
+		results, err = m.provider.UpTo(ctx, version)
+
+		return err
+	})
+
+	return results, err
+}
+
+// Down rolls back the latest applied migration.
+func (m *Migrator) Down(ctx context.Context) (results []*goose.MigrationResult, err error) {
+	err = m.locked(ctx, func() error {
+		result, err := m.provider.Down(ctx)
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			return ErrNoMigrations
+		}
+
+		if result != nil {
+			results = append(results, result)
+		}
+
+		return err
+	})
+
+	return results, err
+}
+
+// DownTo rolls back the applied migrations newer than version, which stays applied. Version 0 rolls back
+// every migration.
+func (m *Migrator) DownTo(ctx context.Context, version int64) (results []*goose.MigrationResult, err error) {
+	err = m.locked(ctx, func() error {
+		results, err = m.provider.DownTo(ctx, version)
+
+		return err
+	})
+
+	return results, err
+}
+
+// Redo rolls back the latest applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) (results []*goose.MigrationResult, err error) {
+	err = m.locked(ctx, func() error {
+		version, err := m.provider.GetDBVersion(ctx)
+		if err != nil {
+			return err
+		}
+
+		if version == 0 {
+			return ErrNoMigrations
+		}
+
+		for _, direction := range []bool{false, true} {
+			result, err := m.provider.ApplyVersion(ctx, version, direction)
+			if result != nil {
+				results = append(results, result)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return results, err
+}
+
+// Status returns the state of every migration file.
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return m.provider.Status(ctx)
+}
+
+// Version returns the version of the latest applied migration, 0 for an empty database.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	return m.provider.GetDBVersion(ctx)
+}
+
+// PlanUp returns the SQL UpTo(version) would run, without running it.
+func (m *Migrator) PlanUp(ctx context.Context, version int64) ([]Script, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var scripts []Script
+
+	for _, status := range statuses {
+		if status.State == goose.StatePending && status.Source.Version <= version {
+			script, err := m.script(status.Source, true)
+			if err != nil {
+				return nil, err
+			}
+
+			scripts = append(scripts, script)
+		}
+	}
+
+	return scripts, nil
+}
+
+// PlanDown returns the SQL DownTo(version) would run, without running it. A negative version plans Down,
+// the rollback of the latest applied migration only.
+func (m *Migrator) PlanDown(ctx context.Context, version int64) ([]Script, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var scripts []Script
+
+	for _, status := range slices.Backward(statuses) {
+		if status.State != goose.StateApplied || (version >= 0 && status.Source.Version <= version) {
+			continue
+		}
+
+		script, err := m.script(status.Source, false)
+		if err != nil {
+			return nil, err
+		}
+
+		scripts = append(scripts, script)
+
+		if version < 0 {
+			break
+		}
+	}
+
+	return scripts, nil
+}
+
+// PlanRedo returns the SQL Redo would run, without running it.
+func (m *Migrator) PlanRedo(ctx context.Context) ([]Script, error) {
+	down, err := m.PlanDown(ctx, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(down) == 0 {
+		return nil, ErrNoMigrations
+	}
+
+	up, err := m.script(&goose.Source{Version: down[0].Version, Path: down[0].Path}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(down, up), nil
+}
+
+// Validate checks that every migration file can be run and that the database isn't at a version missing
+// from the files, as after running a newer build's migrations. The problems found are joined.
+func (m *Migrator) Validate(ctx context.Context) error {
+	var (
+		result   error
+		versions = make(map[int64]bool)
+	)
+
+	for _, source := range m.provider.ListSources() {
+		versions[source.Version] = true
+
+		if _, _, err := readSections(m.fsys, source.Path); err != nil {
+			result = errors.Join(result, err)
+		}
+	}
+
+	version, err := m.provider.GetDBVersion(ctx)
+	if err != nil {
+		return errors.Join(result, err)
+	}
+
+	if version != 0 && !versions[version] {
+		result = errors.Join(result, fmt.Errorf("%w: the database is at version %d, which has no migration file",
+			ErrInvalidMigration, version))
+	}
+
+	return result
+}
+
+func (m *Migrator) script(source *goose.Source, up bool) (Script, error) {
+	upSQL, downSQL, err := readSections(m.fsys, source.Path)
+	if err != nil {
+		return Script{}, err
+	}
+
+	if up {
+		return Script{Version: source.Version, Path: source.Path, Direction: "up", SQL: upSQL}, nil
+	}
+
+	return Script{Version: source.Version, Path: source.Path, Direction: "down", SQL: downSQL}, nil
+}
+
+// locked runs fn holding the migration lock.
+func (m *Migrator) locked(ctx context.Context, fn func() error) (err error) {
+	if m.dialect != goose.DialectPostgres {
+		return fn()
+	}
+
+	unlock, err := lock(ctx, m.db, m.lockTimeout)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err = errors.Join(err, unlock())
+	}()
+
+	return fn()
+}
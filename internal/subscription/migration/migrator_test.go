@@ -0,0 +1,234 @@
+package migration_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage/sqlite"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/migration"
+	"github.com/pressly/goose/v3"
+)
+
+var migrations = fstest.MapFS{
+	"000001_create_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id INTEGER);\n\n-- +goose Down\nDROP TABLE a;\n")},
+	"000002_create_b.sql": {Data: []byte("-- +goose Up\n-- +goose StatementBegin\nCREATE TABLE b (id INTEGER);\n" +
+		"-- +goose StatementEnd\n\n-- +goose Down\nDROP TABLE b;\n")},
+	"000003_create_c.sql": {Data: []byte("-- +goose Up\nCREATE TABLE c (id INTEGER);\n\n-- +goose Down\nDROP TABLE c;\n")},
+}
+
+func newMigrator(t *testing.T, fsys fstest.MapFS) *migration.Migrator {
+	t.Helper()
+
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migration.NewMigrator(db, goose.DialectSQLite3, fsys, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return migrator
+}
+
+func mustVersion(t *testing.T, migrator *migration.Migrator, want int64) {
+	t.Helper()
+
+	version, err := migrator.Version(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if version != want {
+		t.Errorf("expected version %d, got %d", want, version)
+	}
+}
+
+func scriptNames(scripts []migration.Script) string {
+	names := make([]string, 0, len(scripts))
+	for _, script := range scripts {
+		names = append(names, script.Direction+" "+script.Path)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func TestMigrator(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	migrator := newMigrator(t, migrations)
+
+	scripts, err := migrator.PlanUp(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := scriptNames(scripts); got != "up 000001_create_a.sql, up 000002_create_b.sql" {
+		t.Errorf("unexpected plan %s", got)
+	}
+
+	if !strings.Contains(scripts[1].SQL, "CREATE TABLE b") || strings.Contains(scripts[1].SQL, "DROP") {
+		t.Errorf("expected the Up section only, got %q", scripts[1].SQL)
+	}
+
+	mustVersion(t, migrator, 0)
+
+	if _, err := migrator.UpTo(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	mustVersion(t, migrator, 2)
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mustVersion(t, migrator, 3)
+
+	if results, err := migrator.Up(ctx); err != nil || len(results) != 0 {
+		t.Errorf("expected nothing to apply, got %v and %v", results, err)
+	}
+
+	if scripts, err = migrator.PlanRedo(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := scriptNames(scripts); got != "down 000003_create_c.sql, up 000003_create_c.sql" {
+		t.Errorf("unexpected redo plan %s", got)
+	}
+
+	if results, err := migrator.Redo(ctx); err != nil || len(results) != 2 {
+		t.Fatalf("expected a rollback and an apply, got %v and %v", results, err)
+	}
+
+	mustVersion(t, migrator, 3)
+
+	if scripts, err = migrator.PlanDown(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := scriptNames(scripts); got != "down 000003_create_c.sql, down 000002_create_b.sql" {
+		t.Errorf("unexpected down plan %s", got)
+	}
+
+	if _, err := migrator.DownTo(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	mustVersion(t, migrator, 1)
+
+	if _, err := migrator.Down(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrator.Down(ctx); !errors.Is(err, migration.ErrNoMigrations) {
+		t.Errorf("expected ErrNoMigrations, got %v", err)
+	}
+
+	if _, err := migrator.Redo(ctx); !errors.Is(err, migration.ErrNoMigrations) {
+		t.Errorf("expected ErrNoMigrations, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "no up", content: "CREATE TABLE d (id INTEGER);\n"},
+		{name: "up after down", content: "-- +goose Down\nDROP TABLE d;\n-- +goose Up\nCREATE TABLE d (id INTEGER);\n"},
+		{name: "unclosed statement", content: "-- +goose Up\n-- +goose StatementBegin\nCREATE TABLE d (id INTEGER);\n"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fsys := fstest.MapFS{"000001_create_d.sql": {Data: []byte(tt.content)}}
+
+			if err := newMigrator(t, fsys).Validate(context.Background()); !errors.Is(err, migration.ErrInvalidMigration) {
+				t.Errorf("expected ErrInvalidMigration, got %v", err)
+			}
+		})
+	}
+
+	t.Run("database ahead of the files", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		newer, err := migration.NewMigrator(db, goose.DialectSQLite3, migrations, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := newer.Up(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		older, err := migration.NewMigrator(db, goose.DialectSQLite3, fstest.MapFS{
+			"000001_create_a.sql": migrations["000001_create_a.sql"],
+		}, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := older.Validate(context.Background()); !errors.Is(err, migration.ErrInvalidMigration) {
+			t.Errorf("expected ErrInvalidMigration, got %v", err)
+		}
+
+		if err := newer.Validate(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	first, err := migration.Create(dir, "Add Users table")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := migration.Create(dir, "add-index")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Base(first) != "000001_add_users_table.sql" || filepath.Base(second) != "000002_add_index.sql" {
+		t.Errorf("unexpected names %s and %s", first, second)
+	}
+
+	data, err := os.ReadFile(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), "-- +goose Up") {
+		t.Errorf("expected the goose annotations, got %q", data)
+	}
+
+	if _, err := migration.Create(dir, "--"); err == nil {
+		t.Error("expected an empty name to fail")
+	}
+}
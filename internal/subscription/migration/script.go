@@ -0,0 +1,105 @@
+package migration
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// ErrInvalidMigration is returned for a migration file goose can't run.
+var ErrInvalidMigration = errors.New("invalid migration")
+
+const (
+	annotationUp             = "-- +goose Up"
+	annotationDown           = "-- +goose Down"
+	annotationStatementBegin = "-- +goose StatementBegin"
+	annotationStatementEnd   = "-- +goose StatementEnd"
+)
+
+// Script is the SQL of one direction of a migration file.
+type Script struct {
+	Version   int64
+	Path      string
+	Direction string
+	SQL       string
+}
+
+// readSections reads the Up and Down sections of the migration file at path. It checks the annotations goose
+// relies on: an Up section before the Down one and every StatementBegin closed by a StatementEnd.
+func readSections(fsys fs.FS, path string) (up, down string, err error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return "", "", fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var (
+		sections      = map[string]*strings.Builder{annotationUp: {}, annotationDown: {}}
+		current       *strings.Builder
+		seen          = make(map[string]bool)
+		inStatement   bool
+		statementLine int
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+
+		switch strings.TrimSpace(text) {
+		case annotationUp, annotationDown:
+			annotation := strings.TrimSpace(text)
+			if seen[annotation] {
+				return "", "", fmt.Errorf("%w: %s:%d: duplicate %q", ErrInvalidMigration, path, line, annotation)
+			}
+
+			if annotation == annotationUp && seen[annotationDown] {
+				return "", "", fmt.Errorf("%w: %s:%d: Up after Down", ErrInvalidMigration, path, line)
+			}
+
+			if inStatement {
+				return "", "", fmt.Errorf("%w: %s:%d: StatementBegin at line %d isn't closed", ErrInvalidMigration,
+					path, line, statementLine)
+			}
+
+			seen[annotation] = true
+			current = sections[annotation]
+
+			continue
+		case annotationStatementBegin:
+			if inStatement {
+				return "", "", fmt.Errorf("%w: %s:%d: nested StatementBegin", ErrInvalidMigration, path, line)
+			}
+
+			inStatement, statementLine = true, line
+		case annotationStatementEnd:
+			if !inStatement {
+				return "", "", fmt.Errorf("%w: %s:%d: StatementEnd without StatementBegin", ErrInvalidMigration,
+					path, line)
+			}
+
+			inStatement = false
+		}
+
+		if current != nil {
+			current.WriteString(text)
+			current.WriteByte('\n')
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("read %s: %w", path, err)
+	}
+
+	if !seen[annotationUp] {
+		return "", "", fmt.Errorf("%w: %s: no %q annotation", ErrInvalidMigration, path, annotationUp)
+	}
+
+	if inStatement {
+		return "", "", fmt.Errorf("%w: %s: StatementBegin at line %d isn't closed", ErrInvalidMigration, path,
+			statementLine)
+	}
+
+	return strings.TrimSpace(sections[annotationUp].String()), strings.TrimSpace(sections[annotationDown].String()), nil
+}
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE subscription (
+                       id INTEGER PRIMARY KEY AUTOINCREMENT,
+                       user_id TEXT NOT NULL,
+                       start_date TEXT NOT NULL,
+                       price INTEGER NOT NULL,
+                       service_name TEXT NOT NULL,
+                       end_date TEXT,
+                       currency TEXT NOT NULL DEFAULT 'RUB',
+                       billing_period TEXT NOT NULL DEFAULT 'monthly'
+                           CHECK (billing_period IN ('monthly', 'quarterly', 'semiannual', 'yearly')),
+                       trial_end_date TEXT,
+                       created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                       CONSTRAINT subscription_constrain UNIQUE (user_id, start_date, price, service_name)
+);
+
+-- +goose Down
+DROP TABLE subscription;
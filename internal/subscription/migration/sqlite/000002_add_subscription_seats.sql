@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE subscription ADD COLUMN seats INTEGER NOT NULL DEFAULT 1 CHECK (seats > 0);
+ALTER TABLE subscription ADD COLUMN organization_id INTEGER;
+ALTER TABLE subscription ADD COLUMN department TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE subscription DROP COLUMN department;
+ALTER TABLE subscription DROP COLUMN organization_id;
+ALTER TABLE subscription DROP COLUMN seats;
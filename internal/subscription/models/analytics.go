@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Dimensions the spend analytics can be grouped by.
+const (
+	GroupByMonth    = "month"
+	GroupByService  = "service"
+	GroupByCategory = "category"
+	GroupByCurrency = "currency"
+)
+
+type SpendAnalyticsQueryJSON struct {
+	UserID    string `query:"user_id"    example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	StartDate string `query:"start_date" example:"01-2025"`
+	EndDate   string `query:"end_date"   example:"12-2025"`
+	GroupBy   string `query:"group_by"   example:"month,service"`
+}
+
+type SpendAnalyticsFilter struct {
+	UserID    uuid.UUID
+	StartDate time.Time
+	EndDate   time.Time
+	GroupBy   []string
+}
+
+type SpendAnalyticsDB struct {
+	Total          int
+	MonthlyAverage float64
+	Groups         []SpendAnalyticsGroupDB
+}
+
+type SpendAnalyticsGroupDB struct {
+	Month       *time.Time
+	ServiceName *string
+	Category    *string
+	Currency    *string
+	Total       int
+	Average     float64
+	Charges     int
+	Delta       *int
+}
+
+// MonthlySpendDB is the spend of a user in a month, Delta is nil for the first month.
+type MonthlySpendDB struct {
+	UserID uuid.UUID
+	Month  time.Time
+	Total  int
+	Delta  *int
+}
+
+type SpendAnalyticsJSON struct {
+	StartDate      string                    `json:"start_date"      example:"01-2025"`
+	EndDate        string                    `json:"end_date"        example:"12-2025"`
+	GroupBy        []string                  `json:"group_by"        example:"month,service"`
+	Total          int                       `json:"total"           example:"4800"`
+	MonthlyAverage float64                   `json:"monthly_average" example:"400"`
+	Groups         []SpendAnalyticsGroupJSON `json:"groups"`
+}
+
+type SpendAnalyticsGroupJSON struct {
+	Month       string  `json:"month,omitempty"        example:"09-2025"`
+	ServiceName string  `json:"service_name,omitempty" example:"Netflix"`
+	Category    string  `json:"category,omitempty"     example:"video"`
+	Currency    string  `json:"currency,omitempty"     example:"RUB"`
+	Total       int     `json:"total"                  example:"400"`
+	Average     float64 `json:"average"                example:"400"`
+	Charges     int     `json:"charges"                example:"1"`
+	Delta       *int    `json:"delta,omitempty"        example:"-100"`
+}
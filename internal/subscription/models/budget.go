@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes a budget can limit: all subscriptions of the user, one category or one service.
+const (
+	BudgetScopeTotal    = "total"
+	BudgetScopeCategory = "category"
+	BudgetScopeService  = "service"
+)
+
+type BudgetJSON struct {
+	ID         int       `json:"id,omitempty"          example:"1"`
+	UserID     uuid.UUID `json:"user_id"               example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Scope      string    `json:"scope"                 example:"category"`
+	ScopeValue string    `json:"scope_value,omitempty" example:"music"`
+	Amount     int       `json:"amount"                example:"1000"`
+}
+
+// BudgetUsage is a budget together with the projected cost of its scope for one month.
+type BudgetUsage struct {
+	Budget       BudgetJSON
+	Projected    int
+	AlertedMonth *time.Time
+}
+
+type BudgetAlert struct {
+	Budget    BudgetJSON
+	Month     time.Time
+	Projected int
+}
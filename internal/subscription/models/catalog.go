@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subscription is a complete subscription row, used where callers need every column at once. Price is the
+// price of a seat, OrganizationID is set for the subscriptions an organization owns.
+type Subscription struct {
+	ID             int
+	UserID         uuid.UUID
+	ServiceName    string
+	Price          int
+	Currency       string
+	BillingPeriod  string
+	StartDate      time.Time
+	EndDate        *time.Time
+	TrialEndDate   *time.Time
+	Seats          int
+	OrganizationID *int
+	Department     string
+}
+
+// Cost is the price of all the seats of the subscription. A subscription without seats set has one.
+func (s Subscription) Cost() int {
+	return s.Price * max(s.Seats, 1)
+}
+
+type CatalogService struct {
+	ServiceName string
+	Category    string
+	Price       *int
+}
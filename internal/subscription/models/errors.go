@@ -2,10 +2,43 @@ package models
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
 	ErrUnique               = errors.New("already exists")
 	ErrNotFound             = errors.New("not found")
 	ErrDBConnectionCreation = errors.New("db connection creation error")
+	ErrInvalidGroupBy       = errors.New("invalid group by dimension")
+	ErrInvalidBudget        = errors.New("invalid budget")
+	ErrInvalidHousehold     = errors.New("invalid household")
+	ErrInvalidShare         = errors.New("invalid subscription share")
+	ErrInvalidOrganization  = errors.New("invalid organization")
+	ErrInvalidSubscription  = errors.New("invalid subscription")
+	ErrForbidden            = errors.New("subscription belongs to another user")
+	ErrForeignKey           = errors.New("referenced record doesn't exist")
+	ErrCheckViolation       = errors.New("value violates a constraint")
+	ErrUnavailable          = errors.New("storage is temporarily unavailable")
 )
+
+// DBError is a storage error the client can act on. Kind is one of ErrUnique, ErrForeignKey,
+// ErrCheckViolation and ErrUnavailable, the other fields name what was violated when the database reports it.
+type DBError struct {
+	Kind       error
+	Table      string
+	Column     string
+	Constraint string
+	Cause      error
+}
+
+func (e *DBError) Error() string {
+	if e.Constraint != "" {
+		return fmt.Sprintf("%s: constraint %s: %v", e.Kind, e.Constraint, e.Cause)
+	}
+
+	return fmt.Sprintf("%s: %v", e.Kind, e.Cause)
+}
+
+func (e *DBError) Unwrap() []error {
+	return []error{e.Kind, e.Cause}
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionEventType string
+
+const (
+	SubscriptionCreated SubscriptionEventType = "subscription.created"
+	SubscriptionUpdated SubscriptionEventType = "subscription.updated"
+	SubscriptionDeleted SubscriptionEventType = "subscription.deleted"
+)
+
+// SubscriptionEvent is emitted after a subscription has been changed.
+type SubscriptionEvent struct {
+	Type           SubscriptionEventType
+	SubscriptionID int
+	UserID         uuid.UUID
+	ServiceName    string
+	At             time.Time
+}
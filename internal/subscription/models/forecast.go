@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BillingMonthly    = "monthly"
+	BillingQuarterly  = "quarterly"
+	BillingSemiannual = "semiannual"
+	BillingYearly     = "yearly"
+)
+
+// BillingPeriodMonths is the number of months between two charges of a billing period.
+var BillingPeriodMonths = map[string]int{
+	BillingMonthly:    1,
+	BillingQuarterly:  3,
+	BillingSemiannual: 6,
+	BillingYearly:     12,
+}
+
+type PriceChangeJSON struct {
+	SubscriptionID int    `json:"subscription_id" example:"1"`
+	EffectiveDate  string `json:"effective_date"  example:"01-2026"`
+	Price          int    `json:"price"           example:"500"`
+}
+
+type PriceChange struct {
+	EffectiveDate time.Time
+	Price         int
+}
+
+type ForecastSubscription struct {
+	ID            int
+	ServiceName   string
+	Price         int
+	Currency      string
+	BillingPeriod string
+	StartDate     time.Time
+	EndDate       *time.Time
+	TrialEndDate  *time.Time
+	PriceChanges  []PriceChange
+	Hypothetical  bool
+}
+
+type ForecastRequestJSON struct {
+	UserID    uuid.UUID          `json:"user_id"              example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	StartDate string             `json:"start_date,omitempty" example:"01-2026"`
+	Months    int                `json:"months,omitempty"     example:"12"`
+	WhatIf    ForecastWhatIfJSON `json:"what_if"`
+}
+
+// ForecastWhatIfJSON describes hypothetical changes applied to the forecast only, nothing is persisted.
+type ForecastWhatIfJSON struct {
+	CancelIDs      []int                  `json:"cancel_ids,omitempty"      example:"1,2"`
+	CancelServices []string               `json:"cancel_services,omitempty" example:"Netflix"`
+	Add            []SubscriptionListJSON `json:"add,omitempty"`
+}
+
+type Forecast struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Total     int
+	Months    []ForecastMonth
+	Lines     []ForecastLine
+}
+
+type ForecastMonth struct {
+	Month time.Time
+	Total int
+}
+
+type ForecastLine struct {
+	SubscriptionID int
+	ServiceName    string
+	Currency       string
+	Hypothetical   bool
+	Total          int
+	Charges        []ForecastMonth
+}
+
+type ForecastJSON struct {
+	StartDate string              `json:"start_date" example:"01-2026"`
+	EndDate   string              `json:"end_date"   example:"12-2026"`
+	Total     int                 `json:"total"      example:"4800"`
+	Months    []ForecastMonthJSON `json:"months"`
+	Lines     []ForecastLineJSON  `json:"lines"`
+}
+
+type ForecastMonthJSON struct {
+	Month string `json:"month" example:"01-2026"`
+	Total int    `json:"total" example:"400"`
+}
+
+type ForecastLineJSON struct {
+	SubscriptionID int                 `json:"subscription_id,omitempty" example:"1"`
+	ServiceName    string              `json:"service_name"              example:"Netflix"`
+	Currency       string              `json:"currency"                  example:"RUB"`
+	Hypothetical   bool                `json:"hypothetical"              example:"false"`
+	Total          int                 `json:"total"                     example:"4800"`
+	Charges        []ForecastMonthJSON `json:"charges"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// Statuses reported by the health endpoints.
+const (
+	HealthStatusOK           = "ok"
+	HealthStatusDegraded     = "degraded"
+	HealthStatusUnavailable  = "unavailable"
+	HealthStatusShuttingDown = "shutting_down"
+)
+
+type HealthJSON struct {
+	Status string            `json:"status"           example:"ok"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Split rules of a shared subscription. The members get an equal part of the price, a percentage of it or a
+// fixed amount, and the payer gets the rest.
+const (
+	SplitEqual      = "equal"
+	SplitPercentage = "percentage"
+	SplitFixed      = "fixed"
+)
+
+type HouseholdJSON struct {
+	ID      int         `json:"id,omitempty"      example:"1"`
+	Name    string      `json:"name"              example:"Семья"`
+	OwnerID uuid.UUID   `json:"owner_id"          example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Members []uuid.UUID `json:"members,omitempty"`
+}
+
+type HouseholdMemberJSON struct {
+	HouseholdID int       `json:"household_id" example:"1"`
+	UserID      uuid.UUID `json:"user_id"      example:"7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d"`
+}
+
+// ShareJSON is the part of a member in a shared subscription: a percentage of the price for the percentage
+// split or an amount for the fixed one. The equal split ignores Value.
+type ShareJSON struct {
+	UserID uuid.UUID `json:"user_id"         example:"7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d"`
+	Value  int       `json:"value,omitempty" example:"30"`
+}
+
+// SharedSubscriptionJSON shares a subscription with members of a household. The user of the subscription pays
+// for it and is left with what the shares of the members don't cover.
+type SharedSubscriptionJSON struct {
+	SubscriptionID int         `json:"subscription_id" example:"1"`
+	HouseholdID    int         `json:"household_id"    example:"1"`
+	Split          string      `json:"split"           example:"percentage"`
+	Shares         []ShareJSON `json:"shares"`
+}
+
+// SharedSubscription is a subscription with the split rule it is shared by.
+type SharedSubscription struct {
+	Subscription Subscription
+	HouseholdID  int
+	Split        string
+	Shares       []ShareJSON
+}
+
+// SharedCharge is a charge of a shared subscription in a month.
+type SharedCharge struct {
+	SubscriptionID int
+	Month          time.Time
+	Price          int
+	Currency       string
+}
+
+// SettlementJSON is a payment settling the shared costs: From owes To Amount.
+type SettlementJSON struct {
+	From     uuid.UUID `json:"from"     example:"7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d"`
+	To       uuid.UUID `json:"to"       example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Amount   int       `json:"amount"   example:"270"`
+	Currency string    `json:"currency" example:"RUB"`
+}
+
+type SettleUpJSON struct {
+	HouseholdID int              `json:"household_id" example:"1"`
+	StartDate   string           `json:"start_date"   example:"01-2025"`
+	EndDate     string           `json:"end_date"     example:"06-2025"`
+	Settlements []SettlementJSON `json:"settlements"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// ServiceSpend is the number of active subscriptions of a service and their spend normalized to one month.
+type ServiceSpend struct {
+	ServiceName   string
+	Currency      string
+	Subscriptions int
+	MonthlySpend  float64
+}
@@ -1,28 +1,52 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type SubscriptionListDB struct {
-	UserID      uuid.UUID   `db:"user_id"`
-	StartDate   pgtype.Date `db:"start_date"`
-	Price       int         `db:"price"`
-	ServiceName string      `db:"service_name"`
+// MonthLayout is the "MM-YYYY" layout used for every date the API accepts and returns.
+const MonthLayout = "01-2006"
+
+// ParseOptionalMonth parses a MonthLayout date that may be omitted, returning nil for an empty value.
+func ParseOptionalMonth(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(MonthLayout, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &date, nil
 }
 
 type SubscriptionListDTO struct {
-	StartDate   pgtype.Date `json:"start_date"   example:"09-2025"`
-	Price       int         `json:"price"        example:"400"`
-	ServiceName string      `json:"service_name" example:"Netflix"`
+	StartDate      pgtype.Date `json:"start_date"                example:"09-2025"`
+	Price          int         `json:"price"                     example:"400"`
+	Seats          int         `json:"seats"                     example:"1"`
+	ServiceName    string      `json:"service_name"              example:"Netflix"`
+	OrganizationID *int        `json:"organization_id,omitempty" example:"1"`
+	Department     string      `json:"department,omitempty"      example:"Разработка"`
 }
 
+// SubscriptionListJSON is a subscription as the API accepts it. Price is the price of a seat, the
+// subscription costs Seats times it; omitted Seats means one.
 type SubscriptionListJSON struct {
-	UserID      uuid.UUID `json:"user_id"      example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-	StartDate   string    `json:"start_date"   example:"09-2025"`
-	Price       int       `json:"price"        example:"400"`
-	ServiceName string    `json:"service_name" example:"Netflix"`
+	UserID         uuid.UUID `json:"user_id"                   example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	StartDate      string    `json:"start_date"                example:"09-2025"`
+	EndDate        string    `json:"end_date,omitempty"        example:"12-2025"`
+	TrialEndDate   string    `json:"trial_end_date,omitempty"  example:"10-2025"`
+	Price          int       `json:"price"                     example:"400"`
+	Seats          int       `json:"seats,omitempty"           example:"1"`
+	Currency       string    `json:"currency,omitempty"        example:"RUB"`
+	BillingPeriod  string    `json:"billing_period,omitempty"  example:"monthly"`
+	ServiceName    string    `json:"service_name"              example:"Netflix"`
+	OrganizationID *int      `json:"organization_id,omitempty" example:"1"`
+	Department     string    `json:"department,omitempty"      example:"Разработка"`
 }
 
 type SubscriptionListToCostJSON struct {
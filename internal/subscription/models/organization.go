@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles of the organization members. Admins manage the organization and its subscriptions and see the spend
+// of the whole organization, members see their own.
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// Scopes of the organization spend.
+const (
+	SpendScopeOrganization = "organization"
+	SpendScopeMember       = "member"
+)
+
+// DepartmentUnassigned attributes the spend of the subscriptions with no department, whose user has none either.
+const DepartmentUnassigned = "unassigned"
+
+// OrganizationJSON is an organization. AdminID creates it and becomes its first admin.
+type OrganizationJSON struct {
+	ID      int                      `json:"id,omitempty"      example:"1"`
+	Name    string                   `json:"name"              example:"ООО Ромашка"`
+	AdminID uuid.UUID                `json:"admin_id"          example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Members []OrganizationMemberJSON `json:"members,omitempty"`
+}
+
+type OrganizationMemberJSON struct {
+	OrganizationID int       `json:"organization_id"      example:"1"`
+	UserID         uuid.UUID `json:"user_id"              example:"7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d"`
+	Role           string    `json:"role"                 example:"member"`
+	Department     string    `json:"department,omitempty" example:"Разработка"`
+}
+
+// SeatChangeJSON is a change of the number of seats of a subscription.
+type SeatChangeJSON struct {
+	SubscriptionID int       `json:"subscription_id" example:"1"`
+	PreviousSeats  int       `json:"previous_seats"  example:"5"`
+	Seats          int       `json:"seats"           example:"8"`
+	ChangedAt      time.Time `json:"changed_at"      example:"2025-09-01T10:00:00Z"`
+}
+
+// OrganizationSpendFilter selects the charges of the subscriptions of the organization from StartDate to
+// EndDate, only those of UserID when it is set.
+type OrganizationSpendFilter struct {
+	OrganizationID int
+	UserID         *uuid.UUID
+	StartDate      time.Time
+	EndDate        time.Time
+}
+
+// OrganizationSpendDB is the total of the charges of a service in a department.
+type OrganizationSpendDB struct {
+	Department  string
+	ServiceName string
+	Currency    string
+	Seats       int
+	Total       int
+}
+
+type OrganizationSpendJSON struct {
+	OrganizationID int                   `json:"organization_id" example:"1"`
+	Scope          string                `json:"scope"           example:"organization"`
+	StartDate      string                `json:"start_date"      example:"01-2025"`
+	EndDate        string                `json:"end_date"        example:"12-2025"`
+	Totals         []CurrencyTotalJSON   `json:"totals"`
+	Departments    []DepartmentSpendJSON `json:"departments"`
+}
+
+type CurrencyTotalJSON struct {
+	Currency string `json:"currency" example:"RUB"`
+	Total    int    `json:"total"    example:"48000"`
+}
+
+type DepartmentSpendJSON struct {
+	Department string              `json:"department" example:"Разработка"`
+	Totals     []CurrencyTotalJSON `json:"totals"`
+	Services   []ServiceSpendJSON  `json:"services"`
+}
+
+// ServiceSpendJSON is the spend on a service in a currency, Seats is the largest number of seats charged in
+// a month.
+type ServiceSpendJSON struct {
+	ServiceName string `json:"service_name" example:"Slack"`
+	Currency    string `json:"currency"     example:"RUB"`
+	Seats       int    `json:"seats"        example:"8"`
+	Total       int    `json:"total"        example:"24000"`
+}
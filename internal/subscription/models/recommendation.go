@@ -0,0 +1,39 @@
+package models
+
+import "github.com/google/uuid"
+
+// Kinds of findings reported by the waste detection.
+const (
+	FindingOverlap           = "overlap"
+	FindingDuplicate         = "duplicate"
+	FindingNearDuplicate     = "near_duplicate"
+	FindingAboveCatalogPrice = "above_catalog_price"
+)
+
+// ActiveSubscription is a subscription that has not ended yet, joined with its service catalog entry.
+// ActiveSubscription is a subscription with its catalog entry. Price and CatalogPrice are prices of a seat.
+type ActiveSubscription struct {
+	ID            int
+	ServiceName   string
+	Price         int
+	Seats         int
+	Currency      string
+	BillingPeriod string
+	Category      *string
+	CatalogPrice  *int
+}
+
+type FindingJSON struct {
+	Type            string   `json:"type"               example:"overlap"`
+	Category        string   `json:"category,omitempty" example:"music"`
+	SubscriptionIDs []int    `json:"subscription_ids"   example:"1,2"`
+	ServiceNames    []string `json:"service_names"      example:"Spotify,Yandex Music"`
+	Description     string   `json:"description"        example:"Несколько подписок в категории music"`
+	AnnualSaving    int      `json:"annual_saving"      example:"2388"`
+}
+
+type RecommendationsJSON struct {
+	UserID            uuid.UUID     `json:"user_id"             example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	TotalAnnualSaving int           `json:"total_annual_saving" example:"2388"`
+	Findings          []FindingJSON `json:"findings"`
+}
@@ -0,0 +1,35 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+)
+
+// Notifier delivers alerts raised by the background workers to the user.
+type Notifier interface {
+	NotifyBudgetExceeded(ctx context.Context, alert models.BudgetAlert) error
+}
+
+// LogNotifier writes alerts to the service log, it is used until a real delivery channel is configured.
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) NotifyBudgetExceeded(_ context.Context, alert models.BudgetAlert) error {
+	n.logger.Warn("Budget exceeded",
+		"BudgetID", alert.Budget.ID,
+		"UserID", alert.Budget.UserID,
+		"Scope", alert.Budget.Scope,
+		"ScopeValue", alert.Budget.ScopeValue,
+		"Amount", alert.Budget.Amount,
+		"Projected", alert.Projected,
+		"Month", alert.Month.Format(models.MonthLayout))
+
+	return nil
+}
@@ -0,0 +1,111 @@
+// Package organization validates the organizations and their members and aggregates the spend of the
+// organization subscriptions by department.
+package organization
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+const (
+	maxNameLength       = 64
+	maxDepartmentLength = 64
+)
+
+// ValidateOrganization checks the name and the admin creating the organization.
+func ValidateOrganization(org models.OrganizationJSON) error {
+	if org.Name == "" || len([]rune(org.Name)) > maxNameLength {
+		return fmt.Errorf("%w: name must be 1 to %d characters", models.ErrInvalidOrganization, maxNameLength)
+	}
+
+	if org.AdminID == uuid.Nil {
+		return fmt.Errorf("%w: no admin_id", models.ErrInvalidOrganization)
+	}
+
+	return nil
+}
+
+// ValidateMember checks the member, an omitted role makes a regular member.
+func ValidateMember(member *models.OrganizationMemberJSON) error {
+	if member.UserID == uuid.Nil {
+		return fmt.Errorf("%w: no user_id", models.ErrInvalidOrganization)
+	}
+
+	switch member.Role {
+	case "":
+		member.Role = models.RoleMember
+	case models.RoleAdmin, models.RoleMember:
+	default:
+		return fmt.Errorf("%w: unknown role %q", models.ErrInvalidOrganization, member.Role)
+	}
+
+	if len([]rune(member.Department)) > maxDepartmentLength {
+		return fmt.Errorf("%w: department must have at most %d characters", models.ErrInvalidOrganization,
+			maxDepartmentLength)
+	}
+
+	return nil
+}
+
+// Spend sums the rows by department and by currency. The departments and the services within them are ordered
+// by name, the currencies by code.
+func Spend(rows []models.OrganizationSpendDB) (totals []models.CurrencyTotalJSON, departments []models.DepartmentSpendJSON) {
+	all := make(map[string]int)
+	byDepartment := make(map[string]*models.DepartmentSpendJSON)
+	departmentTotals := make(map[string]map[string]int)
+
+	for _, row := range rows {
+		all[row.Currency] += row.Total
+
+		department, ok := byDepartment[row.Department]
+		if !ok {
+			department = &models.DepartmentSpendJSON{Department: row.Department}
+			byDepartment[row.Department] = department
+			departmentTotals[row.Department] = make(map[string]int)
+		}
+
+		departmentTotals[row.Department][row.Currency] += row.Total
+		department.Services = append(department.Services, models.ServiceSpendJSON{
+			ServiceName: row.ServiceName,
+			Currency:    row.Currency,
+			Seats:       row.Seats,
+			Total:       row.Total,
+		})
+	}
+
+	departments = make([]models.DepartmentSpendJSON, 0, len(byDepartment))
+
+	for name, department := range byDepartment {
+		department.Totals = currencyTotals(departmentTotals[name])
+
+		sort.Slice(department.Services, func(i, j int) bool {
+			a, b := department.Services[i], department.Services[j]
+			if a.ServiceName != b.ServiceName {
+				return a.ServiceName < b.ServiceName
+			}
+
+			return a.Currency < b.Currency
+		})
+
+		departments = append(departments, *department)
+	}
+
+	sort.Slice(departments, func(i, j int) bool { return departments[i].Department < departments[j].Department })
+
+	return currencyTotals(all), departments
+}
+
+func currencyTotals(amounts map[string]int) []models.CurrencyTotalJSON {
+	totals := make([]models.CurrencyTotalJSON, 0, len(amounts))
+
+	for currency, total := range amounts {
+		totals = append(totals, models.CurrencyTotalJSON{Currency: currency, Total: total})
+	}
+
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Currency < totals[j].Currency })
+
+	return totals
+}
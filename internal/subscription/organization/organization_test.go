@@ -0,0 +1,126 @@
+package organization
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+var user = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+func TestValidateOrganization(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		org     models.OrganizationJSON
+		wantErr bool
+	}{
+		{name: "valid", org: models.OrganizationJSON{Name: "ООО Ромашка", AdminID: user}},
+		{name: "no name", org: models.OrganizationJSON{AdminID: user}, wantErr: true},
+		{name: "long name", org: models.OrganizationJSON{Name: strings.Repeat("я", 65), AdminID: user}, wantErr: true},
+		{name: "no admin", org: models.OrganizationJSON{Name: "ООО Ромашка"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateOrganization(tt.org)
+			if tt.wantErr != errors.Is(err, models.ErrInvalidOrganization) || (!tt.wantErr && err != nil) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateMember(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		member   models.OrganizationMemberJSON
+		wantRole string
+		wantErr  bool
+	}{
+		{name: "role defaults to member", member: models.OrganizationMemberJSON{UserID: user}, wantRole: models.RoleMember},
+		{name: "admin", member: models.OrganizationMemberJSON{UserID: user, Role: models.RoleAdmin}, wantRole: models.RoleAdmin},
+		{name: "unknown role", member: models.OrganizationMemberJSON{UserID: user, Role: "owner"}, wantErr: true},
+		{name: "no user", member: models.OrganizationMemberJSON{Role: models.RoleMember}, wantErr: true},
+		{
+			name:    "long department",
+			member:  models.OrganizationMemberJSON{UserID: user, Department: strings.Repeat("я", 65)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateMember(&tt.member)
+			if tt.wantErr {
+				if !errors.Is(err, models.ErrInvalidOrganization) {
+					t.Errorf("expected ErrInvalidOrganization, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil || tt.member.Role != tt.wantRole {
+				t.Errorf("expected role %s, got %s and %v", tt.wantRole, tt.member.Role, err)
+			}
+		})
+	}
+}
+
+func TestSpend(t *testing.T) {
+	t.Parallel()
+
+	totals, departments := Spend([]models.OrganizationSpendDB{
+		{Department: "Продажи", ServiceName: "Slack", Currency: "RUB", Seats: 3, Total: 3000},
+		{Department: "Разработка", ServiceName: "Slack", Currency: "RUB", Seats: 8, Total: 8000},
+		{Department: "Разработка", ServiceName: "GitHub", Currency: "USD", Seats: 8, Total: 168},
+		{Department: "Разработка", ServiceName: "Figma", Currency: "RUB", Seats: 2, Total: 2400},
+	})
+
+	wantTotals := []models.CurrencyTotalJSON{{Currency: "RUB", Total: 13400}, {Currency: "USD", Total: 168}}
+	if !reflect.DeepEqual(totals, wantTotals) {
+		t.Errorf("expected totals %+v, got %+v", wantTotals, totals)
+	}
+
+	wantDepartments := []models.DepartmentSpendJSON{
+		{
+			Department: "Продажи",
+			Totals:     []models.CurrencyTotalJSON{{Currency: "RUB", Total: 3000}},
+			Services:   []models.ServiceSpendJSON{{ServiceName: "Slack", Currency: "RUB", Seats: 3, Total: 3000}},
+		},
+		{
+			Department: "Разработка",
+			Totals:     []models.CurrencyTotalJSON{{Currency: "RUB", Total: 10400}, {Currency: "USD", Total: 168}},
+			Services: []models.ServiceSpendJSON{
+				{ServiceName: "Figma", Currency: "RUB", Seats: 2, Total: 2400},
+				{ServiceName: "GitHub", Currency: "USD", Seats: 8, Total: 168},
+				{ServiceName: "Slack", Currency: "RUB", Seats: 8, Total: 8000},
+			},
+		},
+	}
+	if !reflect.DeepEqual(departments, wantDepartments) {
+		t.Errorf("expected departments %+v, got %+v", wantDepartments, departments)
+	}
+}
+
+func TestSpendEmpty(t *testing.T) {
+	t.Parallel()
+
+	totals, departments := Spend(nil)
+	if totals == nil || departments == nil || len(totals) != 0 || len(departments) != 0 {
+		t.Errorf("expected empty, non-nil results, got %+v and %+v", totals, departments)
+	}
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryLimiter keeps the buckets in process memory, every replica enforces its own limits.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return newMemoryLimiter(time.Now)
+}
+
+func newMemoryLimiter(now func() time.Time) *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: now(),
+		now:       now,
+	}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, buckets []Bucket) ([]Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	refilled := make([]*bucket, len(buckets))
+	allowed := true
+
+	for i, request := range buckets {
+		b, ok := l.buckets[request.Key]
+		if !ok {
+			b = &bucket{tokens: float64(request.Limit.Burst), updatedAt: now}
+			l.buckets[request.Key] = b
+		}
+
+		b.tokens = math.Min(float64(request.Limit.Burst), b.tokens+now.Sub(b.updatedAt).Seconds()*request.Limit.Rate)
+		b.updatedAt = now
+
+		refilled[i] = b
+		allowed = allowed && b.tokens >= 1
+	}
+
+	results := make([]Result, len(buckets))
+
+	for i, b := range refilled {
+		hasToken := b.tokens >= 1
+		if allowed {
+			b.tokens--
+		}
+
+		results[i] = newResult(hasToken, b.tokens, buckets[i].Limit)
+	}
+
+	return results, nil
+}
+
+// sweep drops the buckets idle for a sweep interval. A dropped bucket may not be full yet, so a client
+// gets at most one extra burst per sweep interval, which keeps memory bounded by the active clients.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+
+	for key, b := range l.buckets {
+		if now.Sub(b.updatedAt) >= sweepInterval {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.lastSweep = now
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func allow(limiter *MemoryLimiter, key string, limit Limit) Result {
+	results, _ := limiter.Allow(context.Background(), []Bucket{{Key: key, Limit: limit}})
+
+	return results[0]
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newMemoryLimiter(func() time.Time { return now })
+	limit := Limit{Rate: 1, Burst: 3}
+
+	for i := 2; i >= 0; i-- {
+		res := allow(limiter, "ip:10.0.0.1", limit)
+		if !res.Allowed || res.Remaining != i {
+			t.Fatalf("expected allowed with %d remaining, got %+v", i, res)
+		}
+	}
+
+	res := allow(limiter, "ip:10.0.0.1", limit)
+	if res.Allowed || res.RetryAfter != time.Second || res.Reset != 3*time.Second {
+		t.Fatalf("expected denied for a second, got %+v", res)
+	}
+
+	if other := allow(limiter, "ip:10.0.0.2", limit); !other.Allowed {
+		t.Fatal("expected separate buckets per key")
+	}
+
+	now = now.Add(1500 * time.Millisecond)
+
+	res = allow(limiter, "ip:10.0.0.1", limit)
+	if !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("expected one refilled token, got %+v", res)
+	}
+
+	now = now.Add(time.Hour)
+
+	res = allow(limiter, "ip:10.0.0.1", limit)
+	if !res.Allowed || res.Remaining != 2 {
+		t.Fatalf("expected a full bucket capped at burst, got %+v", res)
+	}
+
+	if len(limiter.buckets) != 1 {
+		t.Errorf("expected the idle bucket to be swept, got %d buckets", len(limiter.buckets))
+	}
+}
+
+func TestMemoryLimiterAllOrNothing(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newMemoryLimiter(func() time.Time { return now })
+	ip := Bucket{Key: "ip:10.0.0.1", Limit: Limit{Rate: 1, Burst: 3}}
+	route := Bucket{Key: "route:GET /subscription/total-price", Limit: Limit{Rate: 1, Burst: 1}}
+
+	results, _ := limiter.Allow(context.Background(), []Bucket{ip, route})
+	if !results[0].Allowed || !results[1].Allowed || results[0].Remaining != 2 {
+		t.Fatalf("expected both buckets to take a token, got %+v", results)
+	}
+
+	for i := 0; i < 2; i++ {
+		results, _ = limiter.Allow(context.Background(), []Bucket{ip, route})
+		if !results[0].Allowed || results[1].Allowed || results[0].Remaining != 2 {
+			t.Fatalf("expected the route to reject without draining the IP bucket, got %+v", results)
+		}
+	}
+
+	if res := allow(limiter, ip.Key, ip.Limit); !res.Allowed || res.Remaining != 1 {
+		t.Errorf("expected the IP bucket to keep its tokens, got %+v", res)
+	}
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage"
+)
+
+// bucketTTL is how long an idle bucket is kept in the database, any sensible limit refills it by then.
+const bucketTTL = time.Hour
+
+// PostgresLimiter keeps the buckets in Postgres, so all replicas share the same limits.
+type PostgresLimiter struct {
+	repo   storage.RateLimitRepository
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresLimiter(repo storage.RateLimitRepository, logger *slog.Logger) *PostgresLimiter {
+	return &PostgresLimiter{repo: repo, logger: logger, lastSweep: time.Now()}
+}
+
+func (l *PostgresLimiter) Allow(ctx context.Context, buckets []Bucket) ([]Result, error) {
+	l.sweep()
+
+	keys := make([]string, len(buckets))
+	rates := make([]float64, len(buckets))
+	bursts := make([]int, len(buckets))
+
+	for i, b := range buckets {
+		keys[i], rates[i], bursts[i] = b.Key, b.Limit.Rate, b.Limit.Burst
+	}
+
+	allowed, tokens, err := l.repo.TakeRateLimitTokens(ctx, keys, rates, bursts)
+	if err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+
+	results := make([]Result, len(buckets))
+	for i, b := range buckets {
+		results[i] = newResult(allowed[i], tokens[i], b.Limit)
+	}
+
+	return results, nil
+}
+
+// sweep deletes the idle buckets in the background at most once per sweep interval per replica.
+func (l *PostgresLimiter) sweep() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+
+	l.lastSweep = now
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sweepInterval)
+		defer cancel()
+
+		if err := l.repo.DeleteRateLimitBuckets(ctx, now.Add(-bucketTTL)); err != nil {
+			l.logger.Error("Error deleting idle rate limit buckets", slog.Any("error_details", err))
+		}
+	}()
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage"
+)
+
+// Limit is a token bucket holding up to Burst tokens and refilled with Rate tokens per second.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Result describes the bucket after a request, in the terms of the RateLimit-* headers.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Bucket is a token bucket checked for a request, stored under Key.
+type Bucket struct {
+	Key   string
+	Limit Limit
+}
+
+// Limiter takes a token from every bucket of the request only when all of them have one, so a request
+// rejected by one bucket doesn't drain the others. The results are in the order of the buckets.
+type Limiter interface {
+	Allow(ctx context.Context, buckets []Bucket) ([]Result, error)
+}
+
+// newResult derives the headers values from the tokens left in the bucket.
+func newResult(allowed bool, tokens float64, limit Limit) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Max(0, s) * float64(time.Second))
+}
+
+// Backends supported in RateLimitConfig.Backend.
+const (
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+)
+
+var ErrUnknownBackend = errors.New("unknown rate limit backend")
+
+func New(backend string, repo storage.RateLimitRepository, logger *slog.Logger) (Limiter, error) {
+	switch backend {
+	case "", BackendMemory:
+		return NewMemoryLimiter(), nil
+	case BackendPostgres:
+		return NewPostgresLimiter(repo, logger), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, backend)
+	}
+}
@@ -0,0 +1,302 @@
+package recommendation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+)
+
+// Names of at least minFuzzyLength characters that differ by at most maxNameDistance edits
+// are treated as the same service.
+const (
+	minFuzzyLength  = 5
+	maxNameDistance = 2
+)
+
+// catalogCurrency is the currency of the service catalog prices.
+const catalogCurrency = "RUB"
+
+// Detect looks for waste among the active subscriptions of a user: exact and near duplicates of the same
+// service, several services of one category and prices above the catalog price. Every finding carries
+// an estimated annual saving.
+func Detect(subs []models.ActiveSubscription) []models.FindingJSON {
+	findings := []models.FindingJSON{}
+
+	clusters := clusterByName(subs)
+	representatives := make([]models.ActiveSubscription, 0, len(clusters))
+
+	for _, cluster := range clusters {
+		representatives = append(representatives, cheapest(cluster))
+
+		if len(cluster) > 1 {
+			findings = append(findings, duplicateFinding(cluster))
+		}
+	}
+
+	findings = append(findings, overlapFindings(representatives)...)
+
+	for _, sub := range subs {
+		if sub.CatalogPrice != nil && sub.Currency == catalogCurrency && sub.Price > *sub.CatalogPrice {
+			findings = append(findings, models.FindingJSON{
+				Type:            models.FindingAboveCatalogPrice,
+				SubscriptionIDs: []int{sub.ID},
+				ServiceNames:    []string{sub.ServiceName},
+				Description: fmt.Sprintf("Цена %d выше цены каталога %d, проверьте тариф",
+					sub.Price, *sub.CatalogPrice),
+				AnnualSaving: (sub.Price - *sub.CatalogPrice) * seats(sub) * chargesPerYear(sub),
+			})
+		}
+	}
+
+	return findings
+}
+
+// TotalAnnualSaving sums the savings of the findings. Findings that share a subscription, like a duplicate
+// that is also above the catalog price, can't both be realized, so only the largest saving of such findings counts.
+func TotalAnnualSaving(findings []models.FindingJSON) int {
+	parent := make([]int, len(findings))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+
+		return parent[i]
+	}
+
+	bySubscription := make(map[int]int)
+
+	for i, finding := range findings {
+		for _, id := range finding.SubscriptionIDs {
+			if j, ok := bySubscription[id]; ok {
+				parent[find(i)] = find(j)
+
+				continue
+			}
+
+			bySubscription[id] = i
+		}
+	}
+
+	largest := make(map[int]int)
+	for i, finding := range findings {
+		root := find(i)
+		largest[root] = max(largest[root], finding.AnnualSaving)
+	}
+
+	total := 0
+	for _, saving := range largest {
+		total += saving
+	}
+
+	return total
+}
+
+func duplicateFinding(cluster []models.ActiveSubscription) models.FindingJSON {
+	finding := models.FindingJSON{
+		Type:         models.FindingDuplicate,
+		Description:  "Подписка на один и тот же сервис оформлена несколько раз",
+		AnnualSaving: totalAnnual(cluster) - annual(cheapest(cluster)),
+	}
+
+	for _, sub := range cluster {
+		finding.SubscriptionIDs = append(finding.SubscriptionIDs, sub.ID)
+		finding.ServiceNames = append(finding.ServiceNames, sub.ServiceName)
+
+		if normalize(sub.ServiceName) != normalize(cluster[0].ServiceName) || sub.Price != cluster[0].Price {
+			finding.Type = models.FindingNearDuplicate
+			finding.Description = "Похожие подписки, вероятно, на один и тот же сервис"
+		}
+	}
+
+	return finding
+}
+
+func overlapFindings(subs []models.ActiveSubscription) []models.FindingJSON {
+	byCategory := make(map[string][]models.ActiveSubscription)
+
+	for _, sub := range subs {
+		if sub.Category == nil {
+			continue
+		}
+
+		byCategory[*sub.Category] = append(byCategory[*sub.Category], sub)
+	}
+
+	categories := make([]string, 0, len(byCategory))
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+
+	sort.Strings(categories)
+
+	findings := []models.FindingJSON{}
+
+	for _, category := range categories {
+		group := byCategory[category]
+		if len(group) < 2 {
+			continue
+		}
+
+		mostExpensive := 0
+
+		finding := models.FindingJSON{
+			Type:        models.FindingOverlap,
+			Category:    category,
+			Description: fmt.Sprintf("Несколько подписок в категории %s, возможно, достаточно одной", category),
+		}
+
+		for _, sub := range group {
+			finding.SubscriptionIDs = append(finding.SubscriptionIDs, sub.ID)
+			finding.ServiceNames = append(finding.ServiceNames, sub.ServiceName)
+			mostExpensive = max(mostExpensive, annual(sub))
+		}
+
+		finding.AnnualSaving = totalAnnual(group) - mostExpensive
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+// clusterByName groups subscriptions whose normalized names are equal or close enough.
+func clusterByName(subs []models.ActiveSubscription) [][]models.ActiveSubscription {
+	parent := make([]int, len(subs))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+
+		return parent[i]
+	}
+
+	for i := range subs {
+		for j := i + 1; j < len(subs); j++ {
+			if similarNames(subs[i].ServiceName, subs[j].ServiceName) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	indexByRoot := make(map[int]int)
+	clusters := [][]models.ActiveSubscription{}
+
+	for i, sub := range subs {
+		root := find(i)
+
+		idx, ok := indexByRoot[root]
+		if !ok {
+			idx = len(clusters)
+			indexByRoot[root] = idx
+			clusters = append(clusters, nil)
+		}
+
+		clusters[idx] = append(clusters[idx], sub)
+	}
+
+	return clusters
+}
+
+func similarNames(a, b string) bool {
+	a, b = normalize(a), normalize(b)
+	if a == b {
+		return true
+	}
+
+	if min(len([]rune(a)), len([]rune(b))) < minFuzzyLength {
+		return false
+	}
+
+	return levenshtein(a, b) <= maxNameDistance
+}
+
+func normalize(name string) string {
+	var sb strings.Builder
+
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func cheapest(subs []models.ActiveSubscription) models.ActiveSubscription {
+	res := subs[0]
+
+	for _, sub := range subs[1:] {
+		if annual(sub) < annual(res) {
+			res = sub
+		}
+	}
+
+	return res
+}
+
+func totalAnnual(subs []models.ActiveSubscription) int {
+	total := 0
+
+	for _, sub := range subs {
+		total += annual(sub)
+	}
+
+	return total
+}
+
+func annual(sub models.ActiveSubscription) int {
+	return sub.Price * seats(sub) * chargesPerYear(sub)
+}
+
+// seats returns the seats of sub, a subscription without seats set has one.
+func seats(sub models.ActiveSubscription) int {
+	return max(sub.Seats, 1)
+}
+
+func chargesPerYear(sub models.ActiveSubscription) int {
+	interval, ok := models.BillingPeriodMonths[sub.BillingPeriod]
+	if !ok {
+		interval = 1
+	}
+
+	return 12 / interval
+}
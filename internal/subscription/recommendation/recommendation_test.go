@@ -0,0 +1,137 @@
+package recommendation_test
+
+import (
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/recommendation"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		subs        []models.ActiveSubscription
+		wantTypes   []string
+		wantSavings []int
+	}{
+		{
+			name: "NoWaste",
+			subs: []models.ActiveSubscription{
+				{ID: 1, ServiceName: "Netflix", Price: 799, Category: ptr("video"), CatalogPrice: ptr(799)},
+				{ID: 2, ServiceName: "Spotify", Price: 199, Category: ptr("music"), CatalogPrice: ptr(199)},
+			},
+		},
+		{
+			name: "ExactDuplicate",
+			subs: []models.ActiveSubscription{
+				{ID: 1, ServiceName: "iCloud", Price: 149},
+				{ID: 2, ServiceName: "icloud ", Price: 149},
+			},
+			wantTypes:   []string{models.FindingDuplicate},
+			wantSavings: []int{149 * 12},
+		},
+		{
+			name: "NearDuplicate",
+			subs: []models.ActiveSubscription{
+				{ID: 1, ServiceName: "Dropbox", Price: 999},
+				{ID: 2, ServiceName: "Dropbx", Price: 1099},
+			},
+			wantTypes:   []string{models.FindingNearDuplicate},
+			wantSavings: []int{1099 * 12},
+		},
+		{
+			name: "OverlapAndAboveCatalogPrice",
+			subs: []models.ActiveSubscription{
+				{ID: 1, ServiceName: "Spotify", Price: 249, Currency: "RUB", Category: ptr("music"), CatalogPrice: ptr(199)},
+				{ID: 2, ServiceName: "Yandex Music", Price: 299, Currency: "RUB", Category: ptr("music"), CatalogPrice: ptr(299)},
+				{ID: 3, ServiceName: "Apple Music", Price: 1690, BillingPeriod: models.BillingYearly, Category: ptr("music")},
+			},
+			wantTypes:   []string{models.FindingOverlap, models.FindingAboveCatalogPrice},
+			wantSavings: []int{249*12 + 1690, 50 * 12},
+		},
+		{
+			name: "CatalogPriceInOtherCurrency",
+			subs: []models.ActiveSubscription{
+				{ID: 1, ServiceName: "Spotify", Price: 999, Currency: "USD", CatalogPrice: ptr(199)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			findings := recommendation.Detect(tt.subs)
+
+			if len(findings) != len(tt.wantTypes) {
+				t.Fatalf("expected %d findings, got %+v", len(tt.wantTypes), findings)
+			}
+
+			for i, finding := range findings {
+				if finding.Type != tt.wantTypes[i] {
+					t.Errorf("finding %d: expected type %s, got %s", i, tt.wantTypes[i], finding.Type)
+				}
+
+				if finding.AnnualSaving != tt.wantSavings[i] {
+					t.Errorf("finding %d: expected saving %d, got %d", i, tt.wantSavings[i], finding.AnnualSaving)
+				}
+			}
+		})
+	}
+}
+
+func TestTotalAnnualSaving(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		findings []models.FindingJSON
+		want     int
+	}{
+		{name: "NoFindings"},
+		{
+			name: "SeparateSubscriptions",
+			findings: []models.FindingJSON{
+				{SubscriptionIDs: []int{1, 2}, AnnualSaving: 1000},
+				{SubscriptionIDs: []int{3}, AnnualSaving: 600},
+			},
+			want: 1600,
+		},
+		{
+			name: "SharedSubscriptionCountsOnce",
+			findings: []models.FindingJSON{
+				{Type: models.FindingDuplicate, SubscriptionIDs: []int{1, 2}, AnnualSaving: 1788},
+				{Type: models.FindingAboveCatalogPrice, SubscriptionIDs: []int{2}, AnnualSaving: 600},
+			},
+			want: 1788,
+		},
+		{
+			name: "LargerSavingWins",
+			findings: []models.FindingJSON{
+				{Type: models.FindingOverlap, SubscriptionIDs: []int{1, 2}, AnnualSaving: 2388},
+				{Type: models.FindingAboveCatalogPrice, SubscriptionIDs: []int{1}, AnnualSaving: 600},
+				{Type: models.FindingAboveCatalogPrice, SubscriptionIDs: []int{2}, AnnualSaving: 3000},
+				{Type: models.FindingAboveCatalogPrice, SubscriptionIDs: []int{4}, AnnualSaving: 120},
+			},
+			want: 3120,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := recommendation.TotalAnnualSaving(tt.findings); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package gql
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	DefaultMaxDepth      = 6
+	DefaultMaxComplexity = 200
+)
+
+type request struct {
+	Query         string         `json:"query"         query:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName" query:"operationName"`
+}
+
+type Handler struct {
+	schema        graphql.Schema
+	repo          Repository
+	costs         Costs
+	logger        *slog.Logger
+	maxDepth      int
+	maxComplexity int
+}
+
+func New(repo Repository, costs Costs, logger *slog.Logger, maxDepth, maxComplexity int) (*Handler, error) {
+	schema, err := newSchema(repo)
+	if err != nil {
+		return nil, fmt.Errorf("graphql schema: %w", err)
+	}
+
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+
+	if maxComplexity <= 0 {
+		maxComplexity = DefaultMaxComplexity
+	}
+
+	return &Handler{
+		schema:        schema,
+		repo:          repo,
+		costs:         costs,
+		logger:        logger,
+		maxDepth:      maxDepth,
+		maxComplexity: maxComplexity,
+	}, nil
+}
+
+// Serve executes a GraphQL query sent as JSON body or as query parameters.
+func (h *Handler) Serve(echo echo.Context) error {
+	h.logger.Debug("Get Request for GraphQL")
+
+	var req request
+
+	if err := echo.Bind(&req); err != nil || req.Query == "" {
+		return echo.JSON(http.StatusBadRequest, errorResult(fmt.Errorf("query is required")))
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query)})})
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, errorResult(err))
+	}
+
+	if err := checkLimits(doc, req.Variables, h.maxDepth, h.maxComplexity); err != nil {
+		return echo.JSON(http.StatusBadRequest, errorResult(err))
+	}
+
+	if validation := graphql.ValidateDocument(&h.schema, doc, nil); !validation.IsValid {
+		return echo.JSON(http.StatusBadRequest, &graphql.Result{Errors: validation.Errors})
+	}
+
+	ctx := context.WithValue(echo.Request().Context(), loadersKey{}, newLoaders(h.repo, h.costs))
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+
+	return echo.JSON(http.StatusOK, result)
+}
+
+func errorResult(err error) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)}}
+}
@@ -0,0 +1,275 @@
+package gql_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/gql"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type fakeRepository struct {
+	subscriptionCalls int
+	catalogCalls      int
+	costCalls         int
+	spendCalls        int
+	// catalogName is the name the catalog stores a service under, the requested one when nil.
+	catalogName func(string) string
+}
+
+func (r *fakeRepository) GetSubscriptionsByUserIDs(_ context.Context, ids []uuid.UUID) ([]models.Subscription, error) {
+	r.subscriptionCalls++
+
+	subs := make([]models.Subscription, 0, len(ids)*2)
+	for i, id := range ids {
+		subs = append(subs,
+			models.Subscription{ID: i*2 + 1, UserID: id, ServiceName: "Netflix", Price: 799, StartDate: time.Now()},
+			models.Subscription{ID: i*2 + 2, UserID: id, ServiceName: "Spotify", Price: 199, StartDate: time.Now()})
+	}
+
+	return subs, nil
+}
+
+func (r *fakeRepository) GetCatalogServices(_ context.Context) ([]models.CatalogService, error) {
+	return []models.CatalogService{{ServiceName: "Netflix", Category: "video"}}, nil
+}
+
+func (r *fakeRepository) GetCatalogServicesByNames(_ context.Context, names []string) ([]models.CatalogService, error) {
+	r.catalogCalls++
+
+	services := make([]models.CatalogService, 0, len(names))
+	for _, name := range names {
+		if r.catalogName != nil {
+			name = r.catalogName(name)
+		}
+
+		services = append(services, models.CatalogService{ServiceName: name, Category: "media"})
+	}
+
+	return services, nil
+}
+
+func (r *fakeRepository) GetSpendAnalytics(_ context.Context, _ models.SpendAnalyticsFilter) (models.SpendAnalyticsDB, error) {
+	return models.SpendAnalyticsDB{}, nil
+}
+
+func (r *fakeRepository) GetMonthlySpendByUserIDs(_ context.Context, ids []uuid.UUID, from, _ time.Time) ([]models.MonthlySpendDB, error) {
+	r.spendCalls++
+
+	spend := make([]models.MonthlySpendDB, 0, len(ids))
+	for _, id := range ids {
+		spend = append(spend, models.MonthlySpendDB{UserID: id, Month: from, Total: 998})
+	}
+
+	return spend, nil
+}
+
+func (r *fakeRepository) GetTotalPeriodCostsByUserIDs(_ context.Context, ids []uuid.UUID, _ models.SubscriptionListToCostJSON) (map[uuid.UUID]int, error) {
+	r.costCalls++
+
+	costs := make(map[uuid.UUID]int, len(ids))
+	for _, id := range ids {
+		costs[id] = 998
+	}
+
+	return costs, nil
+}
+
+func serve(t *testing.T, handler *gql.Handler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	if err := handler.Serve(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+
+	return rec
+}
+
+func TestGraphQLBatchesNestedLoads(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeRepository{}
+
+	handler, err := gql.New(repo, repo, slog.Default(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serve(t, handler, `{"query": "{ users(ids: [\"60601fee-2bf1-4721-ae6f-7636e79a0cba\", \"d4ae2ec1-3673-45c8-b823-7b28c99baff0\", \"7c9e6679-7425-40de-944b-e07fc1f90ae7\"]) `+
+		`{ id totalCost(startDate: \"01-2025\", endDate: \"12-2025\") monthlySpend(startDate: \"01-2025\", endDate: \"12-2025\") { month total } `+
+		`subscriptions { id serviceName catalog { category } } } }"}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if strings.Contains(rec.Body.String(), "errors") {
+		t.Fatalf("unexpected errors: %s", rec.Body.String())
+	}
+
+	if repo.subscriptionCalls != 1 || repo.catalogCalls != 1 || repo.costCalls != 1 || repo.spendCalls != 1 {
+		t.Errorf("expected one batched call per loader, got %d subscription, %d catalog, %d cost and %d spend calls",
+			repo.subscriptionCalls, repo.catalogCalls, repo.costCalls, repo.spendCalls)
+	}
+
+	if strings.Count(rec.Body.String(), `"category":"media"`) != 6 || strings.Count(rec.Body.String(), `"totalCost":998`) != 3 ||
+		strings.Count(rec.Body.String(), `"total":998`) != 3 {
+		t.Errorf("unexpected body %s", rec.Body.String())
+	}
+}
+
+func TestGraphQLMatchesCatalogIgnoringCase(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeRepository{catalogName: strings.ToUpper}
+
+	handler, err := gql.New(repo, repo, slog.Default(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serve(t, handler, `{"query": "{ user(id: \"60601fee-2bf1-4721-ae6f-7636e79a0cba\") { subscriptions { catalog { serviceName } } } }"}`)
+
+	if !strings.Contains(rec.Body.String(), `"serviceName":"NETFLIX"`) || !strings.Contains(rec.Body.String(), `"serviceName":"SPOTIFY"`) {
+		t.Errorf("expected the catalog entries whatever the case, got %s", rec.Body.String())
+	}
+}
+
+func TestGraphQLCapsMonthlySpend(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		endDate string
+		wantErr string
+	}{
+		{name: "MaxPeriod", endDate: "12-2034"},
+		{name: "TooLong", endDate: "01-2035", wantErr: gql.ErrPeriodTooLong.Error()},
+		{name: "EndBeforeStart", endDate: "12-2024", wantErr: "end date is before start date"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &fakeRepository{}
+
+			handler, err := gql.New(repo, repo, slog.Default(), 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := serve(t, handler, `{"query": "{ user(id: \"60601fee-2bf1-4721-ae6f-7636e79a0cba\") `+
+				`{ monthlySpend(startDate: \"01-2025\", endDate: \"`+tt.endDate+`\") { month } } }"}`)
+
+			if tt.wantErr == "" {
+				if strings.Contains(rec.Body.String(), "errors") || repo.spendCalls != 1 {
+					t.Errorf("expected the spend, got %s", rec.Body.String())
+				}
+
+				return
+			}
+
+			if !strings.Contains(rec.Body.String(), tt.wantErr) || repo.spendCalls != 0 {
+				t.Errorf("expected %q without loading the spend, got %s", tt.wantErr, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	t.Parallel()
+
+	handler, err := gql.New(&fakeRepository{}, &fakeRepository{}, slog.Default(), 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "WithinLimits",
+			body:       `{"query": "{ catalog { serviceName category } }"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "TooDeep",
+			body:       `{"query": "{ user(id: \"60601fee-2bf1-4721-ae6f-7636e79a0cba\") { subscriptions { catalog { category } } } }"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "TooComplex",
+			body:       `{"query": "{ a: catalog { serviceName category } b: catalog { serviceName category } }"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "WithinLimitsForIDs",
+			body:       `{"query": "{ users(ids: [\"a\", \"b\", \"c\", \"d\"]) { id } }"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "TooComplexForIDs",
+			body:       `{"query": "{ users(ids: [\"a\", \"b\", \"c\", \"d\", \"e\"]) { id } }"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "TooComplexForVariableIDs",
+			body: `{"query": "query($ids: [ID!]!) { users(ids: $ids) { id } }", ` +
+				`"variables": {"ids": ["a", "b", "c", "d", "e"]}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "DepthThroughFragments",
+			body:       `{"query": "{ user(id: \"60601fee-2bf1-4721-ae6f-7636e79a0cba\") { ...subs } } fragment subs on User { subscriptions { catalog { category } } }"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := serve(t, handler, tt.body)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGraphQLCapsIDs(t *testing.T) {
+	t.Parallel()
+
+	handler, err := gql.New(&fakeRepository{}, &fakeRepository{}, slog.Default(), 0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, gql.MaxIDs+1)
+	for i := range ids {
+		ids[i] = `\"` + uuid.NewString() + `\"`
+	}
+
+	rec := serve(t, handler, `{"query": "{ users(ids: [`+strings.Join(ids, ", ")+`]) { id } }"}`)
+
+	if !strings.Contains(rec.Body.String(), gql.ErrTooManyIDs.Error()) {
+		t.Errorf("expected the ids to be capped, got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,117 @@
+package gql
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+var (
+	ErrQueryTooDeep    = errors.New("query is too deep")
+	ErrQueryTooComplex = errors.New("query is too complex")
+	ErrTooManyIDs      = errors.New("too many ids")
+	ErrPeriodTooLong   = errors.New("period is too long")
+)
+
+// listArgument is the argument of the fields resolving a list of objects by id, MaxIDs caps its size.
+const (
+	listArgument = "ids"
+	MaxIDs       = 100
+)
+
+// MaxSpendMonths caps the months of a monthlySpend period, every one of them is a row per user.
+const MaxSpendMonths = 120
+
+// checkLimits rejects documents whose selections are nested deeper than maxDepth or that select
+// more than maxComplexity fields in total, fragments included. The selections under a field taking a
+// list of ids count once per id, the variables give the lists passed through them.
+func checkLimits(doc *ast.Document, variables map[string]any, maxDepth, maxComplexity int) error {
+	fragments := make(map[string]*ast.FragmentDefinition)
+
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, def := range doc.Definitions {
+		operation, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		m := measurer{fragments: fragments, variables: variables, visiting: map[string]bool{}}
+		depth, complexity := m.measure(operation.SelectionSet)
+
+		if depth > maxDepth {
+			return fmt.Errorf("%w: depth %d exceeds %d", ErrQueryTooDeep, depth, maxDepth)
+		}
+
+		if complexity > maxComplexity {
+			return fmt.Errorf("%w: complexity %d exceeds %d", ErrQueryTooComplex, complexity, maxComplexity)
+		}
+	}
+
+	return nil
+}
+
+// measurer walks the selections of an operation, visiting guards against fragments spreading themselves.
+type measurer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	visiting  map[string]bool
+}
+
+func (m measurer) measure(set *ast.SelectionSet) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+
+		switch node := selection.(type) {
+		case *ast.Field:
+			d, c = m.measure(node.SelectionSet)
+			d++
+			c = 1 + m.listSize(node)*c
+		case *ast.InlineFragment:
+			d, c = m.measure(node.SelectionSet)
+		case *ast.FragmentSpread:
+			fragment, ok := m.fragments[node.Name.Value]
+			if !ok || m.visiting[node.Name.Value] {
+				continue
+			}
+
+			m.visiting[node.Name.Value] = true
+			d, c = m.measure(fragment.SelectionSet)
+			m.visiting[node.Name.Value] = false
+		}
+
+		depth = max(depth, d)
+		complexity += c
+	}
+
+	return depth, complexity
+}
+
+// listSize is the number of ids the field is asked for, 1 for the fields without them.
+func (m measurer) listSize(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name == nil || arg.Name.Value != listArgument {
+			continue
+		}
+
+		switch value := arg.Value.(type) {
+		case *ast.ListValue:
+			return max(len(value.Values), 1)
+		case *ast.Variable:
+			if list, ok := m.variables[value.Name.Value].([]any); ok {
+				return max(len(list), 1)
+			}
+		}
+	}
+
+	return 1
+}
@@ -0,0 +1,54 @@
+package gql
+
+import (
+	"context"
+	"sync"
+)
+
+// loader batches the keys requested by resolvers of one query level. Load only records the key
+// and returns a thunk; graphql-go resolves thunks breadth first, so the first thunk of a level
+// fetches every key collected so far with a single call.
+type loader[K comparable, V any] struct {
+	fetch   func(ctx context.Context, keys []K) (map[K]V, error)
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	cache   map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:  fetch,
+		queued: make(map[K]bool),
+		cache:  make(map[K]V),
+		errs:   make(map[K]error),
+	}
+}
+
+func (l *loader[K, V]) Load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+
+			res, err := l.fetch(ctx, keys)
+			for _, k := range keys {
+				l.cache[k] = res[k]
+				l.errs[k] = err
+			}
+		}
+
+		return l.cache[key], l.errs[key]
+	}
+}
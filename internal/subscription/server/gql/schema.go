@@ -0,0 +1,418 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+)
+
+type Repository interface {
+	storage.CatalogRepository
+	storage.AnalyticsRepository
+}
+
+// Costs computes the totalCost field, the rules of the cost live in the service package. The costs of the users
+// asked for with the same arguments are computed together.
+type Costs interface {
+	GetTotalPeriodCostsByUserIDs(ctx context.Context, ids []uuid.UUID, subList models.SubscriptionListToCostJSON) (map[uuid.UUID]int, error)
+}
+
+type loadersKey struct{}
+
+// loaders are created per request so batching and caching never leak between queries.
+type loaders struct {
+	subscriptions *loader[uuid.UUID, []models.Subscription]
+	catalog       *loader[string, *models.CatalogService]
+	costs         *loader[costKey, costResult]
+	monthlySpend  *loader[spendKey, []models.MonthlySpendDB]
+}
+
+// costArgs are the arguments of a totalCost field, services joins the service names with costSeparator.
+type costArgs struct {
+	startDate, endDate, services string
+}
+
+const costSeparator = "\x00"
+
+type costKey struct {
+	user uuid.UUID
+	args costArgs
+}
+
+// costResult carries the error of its arguments, invalid arguments of one field don't fail the others.
+type costResult struct {
+	cost int
+	err  error
+}
+
+type spendKey struct {
+	user     uuid.UUID
+	from, to time.Time
+}
+
+func newLoaders(repo Repository, costs Costs) *loaders {
+	return &loaders{
+		costs: newLoader(func(ctx context.Context, keys []costKey) (map[costKey]costResult, error) {
+			users := make(map[costArgs][]uuid.UUID)
+			for _, k := range keys {
+				users[k.args] = append(users[k.args], k.user)
+			}
+
+			res := make(map[costKey]costResult, len(keys))
+
+			for args, ids := range users {
+				subList := models.SubscriptionListToCostJSON{StartDate: args.startDate, EndDate: args.endDate}
+				if args.services != "" {
+					subList.ServiceName = strings.Split(args.services, costSeparator)
+				}
+
+				totals, err := costs.GetTotalPeriodCostsByUserIDs(ctx, ids, subList)
+				for _, id := range ids {
+					res[costKey{user: id, args: args}] = costResult{cost: totals[id], err: err}
+				}
+			}
+
+			return res, nil
+		}),
+		monthlySpend: newLoader(func(ctx context.Context, keys []spendKey) (map[spendKey][]models.MonthlySpendDB, error) {
+			type period struct{ from, to time.Time }
+
+			users := make(map[period][]uuid.UUID)
+			for _, k := range keys {
+				users[period{k.from, k.to}] = append(users[period{k.from, k.to}], k.user)
+			}
+
+			res := make(map[spendKey][]models.MonthlySpendDB, len(keys))
+
+			for p, ids := range users {
+				spend, err := repo.GetMonthlySpendByUserIDs(ctx, ids, p.from, p.to)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, month := range spend {
+					k := spendKey{user: month.UserID, from: p.from, to: p.to}
+					res[k] = append(res[k], month)
+				}
+			}
+
+			return res, nil
+		}),
+		subscriptions: newLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]models.Subscription, error) {
+			subs, err := repo.GetSubscriptionsByUserIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			res := make(map[uuid.UUID][]models.Subscription, len(ids))
+			for _, sub := range subs {
+				res[sub.UserID] = append(res[sub.UserID], sub)
+			}
+
+			return res, nil
+		}),
+		catalog: newLoader(func(ctx context.Context, names []string) (map[string]*models.CatalogService, error) {
+			services, err := repo.GetCatalogServicesByNames(ctx, names)
+			if err != nil {
+				return nil, err
+			}
+
+			// The entries match the names whatever the case, so they are handed back under the requested names.
+			byName := make(map[string]*models.CatalogService, len(services))
+			for i := range services {
+				byName[strings.ToLower(services[i].ServiceName)] = &services[i]
+			}
+
+			res := make(map[string]*models.CatalogService, len(names))
+			for _, name := range names {
+				if service, ok := byName[strings.ToLower(name)]; ok {
+					res[name] = service
+				}
+			}
+
+			return res, nil
+		}),
+	}
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// user is the source value of the User type, every field is resolved lazily from its id.
+type user struct {
+	ID uuid.UUID
+}
+
+func newSchema(repo Repository) (graphql.Schema, error) {
+	catalogType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CatalogService",
+		Fields: graphql.Fields{
+			"serviceName": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: catalogField(
+				func(s *models.CatalogService) any { return s.ServiceName })},
+			"category": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: catalogField(
+				func(s *models.CatalogService) any { return s.Category })},
+			"price": &graphql.Field{Type: graphql.Int, Resolve: catalogField(
+				func(s *models.CatalogService) any { return s.Price })},
+		},
+	})
+
+	subscriptionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: subscriptionField(
+				func(s models.Subscription) any { return s.ID })},
+			"serviceName": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: subscriptionField(
+				func(s models.Subscription) any { return s.ServiceName })},
+			"price": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: subscriptionField(
+				func(s models.Subscription) any { return s.Price })},
+			"seats": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: subscriptionField(
+				func(s models.Subscription) any { return max(s.Seats, 1) })},
+			"currency": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: subscriptionField(
+				func(s models.Subscription) any { return s.Currency })},
+			"billingPeriod": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: subscriptionField(
+				func(s models.Subscription) any { return s.BillingPeriod })},
+			"startDate": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: subscriptionField(
+				func(s models.Subscription) any { return s.StartDate.Format(models.MonthLayout) })},
+			"endDate": &graphql.Field{Type: graphql.String, Resolve: subscriptionField(
+				func(s models.Subscription) any { return formatOptionalMonth(s.EndDate) })},
+			"trialEndDate": &graphql.Field{Type: graphql.String, Resolve: subscriptionField(
+				func(s models.Subscription) any { return formatOptionalMonth(s.TrialEndDate) })},
+			"catalog": &graphql.Field{
+				Type: catalogType,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					sub, _ := p.Source.(models.Subscription)
+					thunk := loadersFrom(p.Context).catalog.Load(p.Context, sub.ServiceName)
+
+					return func() (any, error) {
+						service, err := thunk()
+						if err != nil || service == nil {
+							return nil, err
+						}
+
+						return service, nil
+					}, nil
+				},
+			},
+		},
+	})
+
+	monthlySpendType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "MonthlySpend",
+		Fields: graphql.Fields{
+			"month": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"total": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"delta": &graphql.Field{Type: graphql.Int},
+		},
+	})
+
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(user).ID.String(), nil
+				},
+			},
+			"subscriptions": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(subscriptionType))),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					thunk := loadersFrom(p.Context).subscriptions.Load(p.Context, p.Source.(user).ID)
+
+					return func() (any, error) {
+						subs, err := thunk()
+						if err != nil {
+							return nil, err
+						}
+
+						if subs == nil {
+							subs = []models.Subscription{}
+						}
+
+						return subs, nil
+					}, nil
+				},
+			},
+			"totalCost": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Args: graphql.FieldConfigArgument{
+					"startDate":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"endDate":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"serviceNames": &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					args := costArgs{startDate: p.Args["startDate"].(string), endDate: p.Args["endDate"].(string)}
+
+					if names, ok := p.Args["serviceNames"].([]any); ok && len(names) > 0 {
+						services := make([]string, 0, len(names))
+						for _, name := range names {
+							services = append(services, name.(string))
+						}
+
+						args.services = strings.Join(services, costSeparator)
+					}
+
+					thunk := loadersFrom(p.Context).costs.Load(p.Context, costKey{user: p.Source.(user).ID, args: args})
+
+					return func() (any, error) {
+						res, err := thunk()
+						if err != nil {
+							return nil, err
+						}
+
+						return res.cost, res.err
+					}, nil
+				},
+			},
+			"monthlySpend": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(monthlySpendType))),
+				Args: graphql.FieldConfigArgument{
+					"startDate": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"endDate":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: resolveMonthlySpend,
+			},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"user": &graphql.Field{
+				Type: userType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := uuid.Parse(p.Args["id"].(string))
+					if err != nil {
+						return nil, fmt.Errorf("invalid user id: %w", err)
+					}
+
+					return user{ID: id}, nil
+				},
+			},
+			"users": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+				Args: graphql.FieldConfigArgument{
+					"ids": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					ids, _ := p.Args["ids"].([]any)
+					if len(ids) > MaxIDs {
+						return nil, fmt.Errorf("%w: %d exceeds %d", ErrTooManyIDs, len(ids), MaxIDs)
+					}
+
+					users := make([]user, 0, len(ids))
+
+					for _, v := range ids {
+						id, err := uuid.Parse(v.(string))
+						if err != nil {
+							return nil, fmt.Errorf("invalid user id: %w", err)
+						}
+
+						users = append(users, user{ID: id})
+					}
+
+					return users, nil
+				},
+			},
+			"catalog": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(catalogType))),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					services, err := repo.GetCatalogServices(p.Context)
+					if err != nil {
+						return nil, err
+					}
+
+					res := make([]*models.CatalogService, 0, len(services))
+					for i := range services {
+						res = append(res, &services[i])
+					}
+
+					return res, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+func resolveMonthlySpend(p graphql.ResolveParams) (any, error) {
+	startDate, err := time.Parse(models.MonthLayout, p.Args["startDate"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+
+	endDate, err := time.Parse(models.MonthLayout, p.Args["endDate"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
+
+	if endDate.Before(startDate) {
+		return nil, errors.New("end date is before start date")
+	}
+
+	period := (endDate.Year()-startDate.Year())*12 + int(endDate.Month()) - int(startDate.Month()) + 1
+	if period > MaxSpendMonths {
+		return nil, fmt.Errorf("%w: %d months exceed %d", ErrPeriodTooLong, period, MaxSpendMonths)
+	}
+
+	thunk := loadersFrom(p.Context).monthlySpend.Load(p.Context,
+		spendKey{user: p.Source.(user).ID, from: startDate, to: endDate})
+
+	return func() (any, error) {
+		spend, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+
+		months := make([]map[string]any, 0, len(spend))
+
+		for _, month := range spend {
+			months = append(months, map[string]any{
+				"month": month.Month.Format(models.MonthLayout),
+				"total": month.Total,
+				"delta": month.Delta,
+			})
+		}
+
+		return months, nil
+	}, nil
+}
+
+func subscriptionField(get func(models.Subscription) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		sub, _ := p.Source.(models.Subscription)
+
+		return get(sub), nil
+	}
+}
+
+func catalogField(get func(*models.CatalogService) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		service, _ := p.Source.(*models.CatalogService)
+		if service == nil {
+			return nil, nil
+		}
+
+		return get(service), nil
+	}
+}
+
+func formatOptionalMonth(date *time.Time) any {
+	if date == nil {
+		return nil
+	}
+
+	return date.Format(models.MonthLayout)
+}
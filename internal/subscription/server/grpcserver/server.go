@@ -0,0 +1,108 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	subscriptionv1 "github.com/Ostmind/subscriptionservice/api/subscription/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	server   *grpc.Server
+	health   *health.Server
+	logger   *slog.Logger
+	listener net.Listener
+}
+
+func New(subscriptions subscriptionManager, logger *slog.Logger) *Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(logRequest(logger)))
+
+	subscriptionv1.RegisterSubscriptionServiceServer(server, NewSubscriptionService(subscriptions, logger))
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	healthServer.SetServingStatus(subscriptionv1.SubscriptionService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	reflection.Register(server)
+
+	return &Server{
+		server: server,
+		health: healthServer,
+		logger: logger,
+	}
+}
+
+// Start listens on serverHost:serverPort and serves in the background. The listener is open when Start
+// returns, so a busy port fails it.
+func (s *Server) Start(serverHost string, serverPort int) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", serverHost, serverPort))
+	if err != nil {
+		return fmt.Errorf("error listening on gRPC port %w", err)
+	}
+
+	s.listener = listener
+
+	s.logger.Info("gRPC server is running on", "Address", listener.Addr().String())
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			s.logger.Error("gRPC server serving error: %v", slog.Any("error_details", err))
+		}
+	}()
+
+	return nil
+}
+
+// Addr returns the address the server listens on, once started.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Stop marks the server as not serving, waits for in-flight calls to finish
+// and closes the connections forcibly once ctx expires.
+func (s *Server) Stop(ctx context.Context) error {
+	s.logger.Info("Stopping gRPC server...")
+
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+
+		return fmt.Errorf("error while stopping gRPC Server %w", ctx.Err())
+	}
+}
+
+func logRequest(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		stop := time.Now()
+
+		logger.Info("Request: ",
+			"Method", info.FullMethod,
+			"Time", stop.Sub(start),
+			"gRPC Code", status.Code(err).String())
+
+		return resp, err
+	}
+}
@@ -0,0 +1,565 @@
+package grpcserver_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	subscriptionv1 "github.com/Ostmind/subscriptionservice/api/subscription/v1"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/grpcserver"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+func newClient(t *testing.T, manager *mock_server.MocksubscriptionManager) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	subscriptionv1.RegisterSubscriptionServiceServer(server, grpcserver.NewSubscriptionService(manager, slog.Default()))
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestListSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.MustParse("d4ae2ec1-3673-45c8-b823-7b28c99baff0")
+	organizationID := 3
+
+	tests := []struct {
+		name      string
+		userID    string
+		mockSetup func(m *mock_server.MocksubscriptionManager)
+		wantCode  codes.Code
+		wantCount int
+		wantFirst *subscriptionv1.Subscription
+	}{
+		{
+			name:   "Success",
+			userID: userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				endDate := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+				trialEndDate := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+				m.EXPECT().
+					GetSubscriptionListByUserID(gomock.Any(), userID).
+					Return([]models.Subscription{{
+						ID:             7,
+						UserID:         userID,
+						StartDate:      time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+						EndDate:        &endDate,
+						TrialEndDate:   &trialEndDate,
+						Price:          400,
+						Currency:       "RUB",
+						BillingPeriod:  models.BillingMonthly,
+						ServiceName:    "Netflix",
+						Seats:          5,
+						OrganizationID: &organizationID,
+						Department:     "Разработка",
+					}}, nil)
+			},
+			wantCode:  codes.OK,
+			wantCount: 1,
+			wantFirst: &subscriptionv1.Subscription{
+				Id:             7,
+				UserId:         userID.String(),
+				StartDate:      "09-2025",
+				EndDate:        "08-2026",
+				TrialEndDate:   "10-2025",
+				Price:          400,
+				Currency:       "RUB",
+				BillingPeriod:  models.BillingMonthly,
+				ServiceName:    "Netflix",
+				Seats:          5,
+				OrganizationId: proto.Int64(3),
+				Department:     "Разработка",
+			},
+		},
+		{
+			name:      "InvalidArgument",
+			userID:    "not-a-uuid",
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {},
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			name:   "NotFound",
+			userID: userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().
+					GetSubscriptionListByUserID(gomock.Any(), userID).
+					Return(nil, models.ErrNotFound)
+			},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMocksubscriptionManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			client := subscriptionv1.NewSubscriptionServiceClient(newClient(t, mockManager))
+
+			resp, err := client.ListSubscriptions(context.Background(), &subscriptionv1.ListSubscriptionsRequest{UserId: tt.userID})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("expected code %s, got %v", tt.wantCode, err)
+			}
+
+			if len(resp.GetSubscriptions()) != tt.wantCount {
+				t.Errorf("expected %d subscriptions, got %d", tt.wantCount, len(resp.GetSubscriptions()))
+			}
+
+			if tt.wantFirst != nil && !proto.Equal(resp.GetSubscriptions()[0], tt.wantFirst) {
+				t.Errorf("expected %v, got %v", tt.wantFirst, resp.GetSubscriptions()[0])
+			}
+		})
+	}
+}
+
+func TestCreateSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.MustParse("d4ae2ec1-3673-45c8-b823-7b28c99baff0")
+	netflix := &subscriptionv1.Subscription{UserId: userID.String(), StartDate: "09-2025", Price: 400, ServiceName: "Netflix"}
+
+	tests := []struct {
+		name      string
+		sub       *subscriptionv1.Subscription
+		mockSetup func(m *mock_server.MocksubscriptionManager)
+		wantCode  codes.Code
+	}{
+		{
+			name: "Success",
+			sub:  netflix,
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().PostSubscription(gomock.Any(), models.SubscriptionListJSON{
+					UserID: userID, StartDate: "09-2025", Price: 400, ServiceName: "Netflix",
+				}).Return(nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:      "InvalidArgument_NoSubscription",
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {},
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			name: "InvalidArgument_Validation",
+			sub:  netflix,
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().PostSubscription(gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("%w: price is negative", models.ErrInvalidSubscription))
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "AlreadyExists",
+			sub:  netflix,
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().PostSubscription(gomock.Any(), gomock.Any()).Return(models.ErrUnique)
+			},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name: "Unavailable",
+			sub:  netflix,
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().PostSubscription(gomock.Any(), gomock.Any()).Return(models.ErrUnavailable)
+			},
+			wantCode: codes.Unavailable,
+		},
+		{
+			name: "Internal",
+			sub:  netflix,
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().PostSubscription(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMocksubscriptionManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			client := subscriptionv1.NewSubscriptionServiceClient(newClient(t, mockManager))
+
+			_, err := client.CreateSubscription(context.Background(),
+				&subscriptionv1.CreateSubscriptionRequest{Subscription: tt.sub})
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("expected code %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestUpdateSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.MustParse("d4ae2ec1-3673-45c8-b823-7b28c99baff0")
+	netflix := &subscriptionv1.Subscription{UserId: userID.String(), StartDate: "09-2025", Price: 400, ServiceName: "Netflix"}
+
+	tests := []struct {
+		name      string
+		id        int64
+		cookie    string
+		mockSetup func(m *mock_server.MocksubscriptionManager)
+		wantCode  codes.Code
+	}{
+		{
+			name:   "Success",
+			id:     1,
+			cookie: "userId=" + userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().UpdateSubscription(gomock.Any(), userID, gomock.Any(), 1).Return(nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:      "Unauthenticated_NoCookie",
+			id:        1,
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {},
+			wantCode:  codes.Unauthenticated,
+		},
+		{
+			name:      "Unauthenticated_InvalidCookie",
+			id:        1,
+			cookie:    "userId=not-a-uuid",
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {},
+			wantCode:  codes.Unauthenticated,
+		},
+		{
+			name:      "InvalidArgument_ID",
+			cookie:    "userId=" + userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {},
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			name:   "NotFound",
+			id:     2,
+			cookie: "theme=dark; userId=" + userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().UpdateSubscription(gomock.Any(), userID, gomock.Any(), 2).Return(models.ErrNotFound)
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:   "PermissionDenied",
+			id:     1,
+			cookie: "userId=" + userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().UpdateSubscription(gomock.Any(), userID, gomock.Any(), 1).Return(models.ErrForbidden)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "Unavailable",
+			id:     1,
+			cookie: "userId=" + userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().UpdateSubscription(gomock.Any(), userID, gomock.Any(), 1).Return(models.ErrUnavailable)
+			},
+			wantCode: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMocksubscriptionManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			client := subscriptionv1.NewSubscriptionServiceClient(newClient(t, mockManager))
+
+			ctx := context.Background()
+			if tt.cookie != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "cookie", tt.cookie)
+			}
+
+			_, err := client.UpdateSubscription(ctx, &subscriptionv1.UpdateSubscriptionRequest{Id: tt.id, Subscription: netflix})
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("expected code %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestUpdateSubscriptionKeepsSeatsAndOrganization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.MustParse("d4ae2ec1-3673-45c8-b823-7b28c99baff0")
+	organizationID := 3
+
+	mockManager := mock_server.NewMocksubscriptionManager(ctrl)
+	mockManager.EXPECT().UpdateSubscription(gomock.Any(), userID, models.SubscriptionListJSON{
+		UserID:         userID,
+		StartDate:      "09-2025",
+		Price:          400,
+		Seats:          5,
+		ServiceName:    "Slack",
+		OrganizationID: &organizationID,
+		Department:     "Разработка",
+	}, 1).Return(nil)
+
+	client := subscriptionv1.NewSubscriptionServiceClient(newClient(t, mockManager))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "cookie", "userId="+userID.String())
+
+	_, err := client.UpdateSubscription(ctx, &subscriptionv1.UpdateSubscriptionRequest{
+		Id: 1,
+		Subscription: &subscriptionv1.Subscription{
+			UserId:         userID.String(),
+			StartDate:      "09-2025",
+			Price:          400,
+			Seats:          5,
+			ServiceName:    "Slack",
+			OrganizationId: proto.Int64(3),
+			Department:     "Разработка",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.MustParse("d4ae2ec1-3673-45c8-b823-7b28c99baff0")
+
+	tests := []struct {
+		name      string
+		id        int64
+		cookie    string
+		mockSetup func(m *mock_server.MocksubscriptionManager)
+		wantCode  codes.Code
+	}{
+		{
+			name:   "Success",
+			id:     1,
+			cookie: "userId=" + userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().DeleteSubscription(gomock.Any(), userID, 1).Return(nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:      "Unauthenticated",
+			id:        1,
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {},
+			wantCode:  codes.Unauthenticated,
+		},
+		{
+			name:      "InvalidArgument_ID",
+			id:        -1,
+			cookie:    "userId=" + userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {},
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			name:   "NotFound",
+			id:     2,
+			cookie: "userId=" + userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().DeleteSubscription(gomock.Any(), userID, 2).Return(models.ErrNotFound)
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:   "Unavailable",
+			id:     1,
+			cookie: "userId=" + userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().DeleteSubscription(gomock.Any(), userID, 1).Return(models.ErrUnavailable)
+			},
+			wantCode: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMocksubscriptionManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			client := subscriptionv1.NewSubscriptionServiceClient(newClient(t, mockManager))
+
+			ctx := context.Background()
+			if tt.cookie != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "cookie", tt.cookie)
+			}
+
+			_, err := client.DeleteSubscription(ctx, &subscriptionv1.DeleteSubscriptionRequest{Id: tt.id})
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("expected code %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestGetPeriodCost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.MustParse("d4ae2ec1-3673-45c8-b823-7b28c99baff0")
+
+	tests := []struct {
+		name      string
+		userID    string
+		mockSetup func(m *mock_server.MocksubscriptionManager)
+		wantCode  codes.Code
+		wantTotal int64
+	}{
+		{
+			name:   "Success",
+			userID: userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().GetTotalPeriodCostByDatesAndServiceName(gomock.Any(), models.SubscriptionListToCostJSON{
+					UserID: userID, StartDate: "01-2025", EndDate: "03-2025", ServiceName: []string{"Netflix"},
+				}).Return(1200, nil)
+			},
+			wantCode:  codes.OK,
+			wantTotal: 1200,
+		},
+		{
+			name:      "InvalidArgument_UserID",
+			userID:    "not-a-uuid",
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {},
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			name:   "InvalidArgument_Period",
+			userID: userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().GetTotalPeriodCostByDatesAndServiceName(gomock.Any(), gomock.Any()).
+					Return(0, fmt.Errorf("%w: end_date is before start_date", models.ErrInvalidSubscription))
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:   "NotFound",
+			userID: userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().GetTotalPeriodCostByDatesAndServiceName(gomock.Any(), gomock.Any()).
+					Return(0, models.ErrNotFound)
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:   "Unavailable",
+			userID: userID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().GetTotalPeriodCostByDatesAndServiceName(gomock.Any(), gomock.Any()).
+					Return(0, models.ErrUnavailable)
+			},
+			wantCode: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMocksubscriptionManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			client := subscriptionv1.NewSubscriptionServiceClient(newClient(t, mockManager))
+
+			resp, err := client.GetPeriodCost(context.Background(), &subscriptionv1.GetPeriodCostRequest{
+				UserId: tt.userID, StartDate: "01-2025", EndDate: "03-2025", ServiceNames: []string{"Netflix"},
+			})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("expected code %s, got %v", tt.wantCode, err)
+			}
+
+			if resp.GetTotal() != tt.wantTotal {
+				t.Errorf("expected total %d, got %d", tt.wantTotal, resp.GetTotal())
+			}
+		})
+	}
+}
+
+func TestServerHealthAndGracefulStop(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := grpcserver.New(mock_server.NewMocksubscriptionManager(ctrl), slog.Default())
+
+	if err := server.Start("127.0.0.1", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := grpc.NewClient(server.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: subscriptionv1.SubscriptionService_ServiceDesc.ServiceName,
+	}, grpc.WaitForReady(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected SERVING, got %s", resp.GetStatus())
+	}
+
+	if err := server.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
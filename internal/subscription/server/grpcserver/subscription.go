@@ -0,0 +1,247 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	subscriptionv1 "github.com/Ostmind/subscriptionservice/api/subscription/v1"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/middleware"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type subscriptionManager interface {
+	GetSubscriptionListByUserID(ctx context.Context, id uuid.UUID) ([]models.Subscription, error)
+	PostSubscription(ctx context.Context, sub models.SubscriptionListJSON) error
+	UpdateSubscription(ctx context.Context, userID uuid.UUID, sub models.SubscriptionListJSON, id int) error
+	DeleteSubscription(ctx context.Context, userID uuid.UUID, id int) error
+	GetTotalPeriodCostByDatesAndServiceName(ctx context.Context, subList models.SubscriptionListToCostJSON) (int, error)
+}
+
+type subscriptionService struct {
+	subscriptionv1.UnimplementedSubscriptionServiceServer
+
+	manager subscriptionManager
+	logger  *slog.Logger
+}
+
+func NewSubscriptionService(manager subscriptionManager, log *slog.Logger) subscriptionv1.SubscriptionServiceServer {
+	return &subscriptionService{manager: manager, logger: log}
+}
+
+func (svc *subscriptionService) ListSubscriptions(ctx context.Context, req *subscriptionv1.ListSubscriptionsRequest,
+) (*subscriptionv1.ListSubscriptionsResponse, error) {
+	svc.logger.Debug("Get gRPC Request for Subscription List")
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	res, err := svc.manager.GetSubscriptionListByUserID(ctx, userID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &subscriptionv1.ListSubscriptionsResponse{
+		Subscriptions: make([]*subscriptionv1.Subscription, 0, len(res)),
+	}
+
+	for _, v := range res {
+		sub := &subscriptionv1.Subscription{
+			Id:            int64(v.ID),
+			UserId:        v.UserID.String(),
+			StartDate:     v.StartDate.Format(models.MonthLayout),
+			EndDate:       formatOptionalMonth(v.EndDate),
+			TrialEndDate:  formatOptionalMonth(v.TrialEndDate),
+			Price:         int64(v.Price),
+			Currency:      v.Currency,
+			BillingPeriod: v.BillingPeriod,
+			ServiceName:   v.ServiceName,
+			Seats:         int64(v.Seats),
+			Department:    v.Department,
+		}
+
+		if v.OrganizationID != nil {
+			sub.OrganizationId = proto.Int64(int64(*v.OrganizationID))
+		}
+
+		resp.Subscriptions = append(resp.Subscriptions, sub)
+	}
+
+	return resp, nil
+}
+
+func (svc *subscriptionService) CreateSubscription(ctx context.Context, req *subscriptionv1.CreateSubscriptionRequest,
+) (*subscriptionv1.CreateSubscriptionResponse, error) {
+	svc.logger.Debug("Get gRPC Request for Create Subscription")
+
+	sub, err := fromProto(req.GetSubscription())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.manager.PostSubscription(ctx, sub); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &subscriptionv1.CreateSubscriptionResponse{}, nil
+}
+
+func (svc *subscriptionService) UpdateSubscription(ctx context.Context, req *subscriptionv1.UpdateSubscriptionRequest,
+) (*subscriptionv1.UpdateSubscriptionResponse, error) {
+	svc.logger.Debug("Get gRPC Request for Update Subscription")
+
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := fromProto(req.GetSubscription())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.manager.UpdateSubscription(ctx, userID, sub, int(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &subscriptionv1.UpdateSubscriptionResponse{}, nil
+}
+
+func (svc *subscriptionService) DeleteSubscription(ctx context.Context, req *subscriptionv1.DeleteSubscriptionRequest,
+) (*subscriptionv1.DeleteSubscriptionResponse, error) {
+	svc.logger.Debug("Get gRPC Request for Delete Subscription")
+
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.manager.DeleteSubscription(ctx, userID, int(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &subscriptionv1.DeleteSubscriptionResponse{}, nil
+}
+
+func (svc *subscriptionService) GetPeriodCost(ctx context.Context, req *subscriptionv1.GetPeriodCostRequest,
+) (*subscriptionv1.GetPeriodCostResponse, error) {
+	svc.logger.Debug("Get gRPC Request for Subscription Cost")
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	res, err := svc.manager.GetTotalPeriodCostByDatesAndServiceName(ctx, models.SubscriptionListToCostJSON{
+		UserID:      userID,
+		StartDate:   req.GetStartDate(),
+		EndDate:     req.GetEndDate(),
+		ServiceName: req.GetServiceNames(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &subscriptionv1.GetPeriodCostResponse{Total: int64(res)}, nil
+}
+
+func fromProto(sub *subscriptionv1.Subscription) (models.SubscriptionListJSON, error) {
+	if sub == nil {
+		return models.SubscriptionListJSON{}, status.Error(codes.InvalidArgument, "subscription is required")
+	}
+
+	userID, err := uuid.Parse(sub.GetUserId())
+	if err != nil {
+		return models.SubscriptionListJSON{}, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	res := models.SubscriptionListJSON{
+		UserID:        userID,
+		StartDate:     sub.GetStartDate(),
+		EndDate:       sub.GetEndDate(),
+		TrialEndDate:  sub.GetTrialEndDate(),
+		Price:         int(sub.GetPrice()),
+		Seats:         int(sub.GetSeats()),
+		Currency:      sub.GetCurrency(),
+		BillingPeriod: sub.GetBillingPeriod(),
+		ServiceName:   sub.GetServiceName(),
+		Department:    sub.GetDepartment(),
+	}
+
+	if sub.OrganizationId != nil {
+		organizationID := int(sub.GetOrganizationId())
+		res.OrganizationID = &organizationID
+	}
+
+	return res, nil
+}
+
+// formatOptionalMonth formats a date that may be unset, an empty string for nil.
+func formatOptionalMonth(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+
+	return date.Format(models.MonthLayout)
+}
+
+// callerID returns the user making the request from the userId cookie in the metadata, Unauthenticated
+// without it. The gRPC clients send the cookie as a gateway forwarding the HTTP requests does.
+func callerID(ctx context.Context) (uuid.UUID, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	for _, header := range md.Get("cookie") {
+		cookies, err := http.ParseCookie(header)
+		if err != nil {
+			continue
+		}
+
+		for _, cookie := range cookies {
+			if cookie.Name != middleware.UserIDCookie {
+				continue
+			}
+
+			if userID, err := uuid.Parse(cookie.Value); err == nil {
+				return userID, nil
+			}
+		}
+	}
+
+	return uuid.Nil, status.Error(codes.Unauthenticated, "userId cookie is required")
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, models.ErrUnique):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, models.ErrInvalidSubscription):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, models.ErrForeignKey), errors.Is(err, models.ErrCheckViolation):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, models.ErrUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
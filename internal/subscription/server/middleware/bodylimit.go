@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BodyLimit rejects requests declaring a body over maxBytes with 413 Request Entity Too Large and cuts
+// the bodies of the others at maxBytes, so chunked uploads can't get around the limit.
+func BodyLimit(maxBytes int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			req := echo.Request()
+			if req.ContentLength > maxBytes {
+				return echo.JSON(http.StatusRequestEntityTooLarge, map[string]string{"result": "Слишком большой запрос"})
+			}
+
+			req.Body = http.MaxBytesReader(echo.Response(), req.Body, maxBytes)
+
+			return next(echo)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func TestBodyLimit(t *testing.T) {
+	t.Parallel()
+
+	server := echo.New()
+	server.Use(middleware.BodyLimit(8))
+	server.POST("subscription", func(echo echo.Context) error {
+		if _, err := io.ReadAll(echo.Request().Body); err != nil {
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в теле запроса"})
+		}
+
+		return echo.NoContent(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		wantStatus    int
+	}{
+		{"WithinLimit", "12345678", 8, http.StatusOK},
+		{"DeclaredTooLarge", "123456789", 9, http.StatusRequestEntityTooLarge},
+		{"ChunkedTooLarge", "123456789", -1, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/subscription", strings.NewReader(tt.body))
+		req.ContentLength = tt.contentLength
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.wantStatus, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels requests that did not match any route, so scanners can't blow up the label cardinality.
+const unmatchedRoute = "unmatched"
+
+// Metrics counts requests and observes their latency, labelled by the route template instead of the raw URL.
+func Metrics(reg prometheus.Registerer) echo.MiddlewareFunc {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "subscription",
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests.",
+	}, []string{"method", "route", "code"})
+
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "subscription",
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	reg.MustRegister(requests, duration)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			start := time.Now()
+
+			err := next(echo)
+			if err != nil {
+				echo.Error(err)
+			}
+
+			route := echo.Path()
+			if route == "" {
+				route = unmatchedRoute
+			}
+
+			method := echo.Request().Method
+			requests.WithLabelValues(method, route, strconv.Itoa(echo.Response().Status)).Inc()
+			duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+
+			return nil
+		}
+	}
+}
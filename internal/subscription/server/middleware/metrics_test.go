@@ -0,0 +1,50 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsLabelsByRouteTemplate(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	server := echo.New()
+	server.Use(middleware.Metrics(reg))
+	server.GET("subscription/:id", func(echo echo.Context) error {
+		return echo.NoContent(http.StatusOK)
+	})
+	server.DELETE("subscription", func(echo echo.Context) error {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "bad request"})
+	})
+
+	for _, target := range []string{"/subscription/1", "/subscription/2", "/unknown/path"} {
+		server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/subscription", nil))
+
+	expected := `
+# HELP subscription_http_requests_total Number of HTTP requests.
+# TYPE subscription_http_requests_total counter
+subscription_http_requests_total{code="200",method="GET",route="/subscription/:id"} 2
+subscription_http_requests_total{code="400",method="DELETE",route="/subscription"} 1
+subscription_http_requests_total{code="404",method="GET",route="unmatched"} 1
+`
+
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "subscription_http_requests_total"); err != nil {
+		t.Error(err)
+	}
+
+	if count := testutil.CollectAndCount(reg, "subscription_http_request_duration_seconds"); count != 3 {
+		t.Errorf("expected 3 latency series, got %d", count)
+	}
+}
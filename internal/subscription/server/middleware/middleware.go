@@ -16,7 +16,7 @@ func LogRequest(logger *slog.Logger) echo.MiddlewareFunc {
 
 			stop := time.Now()
 
-			logger.Info("Request: ",
+			logger.InfoContext(echo.Request().Context(), "Request: ",
 				"Method", echo.Request().Method,
 				"URL", echo.Request().URL,
 				"Time", stop.Sub(start),
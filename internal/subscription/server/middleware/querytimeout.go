@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// QueryTimeout puts a deadline of timeout on the request context, so the queries a handler runs with it
+// are cancelled once the request has taken too long.
+func QueryTimeout(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			ctx, cancel := context.WithTimeout(echo.Request().Context(), timeout)
+			defer cancel()
+
+			echo.SetRequest(echo.Request().WithContext(ctx))
+
+			return next(echo)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := echo.New()
+	server.Use(middleware.QueryTimeout(20 * time.Millisecond))
+	server.GET("subscription/users", func(echo echo.Context) error {
+		ctx := echo.Request().Context()
+		if _, ok := ctx.Deadline(); !ok {
+			return echo.NoContent(http.StatusOK)
+		}
+
+		<-ctx.Done()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return echo.NoContent(http.StatusGatewayTimeout)
+		}
+
+		return echo.NoContent(http.StatusInternalServerError)
+	})
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/subscription/users", nil))
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("expected status %d, got %d", http.StatusGatewayTimeout, rec.Code)
+	}
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/ratelimit"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// UserIDCookie names the user making the request, the identity of the whole API.
+const UserIDCookie = "userId"
+
+// unlimitedRoutes are probed by the infrastructure and must never be throttled.
+var unlimitedRoutes = map[string]bool{
+	"/healthz":   true,
+	"/readyz":    true,
+	"/metrics":   true,
+	"/swagger/*": true,
+}
+
+// RateLimit rejects the requests going over the client IP, user or route limits with 429 Too Many Requests.
+// The client IP is the one given by the IPExtractor of the server, the user is the one of the UserIDCookie.
+// A rejected request takes no token from any bucket. The RateLimit-* headers describe the most exhausted of
+// the buckets. When the limiter fails, the request is let through: an unavailable limiter must not take the
+// API down with it.
+func RateLimit(limiter ratelimit.Limiter, cfg config.RateLimitConfig, logger *slog.Logger) echo.MiddlewareFunc {
+	perIP := ratelimit.Limit(cfg.PerIP)
+	perUser := ratelimit.Limit(cfg.PerUser)
+
+	routes := make(map[string]ratelimit.Limit, len(cfg.Routes))
+	for route, limit := range cfg.Routes {
+		routes[route] = ratelimit.Limit(limit)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			if unlimitedRoutes[echo.Path()] {
+				return next(echo)
+			}
+
+			var buckets []ratelimit.Bucket
+
+			addBucket := func(key string, limit ratelimit.Limit) {
+				if limit.Enabled() {
+					buckets = append(buckets, ratelimit.Bucket{Key: key, Limit: limit})
+				}
+			}
+
+			addBucket("ip:"+echo.RealIP(), perIP)
+
+			if userID, err := requestUserID(echo); err == nil {
+				addBucket("user:"+userID.String(), perUser)
+			}
+
+			route := fmt.Sprintf("%s %s", echo.Request().Method, echo.Path())
+			addBucket("route:"+route, routes[route])
+
+			if len(buckets) == 0 {
+				return next(echo)
+			}
+
+			results, err := limiter.Allow(echo.Request().Context(), buckets)
+			if err != nil {
+				logger.ErrorContext(echo.Request().Context(), "Rate limiter error", slog.Any("error_details", err))
+
+				return next(echo)
+			}
+
+			var tightest *ratelimit.Result
+
+			for i := range results {
+				res := &results[i]
+				if tightest == nil || (tightest.Allowed && (!res.Allowed || res.Remaining < tightest.Remaining)) {
+					tightest = res
+				}
+			}
+
+			header := echo.Response().Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(tightest.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(tightest.Reset)))
+
+			if !tightest.Allowed {
+				header.Set("Retry-After", strconv.Itoa(ceilSeconds(tightest.RetryAfter)))
+
+				return echo.JSON(http.StatusTooManyRequests, map[string]string{"result": "Слишком много запросов"})
+			}
+
+			return next(echo)
+		}
+	}
+}
+
+// IPExtractor trusts the X-Forwarded-For header only when it is set by one of the trusted proxies, so a
+// client can't pick the IP it is rate limited by. Without trusted proxies the client IP is the address of
+// the connection.
+func IPExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+
+	for _, proxy := range trustedProxies {
+		// The config validation rejects the malformed CIDRs.
+		if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+			options = append(options, echo.TrustIPRange(ipNet))
+		}
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+func requestUserID(echo echo.Context) (uuid.UUID, error) {
+	cookie, err := echo.Cookie(UserIDCookie)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return uuid.Parse(cookie.Value)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,190 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/ratelimit"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, []ratelimit.Bucket) ([]ratelimit.Result, error) {
+	return nil, errors.New("connection refused")
+}
+
+func newRateLimitedServer(limiter ratelimit.Limiter, cfg config.RateLimitConfig, trustedProxies ...string) *echo.Echo {
+	server := echo.New()
+	server.IPExtractor = middleware.IPExtractor(trustedProxies)
+	server.Use(middleware.RateLimit(limiter, cfg, slog.Default()))
+
+	ok := func(echo echo.Context) error { return echo.NoContent(http.StatusOK) }
+	server.GET("subscription/total-price", ok)
+	server.GET("subscription/users", ok)
+	server.GET("healthz", ok)
+
+	return server
+}
+
+func get(server *echo.Echo, target, ip string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.RemoteAddr = ip + ":41000"
+
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.RateLimitConfig{
+		PerIP:   config.LimitConfig{Rate: 0.001, Burst: 3},
+		PerUser: config.LimitConfig{Rate: 0.001, Burst: 2},
+		Routes: map[string]config.LimitConfig{
+			"GET /subscription/total-price": {Rate: 0.001, Burst: 1},
+		},
+	}
+
+	t.Run("PerIP", func(t *testing.T) {
+		t.Parallel()
+
+		server := newRateLimitedServer(ratelimit.NewMemoryLimiter(), cfg)
+
+		for i := 0; i < 3; i++ {
+			if rec := get(server, "/subscription/users", "10.0.0.1", nil); rec.Code != http.StatusOK {
+				t.Fatalf("request %d: expected 200, got %d", i, rec.Code)
+			}
+		}
+
+		rec := get(server, "/subscription/users", "10.0.0.1", nil)
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected 429, got %d", rec.Code)
+		}
+
+		if rec.Header().Get("RateLimit-Limit") != "3" || rec.Header().Get("RateLimit-Remaining") != "0" ||
+			rec.Header().Get("Retry-After") == "" {
+			t.Errorf("unexpected headers %v", rec.Header())
+		}
+
+		if rec := get(server, "/subscription/users", "10.0.0.2", nil); rec.Code != http.StatusOK {
+			t.Errorf("expected another IP to pass, got %d", rec.Code)
+		}
+
+		if rec := get(server, "/healthz", "10.0.0.1", nil); rec.Code != http.StatusOK {
+			t.Errorf("expected probes to be unlimited, got %d", rec.Code)
+		}
+	})
+
+	t.Run("PerUser", func(t *testing.T) {
+		t.Parallel()
+
+		server := newRateLimitedServer(ratelimit.NewMemoryLimiter(), cfg)
+		header := http.Header{"Cookie": {middleware.UserIDCookie + "=60601fee-2bf1-4721-ae6f-7636e79a0cba"}}
+
+		get(server, "/subscription/users", "10.0.0.1", header)
+
+		rec := get(server, "/subscription/users", "10.0.0.2", header)
+		if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Remaining") != "0" {
+			t.Fatalf("expected the user bucket to be the tightest, got %d %v", rec.Code, rec.Header())
+		}
+
+		if rec := get(server, "/subscription/users", "10.0.0.3", header); rec.Code != http.StatusTooManyRequests {
+			t.Errorf("expected the user to be limited across IPs, got %d", rec.Code)
+		}
+
+		spoofed := http.Header{"X-User-ID": {"7a1c2b6e-8d55-4c54-9a0b-0f4e53b9c7d1"}}
+		if rec := get(server, "/subscription/users?user_id=7a1c2b6e-8d55-4c54-9a0b-0f4e53b9c7d1", "10.0.0.4", spoofed); rec.Header().Get("RateLimit-Limit") != "3" {
+			t.Errorf("expected only the cookie to name the user, got %v", rec.Header())
+		}
+	})
+
+	t.Run("PerRoute", func(t *testing.T) {
+		t.Parallel()
+
+		server := newRateLimitedServer(ratelimit.NewMemoryLimiter(), cfg)
+
+		if rec := get(server, "/subscription/total-price", "10.0.0.1", nil); rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+
+		if rec := get(server, "/subscription/total-price", "10.0.0.2", nil); rec.Code != http.StatusTooManyRequests {
+			t.Errorf("expected the route limit to be shared by all clients, got %d", rec.Code)
+		}
+	})
+
+	t.Run("RejectedRequestSpendsNothing", func(t *testing.T) {
+		t.Parallel()
+
+		server := newRateLimitedServer(ratelimit.NewMemoryLimiter(), cfg)
+
+		for i := 0; i < 3; i++ {
+			get(server, "/subscription/total-price", "10.0.0.1", nil)
+		}
+
+		for i := 0; i < 2; i++ {
+			if rec := get(server, "/subscription/users", "10.0.0.1", nil); rec.Code != http.StatusOK {
+				t.Fatalf("request %d: expected the rejected requests to leave the IP tokens, got %d", i, rec.Code)
+			}
+		}
+	})
+
+	t.Run("ForwardedFor", func(t *testing.T) {
+		t.Parallel()
+
+		direct := newRateLimitedServer(ratelimit.NewMemoryLimiter(), cfg)
+		proxied := newRateLimitedServer(ratelimit.NewMemoryLimiter(), cfg, "10.1.0.0/16")
+
+		for i := 0; i < 3; i++ {
+			header := http.Header{"X-Forwarded-For": {fmt.Sprintf("203.0.113.%d", i)}}
+			get(direct, "/subscription/users", "10.0.0.1", header)
+			get(proxied, "/subscription/users", "10.0.0.1", header)
+		}
+
+		if rec := get(direct, "/subscription/users", "10.0.0.1", nil); rec.Code != http.StatusTooManyRequests {
+			t.Errorf("expected the header to be ignored without trusted proxies, got %d", rec.Code)
+		}
+
+		if rec := get(proxied, "/subscription/users", "10.0.0.1", nil); rec.Code != http.StatusTooManyRequests {
+			t.Errorf("expected the header of an untrusted client to be ignored, got %d", rec.Code)
+		}
+
+		for i := 0; i < 3; i++ {
+			header := http.Header{"X-Forwarded-For": {"203.0.113.7"}}
+			if rec := get(proxied, "/subscription/users", "10.1.0.5", header); rec.Code != http.StatusOK {
+				t.Fatalf("request %d: expected the client behind the trusted proxy to have its own bucket, got %d", i, rec.Code)
+			}
+		}
+
+		header := http.Header{"X-Forwarded-For": {"203.0.113.8"}}
+		if rec := get(proxied, "/subscription/users", "10.1.0.5", header); rec.Code != http.StatusOK {
+			t.Errorf("expected another client behind the trusted proxy to pass, got %d", rec.Code)
+		}
+	})
+
+	t.Run("FailOpen", func(t *testing.T) {
+		t.Parallel()
+
+		server := newRateLimitedServer(failingLimiter{}, cfg)
+
+		rec := get(server, "/subscription/total-price", "10.0.0.1", nil)
+		if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "" {
+			t.Errorf("expected the request to pass without headers, got %d %v", rec.Code, rec.Header())
+		}
+	})
+}
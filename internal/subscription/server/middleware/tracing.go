@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/Ostmind/subscriptionservice/internal/subscription/server/middleware"
+
+// Tracing starts a server span per request, continuing the trace from the W3C traceparent header if present.
+// The span is stored in the request context so the handlers, the SQL queries and the logs share the trace.
+func Tracing(provider trace.TracerProvider, propagator propagation.TextMapPropagator) echo.MiddlewareFunc {
+	tracer := provider.Tracer(tracerName)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			req := echo.Request()
+			ctx := propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			route := echo.Path()
+			if route == "" {
+				route = unmatchedRoute
+			}
+
+			ctx, span := tracer.Start(ctx, fmt.Sprintf("%s %s", req.Method, route),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(req.URL.Path),
+				))
+			defer span.End()
+
+			echo.SetRequest(req.WithContext(ctx))
+
+			err := next(echo)
+			if err != nil {
+				span.RecordError(err)
+				echo.Error(err)
+			}
+
+			status := echo.Response().Status
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+
+			return nil
+		}
+	}
+}
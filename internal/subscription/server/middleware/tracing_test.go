@@ -0,0 +1,79 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/middleware"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingContinuesIncomingTrace(t *testing.T) {
+	t.Parallel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider(sdktrace.NewSimpleSpanProcessor(exporter), "test", 1)
+
+	var handlerSpan trace.SpanContext
+
+	server := echo.New()
+	server.Use(middleware.Tracing(provider, propagation.TraceContext{}))
+	server.GET("subscription/:id", func(echo echo.Context) error {
+		handlerSpan = trace.SpanContextFromContext(echo.Request().Context())
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Internal Server Error"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/subscription/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	server.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	span := spans[0]
+
+	if span.Name != "GET /subscription/:id" {
+		t.Errorf("unexpected span name %q", span.Name)
+	}
+
+	if span.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected the incoming trace id, got %s", span.SpanContext.TraceID())
+	}
+
+	if span.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("expected the incoming parent span, got %s", span.Parent.SpanID())
+	}
+
+	if handlerSpan.SpanID() != span.SpanContext.SpanID() {
+		t.Error("expected the span in the handler request context")
+	}
+
+	if span.Status.Code != codes.Error {
+		t.Errorf("expected error status, got %v", span.Status.Code)
+	}
+
+	if !containsAttribute(span.Attributes, attribute.Int("http.response.status_code", http.StatusInternalServerError)) {
+		t.Errorf("missing status code attribute in %v", span.Attributes)
+	}
+}
+
+func containsAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if attr == want {
+			return true
+		}
+	}
+
+	return false
+}
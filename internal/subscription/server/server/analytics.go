@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+//go:generate mockgen -source=analytics.go -destination=mock/analyticsrepository.go
+type analyticsManager interface {
+	GetSpendAnalytics(ctx context.Context, filter models.SpendAnalyticsFilter) (models.SpendAnalyticsDB, error)
+}
+
+type analyticsController struct {
+	manager analyticsManager
+	logger  *slog.Logger
+}
+
+func NewAnalyticsHandler(manager analyticsManager, log *slog.Logger) *analyticsController {
+	return &analyticsController{manager, log}
+}
+
+// GetSpendAnalytics godoc
+// @Summary Получить аналитику расходов
+// @Description Возвращает расходы за период с группировкой по месяцу, сервису, категории и валюте
+// @Description в любой комбинации, а также итоги, средние значения и изменения к предыдущему месяцу
+// @Description Совместные подписки домохозяйства учитываются долей пользователя
+// @Tags analytics
+// @Produce json
+// @Param user_id query string true "ID пользователя"
+// @Param start_date query string true "Начало периода в формате MM-YYYY"
+// @Param end_date query string true "Конец периода в формате MM-YYYY"
+// @Param group_by query string false "Измерения через запятую: month, service, category, currency"
+// @Success 200 {object} models.SpendAnalyticsJSON
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/analytics [get]
+func (ctr analyticsController) GetSpendAnalytics(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Spend Analytics")
+
+	var query models.SpendAnalyticsQueryJSON
+
+	if err := echo.Bind(&query); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	filter, err := parseAnalyticsQuery(query)
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	res, err := ctr.manager.GetSpendAnalytics(echo.Request().Context(), filter)
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		if errors.Is(err, models.ErrInvalidGroupBy) {
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	analytics := models.SpendAnalyticsJSON{
+		StartDate:      filter.StartDate.Format(models.MonthLayout),
+		EndDate:        filter.EndDate.Format(models.MonthLayout),
+		GroupBy:        filter.GroupBy,
+		Total:          res.Total,
+		MonthlyAverage: res.MonthlyAverage,
+		Groups:         make([]models.SpendAnalyticsGroupJSON, 0, len(res.Groups)),
+	}
+
+	for _, v := range res.Groups {
+		group := models.SpendAnalyticsGroupJSON{
+			Total:   v.Total,
+			Average: v.Average,
+			Charges: v.Charges,
+			Delta:   v.Delta,
+		}
+
+		if v.Month != nil {
+			group.Month = v.Month.Format(models.MonthLayout)
+		}
+
+		if v.ServiceName != nil {
+			group.ServiceName = *v.ServiceName
+		}
+
+		if v.Category != nil {
+			group.Category = *v.Category
+		}
+
+		if v.Currency != nil {
+			group.Currency = *v.Currency
+		}
+
+		analytics.Groups = append(analytics.Groups, group)
+	}
+
+	return echo.JSON(http.StatusOK, analytics)
+}
+
+func parseAnalyticsQuery(query models.SpendAnalyticsQueryJSON) (filter models.SpendAnalyticsFilter, err error) {
+	filter.UserID, err = uuid.Parse(query.UserID)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.StartDate, err = time.Parse(models.MonthLayout, query.StartDate)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.EndDate, err = time.Parse(models.MonthLayout, query.EndDate)
+	if err != nil {
+		return filter, err
+	}
+
+	if filter.EndDate.Before(filter.StartDate) {
+		return filter, errors.New("end date is before start date")
+	}
+
+	filter.GroupBy = []string{}
+
+	seen := make(map[string]bool)
+
+	for _, dimension := range strings.Split(query.GroupBy, ",") {
+		dimension = strings.ToLower(strings.TrimSpace(dimension))
+		if dimension == "" || seen[dimension] {
+			continue
+		}
+
+		seen[dimension] = true
+		filter.GroupBy = append(filter.GroupBy, dimension)
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,117 @@
+package server_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+)
+
+func TestGetSpendAnalytics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	month := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	service := "Netflix"
+	delta := -100
+
+	tests := []struct {
+		name       string
+		url        string
+		mockSetup  func(m *mock_server.MockanalyticsManager)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "Success",
+			url:  "/?user_id=60601fee-2bf1-4721-ae6f-7636e79a0cba&start_date=09-2025&end_date=09-2025&group_by=month,service",
+			mockSetup: func(m *mock_server.MockanalyticsManager) {
+				m.EXPECT().
+					GetSpendAnalytics(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, filter models.SpendAnalyticsFilter) (models.SpendAnalyticsDB, error) {
+						if len(filter.GroupBy) != 2 || filter.GroupBy[0] != models.GroupByMonth || !filter.StartDate.Equal(month) {
+							t.Errorf("unexpected filter %+v", filter)
+						}
+
+						return models.SpendAnalyticsDB{
+							Total:          400,
+							MonthlyAverage: 400,
+							Groups: []models.SpendAnalyticsGroupDB{
+								{Month: &month, ServiceName: &service, Total: 400, Average: 400, Charges: 1, Delta: &delta},
+							},
+						}, nil
+					})
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"start_date":"09-2025","end_date":"09-2025","group_by":["month","service"],"total":400,"monthly_average":400,` +
+				`"groups":[{"month":"09-2025","service_name":"Netflix","total":400,"average":400,"charges":1,"delta":-100}]}`,
+		},
+		{
+			name:       "BadRequest_InvalidDates",
+			url:        "/?user_id=60601fee-2bf1-4721-ae6f-7636e79a0cba&start_date=12-2025&end_date=09-2025",
+			mockSetup:  func(m *mock_server.MockanalyticsManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "BadRequest_InvalidGroupBy",
+			url:  "/?user_id=60601fee-2bf1-4721-ae6f-7636e79a0cba&start_date=09-2025&end_date=12-2025&group_by=weekday",
+			mockSetup: func(m *mock_server.MockanalyticsManager) {
+				m.EXPECT().
+					GetSpendAnalytics(gomock.Any(), gomock.Any()).
+					Return(models.SpendAnalyticsDB{}, models.ErrInvalidGroupBy)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "InternalServerError",
+			url:  "/?user_id=60601fee-2bf1-4721-ae6f-7636e79a0cba&start_date=09-2025&end_date=12-2025",
+			mockSetup: func(m *mock_server.MockanalyticsManager) {
+				m.EXPECT().
+					GetSpendAnalytics(gomock.Any(), gomock.Any()).
+					Return(models.SpendAnalyticsDB{}, errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockanalyticsManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewAnalyticsHandler(mockManager, logger)
+			if err := handler.GetSpendAnalytics(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+
+			if tt.wantBody != "" {
+				if strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+					t.Errorf("expected body %s, got %s", tt.wantBody, rec.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+//go:generate mockgen -source=budget.go -destination=mock/budgetrepository.go
+type budgetManager interface {
+	PostBudget(ctx context.Context, budget models.BudgetJSON) error
+	GetBudgetsByUserID(ctx context.Context, id uuid.UUID) ([]models.BudgetJSON, error)
+	GetBudget(ctx context.Context, id int) (models.BudgetJSON, error)
+	UpdateBudget(ctx context.Context, budget models.BudgetJSON, id int) error
+	DeleteBudget(ctx context.Context, id int) error
+}
+
+type budgetController struct {
+	manager budgetManager
+	logger  *slog.Logger
+}
+
+func NewBudgetHandler(manager budgetManager, log *slog.Logger) *budgetController {
+	return &budgetController{manager, log}
+}
+
+// GetBudgetsByUserID godoc
+// @Summary Получить список бюджетов
+// @Description Получает бюджеты пользователя по user_id из query-параметров
+// @Tags budgets
+// @Produce json
+// @Param user_id query string true "ID пользователя"
+// @Success 200 {array} models.BudgetJSON
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /subscription/budgets [get]
+func (ctr budgetController) GetBudgetsByUserID(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Budget List")
+
+	userID, err := uuid.Parse(echo.QueryParam("user_id"))
+	if err != nil {
+		return echo.NoContent(http.StatusBadRequest)
+	}
+
+	res, err := ctr.manager.GetBudgetsByUserID(echo.Request().Context(), userID)
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		if errors.Is(err, models.ErrNotFound) {
+			return echo.NoContent(http.StatusNotFound)
+		}
+
+		return echo.NoContent(http.StatusInternalServerError)
+	}
+
+	return echo.JSON(http.StatusOK, res)
+}
+
+// PostBudget godoc
+// @Summary Создать бюджет
+// @Description Добавляет месячный бюджет пользователя: общий (scope=total), на категорию (scope=category)
+// @Description или на сервис (scope=service)
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param budget body models.BudgetJSON true "Данные бюджета"
+// @Success 200 {string} string "Бюджет успешно создан"
+// @Failure 400 {string} string "Неправильный запрос или дубликат бюджета"
+// @Failure 409 {object} map[string]string "Запись уже существует"
+// @Failure 422 {object} map[string]string "Нарушено ограничение, в constraint и column его имя и столбец"
+// @Failure 503 {object} map[string]string "Сервис временно недоступен, повторите запрос"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/budget [post]
+func (ctr budgetController) PostBudget(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for POST Budget")
+
+	var budget models.BudgetJSON
+
+	if err := echo.Bind(&budget); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Неправильный запрос или дубликат бюджета"})
+	}
+
+	if err := validateBudget(&budget); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Неправильный запрос или дубликат бюджета"})
+	}
+
+	if err := ctr.manager.PostBudget(echo.Request().Context(), budget); err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	return echo.JSON(http.StatusOK, map[string]string{"result": "Бюджет успешно создан"})
+}
+
+// UpdateBudget godoc
+// @Summary Обновить бюджет
+// @Description Обновляет бюджет по id переданному в query-параметрах. Доступно владельцу бюджета, user_id бюджета
+// @Description не меняется
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param id query int true "ID бюджета для обновления"
+// @Param budget body models.BudgetJSON true "Данные бюджета для обновления"
+// @Param userId header string true "userId из cookie, владелец бюджета"
+// @Success 200 {string} string "Бюджет успешно обновлен"
+// @Failure 400 {string} string "Неправильный запрос или невалидные данные"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Бюджет принадлежит другому пользователю"
+// @Failure 409 {object} map[string]string "Запись уже существует"
+// @Failure 422 {object} map[string]string "Нарушено ограничение, в constraint и column его имя и столбец"
+// @Failure 503 {object} map[string]string "Сервис временно недоступен, повторите запрос"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/budget [put]
+func (ctr budgetController) UpdateBudget(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Update Budget")
+
+	var budget models.BudgetJSON
+
+	if err := echo.Bind(&budget); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Неправильный запрос или невалидные данные"})
+	}
+
+	id, err := strconv.Atoi(echo.QueryParam("id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Неправильный запрос или невалидные данные"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	ctx := echo.Request().Context()
+
+	// The budget keeps its user, an update can't hand it over to someone else.
+	budget.UserID = requester
+
+	err = ctr.owner(ctx, id, requester)
+	if err == nil {
+		if err := validateBudget(&budget); err != nil {
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Неправильный запрос или невалидные данные"})
+		}
+
+		err = ctr.manager.UpdateBudget(ctx, budget, id)
+	}
+
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Неправильный запрос или невалидные данные"})
+		case errors.Is(err, models.ErrForbidden):
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Бюджет принадлежит другому пользователю"})
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	return echo.JSON(http.StatusOK, map[string]string{"result": "Бюджет успешно обновлен"})
+}
+
+// DeleteBudget godoc
+// @Summary Удалить бюджет
+// @Description Удаление бюджета по id из query-параметров. Доступно владельцу бюджета
+// @Tags budgets
+// @Produce json
+// @Param id query int true "ID бюджета для удаления"
+// @Param userId header string true "userId из cookie, владелец бюджета"
+// @Success 200 {string} string "Бюджет успешно удален"
+// @Failure 400 {string} string "Некорректный id или не найден бюджет"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Бюджет принадлежит другому пользователю"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/budget [delete]
+func (ctr budgetController) DeleteBudget(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Delete Budget")
+
+	id, err := strconv.Atoi(echo.QueryParam("id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректный id или не найден бюджет"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	ctx := echo.Request().Context()
+
+	err = ctr.owner(ctx, id, requester)
+	if err == nil {
+		err = ctr.manager.DeleteBudget(ctx, id)
+	}
+
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректный id или не найден бюджет"})
+		case errors.Is(err, models.ErrForbidden):
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Бюджет принадлежит другому пользователю"})
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	return echo.JSON(http.StatusOK, map[string]string{"result": "Бюджет успешно удален"})
+}
+
+// owner checks the user owns the budget, ErrForbidden when they don't.
+func (ctr budgetController) owner(ctx context.Context, id int, userID uuid.UUID) error {
+	budget, err := ctr.manager.GetBudget(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if budget.UserID != userID {
+		return models.ErrForbidden
+	}
+
+	return nil
+}
+
+func validateBudget(budget *models.BudgetJSON) error {
+	if budget.UserID == uuid.Nil || budget.Amount < 0 {
+		return models.ErrInvalidBudget
+	}
+
+	switch budget.Scope {
+	case "", models.BudgetScopeTotal:
+		budget.Scope = models.BudgetScopeTotal
+		budget.ScopeValue = ""
+	case models.BudgetScopeCategory, models.BudgetScopeService:
+		if budget.ScopeValue == "" {
+			return models.ErrInvalidBudget
+		}
+	default:
+		return models.ErrInvalidBudget
+	}
+
+	return nil
+}
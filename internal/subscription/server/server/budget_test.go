@@ -0,0 +1,444 @@
+package server_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+)
+
+var (
+	budgetOwner = uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")
+	ownBudget   = models.BudgetJSON{ID: 1, UserID: budgetOwner, Scope: models.BudgetScopeTotal, Amount: 1000}
+)
+
+func TestPostBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	tests := []struct {
+		name       string
+		body       string
+		mockSetup  func(m *mock_server.MockbudgetManager)
+		wantStatus int
+	}{
+		{
+			name: "Success_DefaultScopeIsTotal",
+			body: `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","scope_value":"music","amount":1000}`,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().PostBudget(gomock.Any(), models.BudgetJSON{
+					UserID: budgetOwner, Scope: models.BudgetScopeTotal, Amount: 1000,
+				}).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Success_Category",
+			body: `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","scope":"category","scope_value":"music","amount":500}`,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().PostBudget(gomock.Any(), models.BudgetJSON{
+					UserID: budgetOwner, Scope: models.BudgetScopeCategory, ScopeValue: "music", Amount: 500,
+				}).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "BadRequest_NegativeAmount",
+			body:       `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","amount":-1}`,
+			mockSetup:  func(m *mock_server.MockbudgetManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "BadRequest_ServiceWithoutValue",
+			body:       `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","scope":"service","amount":1000}`,
+			mockSetup:  func(m *mock_server.MockbudgetManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "BadRequest_UnknownScope",
+			body:       `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","scope":"weekly","amount":1000}`,
+			mockSetup:  func(m *mock_server.MockbudgetManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "BadRequest_NoUser",
+			body:       `{"amount":1000}`,
+			mockSetup:  func(m *mock_server.MockbudgetManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "BadRequest_InvalidJSON",
+			body:       `{"amount":`,
+			mockSetup:  func(m *mock_server.MockbudgetManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Conflict_Duplicate",
+			body: `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","amount":1000}`,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().PostBudget(gomock.Any(), gomock.Any()).Return(models.ErrUnique)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "InternalServerError",
+			body: `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","amount":1000}`,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().PostBudget(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockbudgetManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewBudgetHandler(mockManager, logger)
+			if err := handler.PostBudget(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestGetBudgetsByUserID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	tests := []struct {
+		name       string
+		url        string
+		mockSetup  func(m *mock_server.MockbudgetManager)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "Success",
+			url:  "/?user_id=" + budgetOwner.String(),
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudgetsByUserID(gomock.Any(), budgetOwner).Return([]models.BudgetJSON{
+					{ID: 1, UserID: budgetOwner, Scope: models.BudgetScopeTotal, Amount: 1000},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"id":1,"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","scope":"total","amount":1000}]`,
+		},
+		{
+			name:       "BadRequest_InvalidUserID",
+			url:        "/?user_id=invalid",
+			mockSetup:  func(m *mock_server.MockbudgetManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "NotFound",
+			url:  "/?user_id=" + budgetOwner.String(),
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudgetsByUserID(gomock.Any(), budgetOwner).Return(nil, models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "InternalServerError",
+			url:  "/?user_id=" + budgetOwner.String(),
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudgetsByUserID(gomock.Any(), budgetOwner).Return(nil, errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockbudgetManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewBudgetHandler(mockManager, logger)
+			if err := handler.GetBudgetsByUserID(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestUpdateBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	tests := []struct {
+		name       string
+		url        string
+		body       string
+		userID     uuid.UUID
+		mockSetup  func(m *mock_server.MockbudgetManager)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "Success",
+			url:    "/?id=1",
+			body:   `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","scope":"service","scope_value":"Netflix","amount":800}`,
+			userID: budgetOwner,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudget(gomock.Any(), 1).Return(ownBudget, nil)
+				m.EXPECT().UpdateBudget(gomock.Any(), models.BudgetJSON{
+					UserID: budgetOwner, Scope: models.BudgetScopeService, ScopeValue: "Netflix", Amount: 800,
+				}, 1).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Success_KeepsStoredUser",
+			url:    "/?id=1",
+			body:   `{"user_id":"7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d","amount":800}`,
+			userID: budgetOwner,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudget(gomock.Any(), 1).Return(ownBudget, nil)
+				m.EXPECT().UpdateBudget(gomock.Any(), models.BudgetJSON{
+					UserID: budgetOwner, Scope: models.BudgetScopeTotal, Amount: 800,
+				}, 1).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Forbidden_OtherOwner",
+			url:    "/?id=1",
+			body:   `{"user_id":"7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d","amount":800}`,
+			userID: uuid.MustParse("7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d"),
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudget(gomock.Any(), 1).Return(ownBudget, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Unauthorized_NoCookie",
+			url:        "/?id=1",
+			body:       `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","amount":800}`,
+			mockSetup:  func(m *mock_server.MockbudgetManager) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "BadRequest_InvalidJSON",
+			url:        "/?id=1",
+			body:       `{"amount":`,
+			userID:     budgetOwner,
+			mockSetup:  func(m *mock_server.MockbudgetManager) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"result":"Неправильный запрос или невалидные данные"}`,
+		},
+		{
+			name:       "BadRequest_InvalidID",
+			url:        "/?id=abc",
+			body:       `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","amount":800}`,
+			userID:     budgetOwner,
+			mockSetup:  func(m *mock_server.MockbudgetManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "BadRequest_InvalidBudget",
+			url:    "/?id=1",
+			body:   `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","scope":"category","amount":800}`,
+			userID: budgetOwner,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudget(gomock.Any(), 1).Return(ownBudget, nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "BadRequest_NotFound",
+			url:    "/?id=2",
+			body:   `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","amount":800}`,
+			userID: budgetOwner,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudget(gomock.Any(), 2).Return(models.BudgetJSON{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "ServiceUnavailable",
+			url:    "/?id=1",
+			body:   `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","amount":800}`,
+			userID: budgetOwner,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudget(gomock.Any(), 1).Return(ownBudget, nil)
+				m.EXPECT().UpdateBudget(gomock.Any(), gomock.Any(), 1).Return(models.ErrUnavailable)
+			},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockbudgetManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodPut, tt.url, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			if tt.userID != uuid.Nil {
+				req.AddCookie(&http.Cookie{Name: "userId", Value: tt.userID.String()})
+			}
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewBudgetHandler(mockManager, logger)
+			if err := handler.UpdateBudget(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeleteBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	tests := []struct {
+		name       string
+		url        string
+		userID     uuid.UUID
+		mockSetup  func(m *mock_server.MockbudgetManager)
+		wantStatus int
+	}{
+		{
+			name:   "Success",
+			url:    "/?id=1",
+			userID: budgetOwner,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudget(gomock.Any(), 1).Return(ownBudget, nil)
+				m.EXPECT().DeleteBudget(gomock.Any(), 1).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Forbidden_OtherOwner",
+			url:    "/?id=1",
+			userID: uuid.MustParse("7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d"),
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudget(gomock.Any(), 1).Return(ownBudget, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Unauthorized_NoCookie",
+			url:        "/?id=1",
+			mockSetup:  func(m *mock_server.MockbudgetManager) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "BadRequest_InvalidID",
+			url:        "/?id=abc",
+			userID:     budgetOwner,
+			mockSetup:  func(m *mock_server.MockbudgetManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "BadRequest_NotFound",
+			url:    "/?id=2",
+			userID: budgetOwner,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudget(gomock.Any(), 2).Return(models.BudgetJSON{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "InternalServerError",
+			url:    "/?id=1",
+			userID: budgetOwner,
+			mockSetup: func(m *mock_server.MockbudgetManager) {
+				m.EXPECT().GetBudget(gomock.Any(), 1).Return(ownBudget, nil)
+				m.EXPECT().DeleteBudget(gomock.Any(), 1).Return(errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockbudgetManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodDelete, tt.url, nil)
+			if tt.userID != uuid.Nil {
+				req.AddCookie(&http.Cookie{Name: "userId", Value: tt.userID.String()})
+			}
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewBudgetHandler(mockManager, logger)
+			if err := handler.DeleteBudget(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/labstack/echo/v4"
+)
+
+// retryAfter is the Retry-After of a 503, in seconds. The failures behind it are a lost serialization race
+// or a query over its timeout, a retry a moment later usually succeeds.
+const retryAfter = "1"
+
+// storageError responds to the storage errors the client can act on: 409 for a duplicate, 422 for a violated
+// constraint and 503 for a transient failure. The constraint and the column are returned when the database
+// names them. handled is false for the other errors, which the handler maps itself.
+func storageError(echo echo.Context, err error) (handled bool, resErr error) {
+	var (
+		status  int
+		message string
+	)
+
+	switch {
+	case errors.Is(err, models.ErrUnique):
+		status, message = http.StatusConflict, "Запись уже существует"
+	case errors.Is(err, models.ErrForeignKey):
+		status, message = http.StatusUnprocessableEntity, "Связанная запись не найдена"
+	case errors.Is(err, models.ErrCheckViolation):
+		status, message = http.StatusUnprocessableEntity, "Значение нарушает ограничение"
+	case errors.Is(err, models.ErrUnavailable):
+		status, message = http.StatusServiceUnavailable, "Сервис временно недоступен, повторите запрос"
+		echo.Response().Header().Set("Retry-After", retryAfter)
+	default:
+		return false, nil
+	}
+
+	body := map[string]string{"result": message}
+
+	var dbErr *models.DBError
+	if errors.As(err, &dbErr) {
+		if dbErr.Constraint != "" {
+			body["constraint"] = dbErr.Constraint
+		}
+
+		if dbErr.Column != "" {
+			body["column"] = dbErr.Column
+		}
+	}
+
+	return true, echo.JSON(status, body)
+}
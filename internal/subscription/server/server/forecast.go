@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/forecast"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+//go:generate mockgen -source=forecast.go -destination=mock/forecastrepository.go
+type forecastManager interface {
+	GetForecastSubscriptionsByUserID(ctx context.Context, id uuid.UUID, from time.Time) ([]models.ForecastSubscription, error)
+	PostPriceChange(ctx context.Context, subscriptionID int, change models.PriceChange) error
+}
+
+type forecastController struct {
+	manager       forecastManager
+	subscriptions subscriptionGetter
+	logger        *slog.Logger
+}
+
+func NewForecastHandler(manager forecastManager, subscriptions subscriptionGetter, log *slog.Logger) *forecastController {
+	return &forecastController{manager, subscriptions, log}
+}
+
+// GetForecast godoc
+// @Summary Получить прогноз расходов
+// @Description Прогнозирует расходы по активным подпискам на указанное число месяцев (по умолчанию 12)
+// @Description с учетом периодичности оплаты, запланированных изменений цены, пробных периодов и дат окончания.
+// @Description Поле what_if позволяет проверить гипотетические отмены и добавления подписок без их сохранения
+// @Tags forecast
+// @Accept json
+// @Produce json
+// @Param forecast body models.ForecastRequestJSON true "Параметры прогноза"
+// @Success 200 {object} models.ForecastJSON
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/forecast [post]
+func (ctr forecastController) GetForecast(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Subscription Forecast")
+
+	var req models.ForecastRequestJSON
+
+	if err := echo.Bind(&req); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	from := time.Now()
+
+	if req.StartDate != "" {
+		startDate, err := time.Parse(models.MonthLayout, req.StartDate)
+		if err != nil {
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+		}
+
+		from = startDate
+	}
+
+	if req.Months == 0 {
+		req.Months = forecast.DefaultMonths
+	}
+
+	if req.Months < 0 || req.Months > forecast.MaxMonths {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	additions := make([]models.ForecastSubscription, 0, len(req.WhatIf.Add))
+
+	for _, v := range req.WhatIf.Add {
+		sub, err := forecast.FromJSON(v)
+		if err != nil {
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+		}
+
+		additions = append(additions, sub)
+	}
+
+	subs, err := ctr.manager.GetForecastSubscriptionsByUserID(echo.Request().Context(), req.UserID, from)
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	subs = forecast.ApplyWhatIf(subs, req.WhatIf.CancelIDs, req.WhatIf.CancelServices, additions)
+
+	return echo.JSON(http.StatusOK, forecastToJSON(forecast.Project(subs, from, req.Months)))
+}
+
+// PostPriceChange godoc
+// @Summary Запланировать изменение цены
+// @Description Добавляет изменение цены подписки, вступающее в силу с указанного месяца. Цена должна быть
+// @Description положительной, изменить цену может только владелец подписки
+// @Tags forecast
+// @Accept json
+// @Produce json
+// @Param priceChange body models.PriceChangeJSON true "Изменение цены"
+// @Param userId header string true "userId из cookie, владелец подписки"
+// @Success 200 {string} string "Изменение цены успешно сохранено"
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Подписка принадлежит другому пользователю"
+// @Failure 404 {string} string "Подписка не найдена"
+// @Failure 409 {object} map[string]string "Запись уже существует"
+// @Failure 422 {object} map[string]string "Нарушено ограничение, в constraint и column его имя и столбец"
+// @Failure 503 {object} map[string]string "Сервис временно недоступен, повторите запрос"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/price-change [post]
+func (ctr forecastController) PostPriceChange(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for POST Price Change")
+
+	var change models.PriceChangeJSON
+
+	if err := echo.Bind(&change); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	effectiveDate, err := time.Parse(models.MonthLayout, change.EffectiveDate)
+	if err != nil || change.Price <= 0 {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	ctx := echo.Request().Context()
+
+	sub, err := ctr.subscriptions.GetSubscription(ctx, change.SubscriptionID)
+	if err == nil && sub.UserID != requester {
+		err = models.ErrForbidden
+	}
+
+	if err == nil {
+		err = ctr.manager.PostPriceChange(ctx, change.SubscriptionID,
+			models.PriceChange{EffectiveDate: effectiveDate, Price: change.Price})
+	}
+
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			return echo.JSON(http.StatusNotFound, map[string]string{"result": "Подписка не найдена"})
+		case errors.Is(err, models.ErrForbidden):
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Подписка принадлежит другому пользователю"})
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	return echo.JSON(http.StatusOK, map[string]string{"result": "Изменение цены успешно сохранено"})
+}
+
+func forecastToJSON(res models.Forecast) models.ForecastJSON {
+	forecastJSON := models.ForecastJSON{
+		StartDate: res.StartDate.Format(models.MonthLayout),
+		EndDate:   res.EndDate.Format(models.MonthLayout),
+		Total:     res.Total,
+		Months:    monthsToJSON(res.Months),
+		Lines:     make([]models.ForecastLineJSON, 0, len(res.Lines)),
+	}
+
+	for _, v := range res.Lines {
+		forecastJSON.Lines = append(forecastJSON.Lines, models.ForecastLineJSON{
+			SubscriptionID: v.SubscriptionID,
+			ServiceName:    v.ServiceName,
+			Currency:       v.Currency,
+			Hypothetical:   v.Hypothetical,
+			Total:          v.Total,
+			Charges:        monthsToJSON(v.Charges),
+		})
+	}
+
+	return forecastJSON
+}
+
+func monthsToJSON(months []models.ForecastMonth) []models.ForecastMonthJSON {
+	res := make([]models.ForecastMonthJSON, 0, len(months))
+
+	for _, v := range months {
+		res = append(res, models.ForecastMonthJSON{Month: v.Month.Format(models.MonthLayout), Total: v.Total})
+	}
+
+	return res
+}
@@ -0,0 +1,251 @@
+package server_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+)
+
+var forecastOwner = uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")
+
+func TestGetForecast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	january := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	netflix := []models.ForecastSubscription{
+		{ID: 1, ServiceName: "Netflix", Price: 800, Currency: "RUB", BillingPeriod: models.BillingMonthly, StartDate: january},
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		mockSetup  func(m *mock_server.MockforecastManager)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "Success",
+			body: `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","start_date":"01-2026","months":2}`,
+			mockSetup: func(m *mock_server.MockforecastManager) {
+				m.EXPECT().GetForecastSubscriptionsByUserID(gomock.Any(), forecastOwner, january).Return(netflix, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"start_date":"01-2026","end_date":"02-2026","total":1600,` +
+				`"months":[{"month":"01-2026","total":800},{"month":"02-2026","total":800}],` +
+				`"lines":[{"subscription_id":1,"service_name":"Netflix","currency":"RUB","hypothetical":false,"total":1600,` +
+				`"charges":[{"month":"01-2026","total":800},{"month":"02-2026","total":800}]}]}`,
+		},
+		{
+			name: "Success_WhatIfCancelAndAdd",
+			body: `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","start_date":"01-2026","months":1,` +
+				`"what_if":{"cancel_ids":[1],"add":[{"service_name":"Okko","price":400,"start_date":"01-2026"}]}}`,
+			mockSetup: func(m *mock_server.MockforecastManager) {
+				m.EXPECT().GetForecastSubscriptionsByUserID(gomock.Any(), forecastOwner, january).Return(netflix, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"start_date":"01-2026","end_date":"01-2026","total":400,` +
+				`"months":[{"month":"01-2026","total":400}],` +
+				`"lines":[{"service_name":"Okko","currency":"RUB","hypothetical":true,"total":400,` +
+				`"charges":[{"month":"01-2026","total":400}]}]}`,
+		},
+		{
+			name:       "BadRequest_NegativeMonths",
+			body:       `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","months":-1}`,
+			mockSetup:  func(m *mock_server.MockforecastManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "BadRequest_TooManyMonths",
+			body:       `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","months":61}`,
+			mockSetup:  func(m *mock_server.MockforecastManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "BadRequest_InvalidStartDate",
+			body:       `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","start_date":"2026-01"}`,
+			mockSetup:  func(m *mock_server.MockforecastManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "BadRequest_WhatIfUnknownBillingPeriod",
+			body: `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba",` +
+				`"what_if":{"add":[{"service_name":"Okko","price":400,"start_date":"01-2026","billing_period":"weekly"}]}}`,
+			mockSetup:  func(m *mock_server.MockforecastManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "BadRequest_WhatIfInvalidEndDate",
+			body: `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba",` +
+				`"what_if":{"add":[{"service_name":"Okko","price":400,"start_date":"01-2026","end_date":"13-2026"}]}}`,
+			mockSetup:  func(m *mock_server.MockforecastManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "InternalServerError",
+			body: `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba"}`,
+			mockSetup: func(m *mock_server.MockforecastManager) {
+				m.EXPECT().GetForecastSubscriptionsByUserID(gomock.Any(), forecastOwner, gomock.Any()).
+					Return(nil, errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockforecastManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewForecastHandler(mockManager, nil, logger)
+			if err := handler.GetForecast(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestPostPriceChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	change := models.PriceChange{EffectiveDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Price: 999}
+
+	tests := []struct {
+		name       string
+		body       string
+		userID     string
+		mockSetup  func(m *mock_server.MockforecastManager, s *mock_server.MocksubscriptionGetter)
+		wantStatus int
+	}{
+		{
+			name:   "Success",
+			body:   `{"subscription_id":1,"effective_date":"03-2026","price":999}`,
+			userID: forecastOwner.String(),
+			mockSetup: func(m *mock_server.MockforecastManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 1).Return(models.Subscription{ID: 1, UserID: forecastOwner}, nil)
+				m.EXPECT().PostPriceChange(gomock.Any(), 1, change).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "BadRequest_ZeroPrice",
+			body:       `{"subscription_id":1,"effective_date":"03-2026","price":0}`,
+			userID:     forecastOwner.String(),
+			mockSetup:  func(m *mock_server.MockforecastManager, s *mock_server.MocksubscriptionGetter) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "BadRequest_NegativePrice",
+			body:       `{"subscription_id":1,"effective_date":"03-2026","price":-100}`,
+			userID:     forecastOwner.String(),
+			mockSetup:  func(m *mock_server.MockforecastManager, s *mock_server.MocksubscriptionGetter) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "BadRequest_InvalidEffectiveDate",
+			body:       `{"subscription_id":1,"effective_date":"2026-03","price":999}`,
+			userID:     forecastOwner.String(),
+			mockSetup:  func(m *mock_server.MockforecastManager, s *mock_server.MocksubscriptionGetter) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Unauthorized_NoCookie",
+			body:       `{"subscription_id":1,"effective_date":"03-2026","price":999}`,
+			mockSetup:  func(m *mock_server.MockforecastManager, s *mock_server.MocksubscriptionGetter) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "Forbidden_OtherOwner",
+			body:   `{"subscription_id":1,"effective_date":"03-2026","price":999}`,
+			userID: uuid.NewString(),
+			mockSetup: func(m *mock_server.MockforecastManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 1).Return(models.Subscription{ID: 1, UserID: forecastOwner}, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "NotFound",
+			body:   `{"subscription_id":2,"effective_date":"03-2026","price":999}`,
+			userID: forecastOwner.String(),
+			mockSetup: func(m *mock_server.MockforecastManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 2).Return(models.Subscription{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "InternalServerError",
+			body:   `{"subscription_id":1,"effective_date":"03-2026","price":999}`,
+			userID: forecastOwner.String(),
+			mockSetup: func(m *mock_server.MockforecastManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 1).Return(models.Subscription{ID: 1, UserID: forecastOwner}, nil)
+				m.EXPECT().PostPriceChange(gomock.Any(), 1, change).Return(errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockforecastManager(ctrl)
+			mockSubscriptions := mock_server.NewMocksubscriptionGetter(ctrl)
+			tt.mockSetup(mockManager, mockSubscriptions)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			if tt.userID != "" {
+				req.AddCookie(&http.Cookie{Name: "userId", Value: tt.userID})
+			}
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewForecastHandler(mockManager, mockSubscriptions, logger)
+			if err := handler.PostPriceChange(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
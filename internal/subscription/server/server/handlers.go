@@ -3,9 +3,10 @@ package server
 import (
 	"context"
 	"errors"
-	"github.com/Ostmind/subscriptionservice/internal/storage"
 	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/middleware"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v4"
 	"log/slog"
 	"net/http"
@@ -14,11 +15,12 @@ import (
 
 //go:generate mockgen -source=handlers.go -destination=mock/handlersrepository.go
 type subscriptionManager interface {
-	GetSubscriptionListByUserID(ctx context.Context, id uuid.UUID) ([]models.SubscriptionListDB, error)
+	GetSubscriptionListByUserID(ctx context.Context, id uuid.UUID) ([]models.Subscription, error)
 	PostSubscription(ctx context.Context, sub models.SubscriptionListJSON) error
-	UpdateSubscription(ctx context.Context, sub models.SubscriptionListJSON, id int) error
-	DeleteSubscription(ctx context.Context, id int) error
+	UpdateSubscription(ctx context.Context, userID uuid.UUID, sub models.SubscriptionListJSON, id int) error
+	DeleteSubscription(ctx context.Context, userID uuid.UUID, id int) error
 	GetTotalPeriodCostByDatesAndServiceName(ctx context.Context, subList models.SubscriptionListToCostJSON) (int, error)
+	GetTotalPeriodCostsByUserIDs(ctx context.Context, ids []uuid.UUID, subList models.SubscriptionListToCostJSON) (map[uuid.UUID]int, error)
 }
 
 type controller struct {
@@ -26,7 +28,7 @@ type controller struct {
 	logger  *slog.Logger
 }
 
-func NewSubscriptionHandler(manager storage.Repository, log *slog.Logger) *controller {
+func NewSubscriptionHandler(manager subscriptionManager, log *slog.Logger) *controller {
 	return &controller{manager, log}
 }
 
@@ -43,18 +45,16 @@ func NewSubscriptionHandler(manager storage.Repository, log *slog.Logger) *contr
 func (ctr controller) GetSubscriptionListByUserID(echo echo.Context) error {
 	ctr.logger.Debug("Get Request for Subscription List")
 
-	userCookie, err := echo.Cookie("userId")
-	if err != nil {
-		return echo.NoContent(http.StatusBadRequest)
-	}
-
-	uuidID, err := uuid.Parse(userCookie.Value)
+	uuidID, err := callerID(echo)
 	if err != nil {
 		return echo.NoContent(http.StatusBadRequest)
 	}
 
 	res, err := ctr.manager.GetSubscriptionListByUserID(echo.Request().Context(), uuidID)
 	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
 
 		if errors.Is(err, models.ErrNotFound) {
 			return echo.NoContent(http.StatusNotFound)
@@ -65,9 +65,12 @@ func (ctr controller) GetSubscriptionListByUserID(echo echo.Context) error {
 	var dtoSubList []models.SubscriptionListDTO
 
 	for _, v := range res {
-		listDTO := models.SubscriptionListDTO{StartDate: v.StartDate,
-			Price:       v.Price,
-			ServiceName: v.ServiceName,
+		listDTO := models.SubscriptionListDTO{StartDate: pgtype.Date{Time: v.StartDate, Valid: true},
+			Price:          v.Price,
+			Seats:          v.Seats,
+			ServiceName:    v.ServiceName,
+			OrganizationID: v.OrganizationID,
+			Department:     v.Department,
 		}
 		dtoSubList = append(dtoSubList, listDTO)
 	}
@@ -77,13 +80,18 @@ func (ctr controller) GetSubscriptionListByUserID(echo echo.Context) error {
 
 // PostSubscription godoc
 // @Summary Создать новую подписку
-// @Description Добавляет подписку на сервис
+// @Description Добавляет подписку на сервис. Стоимость подписки — цена места, умноженная на число мест (seats).
+// @Description Подписку организации (organization_id) может оформить только участник этой организации
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param subscription body models.SubscriptionListJSON true "Данные подписки"
 // @Success 200 {string} string "Подписка успешно создана"
 // @Failure 400 {string} string "Неправильный запрос или дубликат подписки"
+// @Failure 403 {string} string "Пользователь не состоит в организации"
+// @Failure 409 {object} map[string]string "Запись уже существует"
+// @Failure 422 {object} map[string]string "Нарушено ограничение, в constraint и column его имя и столбец"
+// @Failure 503 {object} map[string]string "Сервис временно недоступен, повторите запрос"
 // @Failure 500 {string} string "Внутренняя ошибка сервера"
 // @Router /subscriptions [post]
 func (ctr controller) PostSubscription(echo echo.Context) error {
@@ -96,11 +104,19 @@ func (ctr controller) PostSubscription(echo echo.Context) error {
 	}
 
 	if err := ctr.manager.PostSubscription(echo.Request().Context(), sub); err != nil {
-		if errors.Is(err, models.ErrUnique) {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		if errors.Is(err, models.ErrInvalidSubscription) {
 
 			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Неправильный запрос или дубликат подписки"})
 		}
 
+		if errors.Is(err, models.ErrForbidden) {
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Пользователь не состоит в организации"})
+		}
+
 		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Неправильный запрос или дубликат подписки"})
 	}
 
@@ -114,8 +130,13 @@ func (ctr controller) PostSubscription(echo echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id query int true "ID подписки для удаления"
+// @Param userId header string true "userId из cookie, владелец подписки"
 // @Success 200 {string} string "Подписка успешно удалена"
 // @Failure 400 {string} string "Некорректный id или не найдена подписка"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Подписка принадлежит другому пользователю"
+// @Failure 422 {object} map[string]string "Нарушено ограничение, в constraint и column его имя и столбец"
+// @Failure 503 {object} map[string]string "Сервис временно недоступен, повторите запрос"
 // @Failure 500 {string} string "Внутренняя ошибка сервера"
 // @Router /subscriptions [delete]
 func (ctr controller) DeleteSubscription(echo echo.Context) error {
@@ -128,10 +149,23 @@ func (ctr controller) DeleteSubscription(echo echo.Context) error {
 		return echo.NoContent(http.StatusInternalServerError)
 	}
 
-	if err := ctr.manager.DeleteSubscription(echo.Request().Context(), id); err != nil {
+	userID, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	if err := ctr.manager.DeleteSubscription(echo.Request().Context(), userID, id); err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
 		if errors.Is(err, models.ErrNotFound) {
 			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректный id или не найдена подписка"})
 		}
+
+		if errors.Is(err, models.ErrForbidden) {
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Подписка принадлежит другому пользователю"})
+		}
 		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
 	}
 
@@ -140,14 +174,20 @@ func (ctr controller) DeleteSubscription(echo echo.Context) error {
 
 // UpdateSubscription godoc
 // @Summary Обновить подписку
-// @Description Обновляет подписку по id переданному в query-параметрах
+// @Description Обновляет подписку по id переданному в query-параметрах. Изменение числа мест сохраняется в истории мест
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param id query int true "ID подписки для обновления"
 // @Param subscription body models.SubscriptionListJSON true "Данные подписки для обновления"
+// @Param userId header string true "userId из cookie, владелец подписки"
 // @Success 200 {string} string "Подписка успешно обновлена"
 // @Failure 400 {string} string "Неправильный запрос или невалидные данные"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Подписка принадлежит другому пользователю или пользователь не состоит в организации"
+// @Failure 409 {object} map[string]string "Запись уже существует"
+// @Failure 422 {object} map[string]string "Нарушено ограничение, в constraint и column его имя и столбец"
+// @Failure 503 {object} map[string]string "Сервис временно недоступен, повторите запрос"
 // @Failure 500 {string} string "Внутренняя ошибка сервера"
 // @Router /subscriptions [put]
 func (ctr controller) UpdateSubscription(echo echo.Context) error {
@@ -166,7 +206,23 @@ func (ctr controller) UpdateSubscription(echo echo.Context) error {
 		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Неправильный запрос или невалидные данные"})
 	}
 
-	if err := ctr.manager.UpdateSubscription(echo.Request().Context(), sub, id); err != nil {
+	userID, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	if err := ctr.manager.UpdateSubscription(echo.Request().Context(), userID, sub, id); err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		switch {
+		case errors.Is(err, models.ErrInvalidSubscription), errors.Is(err, models.ErrNotFound):
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Неправильный запрос или невалидные данные"})
+		case errors.Is(err, models.ErrForbidden):
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Подписка принадлежит другому пользователю или пользователь не состоит в организации"})
+		}
+
 		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
 	}
 
@@ -176,6 +232,8 @@ func (ctr controller) UpdateSubscription(echo echo.Context) error {
 // GetTotalPeriodCostByDatesAndServiceName godoc
 // @Summary Получить сумму стоимости по датам и имени сервиса
 // @Description Возвращает общую стоимость подписок на сервис в указанный период
+// @Description Стоимость подписки — цена места, умноженная на число мест.
+// @Description Совместные подписки домохозяйства учитываются долей пользователя
 // @Tags subscriptions
 // @Accept json
 // @Produce json
@@ -195,8 +253,29 @@ func (ctr controller) GetTotalPeriodCostByDatesAndServiceName(echo echo.Context)
 
 	res, err := ctr.manager.GetTotalPeriodCostByDatesAndServiceName(echo.Request().Context(), sub)
 	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		if errors.Is(err, models.ErrInvalidSubscription) {
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+		}
+
 		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
 	}
 
 	return echo.JSON(http.StatusOK, map[string]int{"result": res})
 }
+
+// userCookie names the user making the request, the identity of the whole API.
+const userCookie = middleware.UserIDCookie
+
+// callerID returns the user making the request from the userId cookie.
+func callerID(echo echo.Context) (uuid.UUID, error) {
+	cookie, err := echo.Cookie(userCookie)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return uuid.Parse(cookie.Value)
+}
@@ -2,7 +2,7 @@ package server_test
 
 import (
 	"errors"
-	"github.com/jackc/pgx/v5/pgtype"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -37,24 +37,14 @@ func TestGetSubscriptionListByUserID(t *testing.T) {
 			mockSetup: func(m *mock_server.MocksubscriptionManager) {
 				m.EXPECT().
 					GetSubscriptionListByUserID(gomock.Any(), userID).
-					Return([]models.SubscriptionListDB{
+					Return([]models.Subscription{
 						{
-							StartDate: func() pgtype.Date {
-								var d pgtype.Date
-								d.Time = time.Date(2023, 9, 0, 0, 0, 0, 0, time.UTC)
-								d.Valid = true
-								return d
-							}(),
+							StartDate:   time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
 							Price:       100,
 							ServiceName: "Spotify",
 						},
 						{
-							StartDate: func() pgtype.Date {
-								var d pgtype.Date
-								d.Time = time.Date(2023, 10, 0, 0, 0, 0, 0, time.UTC)
-								d.Valid = true
-								return d
-							}(),
+							StartDate:   time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
 							Price:       200,
 							ServiceName: "Netflix",
 						},
@@ -118,6 +108,7 @@ func TestPostSubscription(t *testing.T) {
 		jsonBody   string
 		mockSetup  func(m *mock_server.MocksubscriptionManager)
 		wantStatus int
+		wantBody   string
 	}{
 		{
 			name:     "BadRequest_BadJSON",
@@ -135,7 +126,31 @@ func TestPostSubscription(t *testing.T) {
 					PostSubscription(gomock.Any(), gomock.Any()).
 					Return(models.ErrUnique)
 			},
-			wantStatus: http.StatusBadRequest,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:     "CheckViolation",
+			jsonBody: `{"service_name": "Spotify", "price": 100, "start_date": "09-2023"}`,
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().
+					PostSubscription(gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("error adding to DB %w", &models.DBError{
+						Kind: models.ErrCheckViolation, Column: "billing_period", Constraint: "subscription_billing_period_check",
+						Cause: errors.New("check violation"),
+					}))
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `"constraint":"subscription_billing_period_check"`,
+		},
+		{
+			name:     "Unavailable",
+			jsonBody: `{"service_name": "Spotify", "price": 100, "start_date": "09-2023"}`,
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().
+					PostSubscription(gomock.Any(), gomock.Any()).
+					Return(&models.DBError{Kind: models.ErrUnavailable, Cause: errors.New("canceling statement")})
+			},
+			wantStatus: http.StatusServiceUnavailable,
 		},
 		{
 			name:     "InternalServerError",
@@ -181,6 +196,14 @@ func TestPostSubscription(t *testing.T) {
 			if rec.Code != tt.wantStatus {
 				t.Errorf("expected %d, got %d", tt.wantStatus, rec.Code)
 			}
+
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("expected %s in %s", tt.wantBody, rec.Body.String())
+			}
+
+			if tt.wantStatus == http.StatusServiceUnavailable && rec.Header().Get("Retry-After") == "" {
+				t.Error("expected a Retry-After header")
+			}
 		})
 	}
 }
@@ -191,45 +214,75 @@ func TestDeleteSubscription(t *testing.T) {
 
 	logger := slog.Default()
 	e := echo.New()
+	ownerID := uuid.New()
 
 	tests := []struct {
 		name       string
 		url        string
+		userID     string
 		mockSetup  func(m *mock_server.MocksubscriptionManager)
 		wantStatus int
 	}{
 		{
-			name: "Success",
-			url:  "/?id=123",
+			name:   "Success",
+			url:    "/?id=123",
+			userID: ownerID.String(),
 			mockSetup: func(m *mock_server.MocksubscriptionManager) {
 				m.EXPECT().
-					DeleteSubscription(gomock.Any(), 123).
+					DeleteSubscription(gomock.Any(), ownerID, 123).
 					Return(nil)
 			},
 			wantStatus: http.StatusOK,
 		},
+		{
+			name:       "Unauthorized_NoCookie",
+			url:        "/?id=123",
+			mockSetup:  func(m *mock_server.MocksubscriptionManager) {},
+			wantStatus: http.StatusUnauthorized,
+		},
 		{
 			name:       "BadRequest_InvalidID",
 			url:        "/?id=abc",
+			userID:     ownerID.String(),
 			mockSetup:  func(m *mock_server.MocksubscriptionManager) {}, // no expected mock calls (invalid ID parsing fails before mock)
 			wantStatus: http.StatusInternalServerError,
 		},
 		{
-			name: "NotFound",
-			url:  "/?id=123",
+			name:   "NotFound",
+			url:    "/?id=123",
+			userID: ownerID.String(),
 			mockSetup: func(m *mock_server.MocksubscriptionManager) {
 				m.EXPECT().
-					DeleteSubscription(gomock.Any(), 123).
+					DeleteSubscription(gomock.Any(), ownerID, 123).
 					Return(models.ErrNotFound)
 			},
 			wantStatus: http.StatusBadRequest,
 		},
 		{
-			name: "InternalServerError",
-			url:  "/?id=123",
+			name:   "Forbidden",
+			url:    "/?id=123",
+			userID: ownerID.String(),
 			mockSetup: func(m *mock_server.MocksubscriptionManager) {
 				m.EXPECT().
-					DeleteSubscription(gomock.Any(), 123).
+					DeleteSubscription(gomock.Any(), ownerID, 123).
+					Return(models.ErrForbidden)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Unauthorized_InvalidUserID",
+			url:        "/?id=123",
+			userID:     "not-a-uuid",
+			mockSetup:  func(m *mock_server.MocksubscriptionManager) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "InternalServerError",
+			url:    "/?id=123",
+			userID: ownerID.String(),
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().
+					DeleteSubscription(gomock.Any(), ownerID, 123).
 					Return(errors.New("db error"))
 			},
 			wantStatus: http.StatusInternalServerError,
@@ -245,6 +298,10 @@ func TestDeleteSubscription(t *testing.T) {
 			tt.mockSetup(mockManager)
 
 			req := httptest.NewRequest(http.MethodDelete, tt.url, nil)
+			if tt.userID != "" {
+				req.AddCookie(&http.Cookie{Name: "userId", Value: tt.userID})
+			}
+
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 
@@ -266,11 +323,13 @@ func TestUpdateSubscription(t *testing.T) {
 
 	logger := slog.Default()
 	e := echo.New()
+	ownerID := uuid.New()
 
 	tests := []struct {
 		name       string
 		url        string
 		jsonBody   string
+		noCookie   bool
 		mockSetup  func(m *mock_server.MocksubscriptionManager)
 		wantStatus int
 	}{
@@ -280,11 +339,19 @@ func TestUpdateSubscription(t *testing.T) {
 			jsonBody: `{"service_name": "Spotify", "price": 300, "start_date": "2025-09"}`,
 			mockSetup: func(m *mock_server.MocksubscriptionManager) {
 				m.EXPECT().
-					UpdateSubscription(gomock.Any(), gomock.Any(), 1).
+					UpdateSubscription(gomock.Any(), ownerID, gomock.Any(), 1).
 					Return(nil)
 			},
 			wantStatus: http.StatusOK,
 		},
+		{
+			name:       "Unauthorized_NoCookie",
+			url:        "/?id=1",
+			jsonBody:   `{"service_name": "Spotify", "price": 300, "start_date": "09-2025"}`,
+			noCookie:   true,
+			mockSetup:  func(m *mock_server.MocksubscriptionManager) {},
+			wantStatus: http.StatusUnauthorized,
+		},
 		{
 			name:       "BadRequest_InvalidID",
 			url:        "/?id=abc",
@@ -299,13 +366,35 @@ func TestUpdateSubscription(t *testing.T) {
 			mockSetup:  func(m *mock_server.MocksubscriptionManager) {},
 			wantStatus: http.StatusBadRequest,
 		},
+		{
+			name:     "BadRequest_Invalid",
+			url:      "/?id=1",
+			jsonBody: `{"service_name": "Spotify", "price": -300, "start_date": "09-2025"}`,
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().
+					UpdateSubscription(gomock.Any(), ownerID, gomock.Any(), 1).
+					Return(models.ErrInvalidSubscription)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "Forbidden",
+			url:      "/?id=1",
+			jsonBody: `{"service_name": "Spotify", "price": 300, "start_date": "09-2025"}`,
+			mockSetup: func(m *mock_server.MocksubscriptionManager) {
+				m.EXPECT().
+					UpdateSubscription(gomock.Any(), ownerID, gomock.Any(), 1).
+					Return(models.ErrForbidden)
+			},
+			wantStatus: http.StatusForbidden,
+		},
 		{
 			name:     "InternalServerError_ManagerError",
 			url:      "/?id=1",
 			jsonBody: `{"service_name": "Spotify", "price": 300, "start_date": "2025-09"}`,
 			mockSetup: func(m *mock_server.MocksubscriptionManager) {
 				m.EXPECT().
-					UpdateSubscription(gomock.Any(), gomock.Any(), 1).
+					UpdateSubscription(gomock.Any(), ownerID, gomock.Any(), 1).
 					Return(errors.New("db error"))
 			},
 			wantStatus: http.StatusInternalServerError,
@@ -323,6 +412,11 @@ func TestUpdateSubscription(t *testing.T) {
 			body := strings.NewReader(tt.jsonBody)
 			req := httptest.NewRequest(http.MethodPut, tt.url, body)
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			if !tt.noCookie {
+				req.AddCookie(&http.Cookie{Name: "userId", Value: ownerID.String()})
+			}
+
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 
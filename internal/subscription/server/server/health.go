@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/labstack/echo/v4"
+)
+
+//go:generate mockgen -source=health.go -destination=mock/healthrepository.go
+type healthManager interface {
+	Ready(ctx context.Context) (models.HealthJSON, bool)
+}
+
+type healthController struct {
+	manager healthManager
+	logger  *slog.Logger
+}
+
+func NewHealthHandler(manager healthManager, log *slog.Logger) *healthController {
+	return &healthController{manager, log}
+}
+
+// GetHealthz godoc
+// @Summary Проверка жизнеспособности
+// @Description Отвечает, пока процесс жив, не обращаясь к зависимостям
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthJSON
+// @Router /healthz [get]
+func (ctr healthController) GetHealthz(echo echo.Context) error {
+	return echo.JSON(http.StatusOK, models.HealthJSON{Status: models.HealthStatusOK})
+}
+
+// GetReadyz godoc
+// @Summary Проверка готовности
+// @Description Проверяет доступность БД и версию миграций, сообщает о сбоях фоновых задач.
+// @Description Перестает отвечать успехом с началом остановки сервиса
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthJSON
+// @Failure 503 {object} models.HealthJSON
+// @Router /readyz [get]
+func (ctr healthController) GetReadyz(echo echo.Context) error {
+	res, ready := ctr.manager.Ready(echo.Request().Context())
+	if !ready {
+		ctr.logger.Warn("Service is not ready", "Checks", res.Checks, "Status", res.Status)
+
+		return echo.JSON(http.StatusServiceUnavailable, res)
+	}
+
+	return echo.JSON(http.StatusOK, res)
+}
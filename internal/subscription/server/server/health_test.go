@@ -0,0 +1,94 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+)
+
+func TestGetHealthz(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	e := echo.New()
+
+	// The liveness probe never asks the dependencies.
+	handler := server.NewHealthHandler(mock_server.NewMockhealthManager(ctrl), slog.Default())
+
+	rec := httptest.NewRecorder()
+	if err := handler.GetHealthz(e.NewContext(httptest.NewRequest(http.MethodGet, "/healthz", nil), rec)); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"status":"ok"}` {
+		t.Errorf("expected 200 with status ok, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetReadyz(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	tests := []struct {
+		name       string
+		health     models.HealthJSON
+		ready      bool
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Ready",
+			health:     models.HealthJSON{Status: models.HealthStatusOK, Checks: map[string]string{"database": "ok"}},
+			ready:      true,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"ok","checks":{"database":"ok"}}`,
+		},
+		{
+			name: "NotReady",
+			health: models.HealthJSON{
+				Status: models.HealthStatusUnavailable,
+				Checks: map[string]string{"database": "connection refused"},
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   `{"status":"unavailable","checks":{"database":"connection refused"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockhealthManager(ctrl)
+			mockManager.EXPECT().Ready(gomock.Any()).Return(tt.health, tt.ready)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewHealthHandler(mockManager, logger)
+			if err := handler.GetReadyz(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+
+			if strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,474 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/household"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+//go:generate mockgen -source=household.go -destination=mock/householdrepository.go
+type householdManager interface {
+	PostHousehold(ctx context.Context, household models.HouseholdJSON) (int, error)
+	GetHousehold(ctx context.Context, id int) (models.HouseholdJSON, error)
+	AddHouseholdMember(ctx context.Context, member models.HouseholdMemberJSON) error
+	DeleteHouseholdMember(ctx context.Context, member models.HouseholdMemberJSON) error
+	PutSharedSubscription(ctx context.Context, shared models.SharedSubscriptionJSON) error
+	DeleteSharedSubscription(ctx context.Context, subscriptionID int) error
+	GetSharedSubscriptionsByHouseholdID(ctx context.Context, id int) ([]models.SharedSubscription, error)
+	GetHouseholdCharges(ctx context.Context, id int, from, to time.Time) ([]models.SharedCharge, error)
+}
+
+// subscriptionGetter finds the subscription being shared, its user must be the requester and its price bounds
+// the fixed shares.
+type subscriptionGetter interface {
+	GetSubscription(ctx context.Context, id int) (models.Subscription, error)
+}
+
+type householdController struct {
+	manager       householdManager
+	subscriptions subscriptionGetter
+	logger        *slog.Logger
+}
+
+func NewHouseholdHandler(manager householdManager, subscriptions subscriptionGetter, log *slog.Logger) *householdController {
+	return &householdController{manager, subscriptions, log}
+}
+
+// PostHousehold godoc
+// @Summary Создать домохозяйство
+// @Description Создает домохозяйство (семью, группу) для совместных подписок. Владелец становится его участником
+// @Tags households
+// @Accept json
+// @Produce json
+// @Param household body models.HouseholdJSON true "Название, владелец и участники"
+// @Success 200 {object} models.HouseholdJSON
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 503 {object} map[string]string "Сервис временно недоступен, повторите запрос"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/household [post]
+func (ctr householdController) PostHousehold(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for POST Household")
+
+	var res models.HouseholdJSON
+
+	if err := echo.Bind(&res); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	if err := household.ValidateHousehold(&res); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	id, err := ctr.manager.PostHousehold(echo.Request().Context(), res)
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	res.ID = id
+
+	return echo.JSON(http.StatusOK, res)
+}
+
+// GetHousehold godoc
+// @Summary Получить домохозяйство
+// @Description Возвращает домохозяйство с его участниками по id из query-параметров. Доступно его участникам
+// @Tags households
+// @Produce json
+// @Param id query int true "ID домохозяйства"
+// @Param userId header string true "userId из cookie, участник домохозяйства"
+// @Success 200 {object} models.HouseholdJSON
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Нет доступа к домохозяйству"
+// @Failure 404 {string} string "Домохозяйство не найдено"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/household [get]
+func (ctr householdController) GetHousehold(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Household")
+
+	id, err := strconv.Atoi(echo.QueryParam("id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	res, err := ctr.member(echo.Request().Context(), id, requester)
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			return echo.JSON(http.StatusNotFound, map[string]string{"result": "Домохозяйство не найдено"})
+		case errors.Is(err, models.ErrForbidden):
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Нет доступа к домохозяйству"})
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	return echo.JSON(http.StatusOK, res)
+}
+
+// AddHouseholdMember godoc
+// @Summary Добавить участника домохозяйства
+// @Description Добавляет участника в домохозяйство. Доступно владельцу домохозяйства
+// @Tags households
+// @Accept json
+// @Produce json
+// @Param member body models.HouseholdMemberJSON true "Домохозяйство и пользователь"
+// @Param userId header string true "userId из cookie, владелец домохозяйства"
+// @Success 200 {string} string "Участник успешно добавлен"
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Нет доступа к домохозяйству"
+// @Failure 404 {string} string "Домохозяйство не найдено"
+// @Failure 409 {object} map[string]string "Запись уже существует"
+// @Failure 422 {object} map[string]string "Нарушено ограничение, в constraint и column его имя и столбец"
+// @Failure 503 {object} map[string]string "Сервис временно недоступен, повторите запрос"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/household/member [post]
+func (ctr householdController) AddHouseholdMember(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for POST Household Member")
+
+	var member models.HouseholdMemberJSON
+
+	if err := echo.Bind(&member); err != nil || member.UserID == uuid.Nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	ctx := echo.Request().Context()
+
+	err = ctr.owner(ctx, member.HouseholdID, requester)
+	if err == nil {
+		err = ctr.manager.AddHouseholdMember(ctx, member)
+	}
+
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			return echo.JSON(http.StatusNotFound, map[string]string{"result": "Домохозяйство не найдено"})
+		case errors.Is(err, models.ErrForbidden):
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Нет доступа к домохозяйству"})
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	return echo.JSON(http.StatusOK, map[string]string{"result": "Участник успешно добавлен"})
+}
+
+// DeleteHouseholdMember godoc
+// @Summary Удалить участника домохозяйства
+// @Description Удаляет участника вместе с его долями в совместных подписках. Подписки, которые он оплачивает,
+// @Description перестают быть совместными. Доступно владельцу домохозяйства, самого владельца удалить нельзя
+// @Tags households
+// @Produce json
+// @Param household_id query int true "ID домохозяйства"
+// @Param user_id query string true "ID пользователя"
+// @Param userId header string true "userId из cookie, владелец домохозяйства"
+// @Success 200 {string} string "Участник успешно удален"
+// @Failure 400 {string} string "Некорректные параметры, владелец или участник не найден"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Нет доступа к домохозяйству"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/household/member [delete]
+func (ctr householdController) DeleteHouseholdMember(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Delete Household Member")
+
+	householdID, err := strconv.Atoi(echo.QueryParam("household_id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректные параметры, владелец или участник не найден"})
+	}
+
+	userID, err := uuid.Parse(echo.QueryParam("user_id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректные параметры, владелец или участник не найден"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	ctx := echo.Request().Context()
+
+	// The owner is the requester, they can't be removed.
+	err = ctr.owner(ctx, householdID, requester)
+	if err == nil && userID == requester {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректные параметры, владелец или участник не найден"})
+	}
+
+	if err == nil {
+		err = ctr.manager.DeleteHouseholdMember(ctx, models.HouseholdMemberJSON{HouseholdID: householdID, UserID: userID})
+	}
+
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректные параметры, владелец или участник не найден"})
+		case errors.Is(err, models.ErrForbidden):
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Нет доступа к домохозяйству"})
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	return echo.JSON(http.StatusOK, map[string]string{"result": "Участник успешно удален"})
+}
+
+// PutSharedSubscription godoc
+// @Summary Сделать подписку совместной
+// @Description Задает правило разделения стоимости подписки между участниками домохозяйства: поровну (split=equal),
+// @Description в процентах (split=percentage, value — процент участника) или фиксированными суммами (split=fixed,
+// @Description value — сумма участника). Владелец подписки платит за нее и получает остаток, в shares его не указывают.
+// @Description Заменяет ранее заданное правило. Доступно владельцу подписки
+// @Tags households
+// @Accept json
+// @Produce json
+// @Param share body models.SharedSubscriptionJSON true "Подписка, домохозяйство и доли участников"
+// @Param userId header string true "userId из cookie, владелец подписки"
+// @Success 200 {string} string "Правило разделения успешно сохранено"
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Подписка принадлежит другому пользователю"
+// @Failure 404 {string} string "Подписка не найдена"
+// @Failure 422 {object} map[string]string "Нарушено ограничение, в constraint и column его имя и столбец"
+// @Failure 503 {object} map[string]string "Сервис временно недоступен, повторите запрос"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/household/share [put]
+func (ctr householdController) PutSharedSubscription(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for PUT Shared Subscription")
+
+	var shared models.SharedSubscriptionJSON
+
+	if err := echo.Bind(&shared); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	ctx := echo.Request().Context()
+
+	sub, err := ctr.subscriptions.GetSubscription(ctx, shared.SubscriptionID)
+	if err == nil && sub.UserID != requester {
+		err = models.ErrForbidden
+	}
+
+	if err == nil {
+		if err := household.ValidateShare(shared, sub.UserID, sub.Cost()); err != nil {
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+		}
+
+		err = ctr.manager.PutSharedSubscription(ctx, shared)
+	}
+
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			return echo.JSON(http.StatusNotFound, map[string]string{"result": "Подписка не найдена"})
+		case errors.Is(err, models.ErrForbidden):
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Подписка принадлежит другому пользователю"})
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	return echo.JSON(http.StatusOK, map[string]string{"result": "Правило разделения успешно сохранено"})
+}
+
+// DeleteSharedSubscription godoc
+// @Summary Прекратить совместное использование подписки
+// @Description Удаляет правило разделения, стоимость подписки снова целиком приходится на ее владельца.
+// @Description Доступно владельцу подписки
+// @Tags households
+// @Produce json
+// @Param subscription_id query int true "ID подписки"
+// @Param userId header string true "userId из cookie, владелец подписки"
+// @Success 200 {string} string "Правило разделения успешно удалено"
+// @Failure 400 {string} string "Некорректный id или подписка не совместная"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Подписка принадлежит другому пользователю"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/household/share [delete]
+func (ctr householdController) DeleteSharedSubscription(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Delete Shared Subscription")
+
+	id, err := strconv.Atoi(echo.QueryParam("subscription_id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректный id или подписка не совместная"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	ctx := echo.Request().Context()
+
+	sub, err := ctr.subscriptions.GetSubscription(ctx, id)
+	if err == nil && sub.UserID != requester {
+		err = models.ErrForbidden
+	}
+
+	if err == nil {
+		err = ctr.manager.DeleteSharedSubscription(ctx, id)
+	}
+
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректный id или подписка не совместная"})
+		case errors.Is(err, models.ErrForbidden):
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Подписка принадлежит другому пользователю"})
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	return echo.JSON(http.StatusOK, map[string]string{"result": "Правило разделения успешно удалено"})
+}
+
+// SettleUp godoc
+// @Summary Рассчитать взаиморасчеты
+// @Description Считает, кто кому и сколько должен за списания совместных подписок домохозяйства за период,
+// @Description отдельно по каждой валюте. Число платежей меньше числа участников. Доступно участникам домохозяйства
+// @Tags households
+// @Produce json
+// @Param id query int true "ID домохозяйства"
+// @Param start_date query string true "Начало периода в формате MM-YYYY"
+// @Param end_date query string true "Конец периода в формате MM-YYYY"
+// @Param userId header string true "userId из cookie, участник домохозяйства"
+// @Success 200 {object} models.SettleUpJSON
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Нет доступа к домохозяйству"
+// @Failure 404 {string} string "Домохозяйство не найдено"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/household/settle-up [get]
+func (ctr householdController) SettleUp(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Household Settle Up")
+
+	id, err := strconv.Atoi(echo.QueryParam("id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	from, fromErr := time.Parse(models.MonthLayout, echo.QueryParam("start_date"))
+	to, toErr := time.Parse(models.MonthLayout, echo.QueryParam("end_date"))
+
+	if fromErr != nil || toErr != nil || to.Before(from) {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	ctx := echo.Request().Context()
+
+	var shared []models.SharedSubscription
+
+	_, err = ctr.member(ctx, id, requester)
+	if err == nil {
+		shared, err = ctr.manager.GetSharedSubscriptionsByHouseholdID(ctx, id)
+	}
+
+	var charges []models.SharedCharge
+	if err == nil {
+		charges, err = ctr.manager.GetHouseholdCharges(ctx, id, from, to)
+	}
+
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			return echo.JSON(http.StatusNotFound, map[string]string{"result": "Домохозяйство не найдено"})
+		case errors.Is(err, models.ErrForbidden):
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Нет доступа к домохозяйству"})
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	return echo.JSON(http.StatusOK, models.SettleUpJSON{
+		HouseholdID: id,
+		StartDate:   from.Format(models.MonthLayout),
+		EndDate:     to.Format(models.MonthLayout),
+		Settlements: household.SettleUp(shared, charges),
+	})
+}
+
+// member returns the household when the user is a member of it, ErrForbidden when they aren't.
+func (ctr householdController) member(ctx context.Context, householdID int, userID uuid.UUID) (models.HouseholdJSON, error) {
+	res, err := ctr.manager.GetHousehold(ctx, householdID)
+	if err != nil {
+		return res, err
+	}
+
+	if res.OwnerID != userID && !slices.Contains(res.Members, userID) {
+		return res, models.ErrForbidden
+	}
+
+	return res, nil
+}
+
+// owner checks the user owns the household, ErrForbidden when they don't.
+func (ctr householdController) owner(ctx context.Context, householdID int, userID uuid.UUID) error {
+	res, err := ctr.manager.GetHousehold(ctx, householdID)
+	if err != nil {
+		return err
+	}
+
+	if res.OwnerID != userID {
+		return models.ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,559 @@
+package server_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+)
+
+var (
+	householdPayer  = uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")
+	householdMember = uuid.MustParse("7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d")
+	householdFamily = models.HouseholdJSON{
+		ID: 1, Name: "Семья", OwnerID: householdPayer, Members: []uuid.UUID{householdPayer, householdMember},
+	}
+)
+
+func TestPutSharedSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	sub := models.Subscription{ID: 1, UserID: householdPayer, Price: 400}
+
+	tests := []struct {
+		name       string
+		body       string
+		userID     uuid.UUID
+		mockSetup  func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter)
+		wantStatus int
+	}{
+		{
+			name:   "Success",
+			body:   `{"subscription_id":1,"household_id":1,"split":"fixed","shares":[{"user_id":"` + householdMember.String() + `","value":150}]}`,
+			userID: householdPayer,
+			mockSetup: func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 1).Return(sub, nil)
+				m.EXPECT().PutSharedSubscription(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "BadRequest_FixedOverPrice",
+			body:   `{"subscription_id":1,"household_id":1,"split":"fixed","shares":[{"user_id":"` + householdMember.String() + `","value":500}]}`,
+			userID: householdPayer,
+			mockSetup: func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 1).Return(sub, nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Forbidden_NotTheOwner",
+			body:   `{"subscription_id":1,"household_id":1,"split":"equal","shares":[{"user_id":"` + householdMember.String() + `"}]}`,
+			userID: householdMember,
+			mockSetup: func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 1).Return(sub, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Unauthorized_NoCookie",
+			body:       `{"subscription_id":1,"household_id":1,"split":"equal","shares":[{"user_id":"` + householdMember.String() + `"}]}`,
+			mockSetup:  func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "NotFound",
+			body:   `{"subscription_id":2,"household_id":1,"split":"equal","shares":[{"user_id":"` + householdMember.String() + `"}]}`,
+			userID: householdPayer,
+			mockSetup: func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 2).Return(models.Subscription{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Unprocessable_NotAMember",
+			body:   `{"subscription_id":1,"household_id":1,"split":"equal","shares":[{"user_id":"` + householdMember.String() + `"}]}`,
+			userID: householdPayer,
+			mockSetup: func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 1).Return(sub, nil)
+				m.EXPECT().PutSharedSubscription(gomock.Any(), gomock.Any()).
+					Return(&models.DBError{Kind: models.ErrForeignKey, Constraint: "subscription_share_household_id_user_id_fkey"})
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockhouseholdManager(ctrl)
+			mockSubscriptions := mock_server.NewMocksubscriptionGetter(ctrl)
+			tt.mockSetup(mockManager, mockSubscriptions)
+
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			addUserCookie(req, tt.userID)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewHouseholdHandler(mockManager, mockSubscriptions, logger)
+			if err := handler.PutSharedSubscription(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestSettleUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	july := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		url        string
+		userID     uuid.UUID
+		mockSetup  func(m *mock_server.MockhouseholdManager)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "Success",
+			url:    "/?id=1&start_date=07-2025&end_date=08-2025",
+			userID: householdMember,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(householdFamily, nil)
+				m.EXPECT().GetSharedSubscriptionsByHouseholdID(gomock.Any(), 1).Return([]models.SharedSubscription{{
+					Subscription: models.Subscription{ID: 1, UserID: householdPayer},
+					HouseholdID:  1,
+					Split:        models.SplitEqual,
+					Shares:       []models.ShareJSON{{UserID: householdMember}},
+				}}, nil)
+				m.EXPECT().GetHouseholdCharges(gomock.Any(), 1, july, july.AddDate(0, 1, 0)).Return([]models.SharedCharge{
+					{SubscriptionID: 1, Month: july, Price: 399, Currency: "RUB"},
+					{SubscriptionID: 1, Month: july.AddDate(0, 1, 0), Price: 399, Currency: "RUB"},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"household_id":1,"start_date":"07-2025","end_date":"08-2025","settlements":[` +
+				`{"from":"` + householdMember.String() + `","to":"` + householdPayer.String() + `","amount":398,"currency":"RUB"}]}`,
+		},
+		{
+			name:       "BadRequest_ReversedPeriod",
+			url:        "/?id=1&start_date=08-2025&end_date=07-2025",
+			userID:     householdMember,
+			mockSetup:  func(m *mock_server.MockhouseholdManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Forbidden_NotAMember",
+			url:    "/?id=1&start_date=07-2025&end_date=08-2025",
+			userID: uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(householdFamily, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "NotFound",
+			url:    "/?id=2&start_date=07-2025&end_date=08-2025",
+			userID: householdMember,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 2).Return(models.HouseholdJSON{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Unauthorized_NoCookie",
+			url:        "/?id=1&start_date=07-2025&end_date=08-2025",
+			mockSetup:  func(m *mock_server.MockhouseholdManager) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "InternalServerError",
+			url:    "/?id=1&start_date=07-2025&end_date=08-2025",
+			userID: householdPayer,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(householdFamily, nil)
+				m.EXPECT().GetSharedSubscriptionsByHouseholdID(gomock.Any(), 1).Return(nil, errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockhouseholdManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			addUserCookie(req, tt.userID)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewHouseholdHandler(mockManager, nil, logger)
+			if err := handler.SettleUp(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetHousehold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	tests := []struct {
+		name       string
+		url        string
+		userID     uuid.UUID
+		mockSetup  func(m *mock_server.MockhouseholdManager)
+		wantStatus int
+	}{
+		{
+			name:   "Success_Member",
+			url:    "/?id=1",
+			userID: householdMember,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(householdFamily, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Forbidden_NotAMember",
+			url:    "/?id=1",
+			userID: uuid.New(),
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(householdFamily, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Unauthorized_NoCookie",
+			url:        "/?id=1",
+			mockSetup:  func(m *mock_server.MockhouseholdManager) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "NotFound",
+			url:    "/?id=2",
+			userID: householdMember,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 2).Return(models.HouseholdJSON{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockhouseholdManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			addUserCookie(req, tt.userID)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewHouseholdHandler(mockManager, nil, logger)
+			if err := handler.GetHousehold(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestAddHouseholdMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	newMember := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	body := `{"household_id":1,"user_id":"` + newMember.String() + `"}`
+
+	tests := []struct {
+		name       string
+		userID     uuid.UUID
+		mockSetup  func(m *mock_server.MockhouseholdManager)
+		wantStatus int
+	}{
+		{
+			name:   "Success_Owner",
+			userID: householdPayer,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(householdFamily, nil)
+				m.EXPECT().AddHouseholdMember(gomock.Any(), models.HouseholdMemberJSON{HouseholdID: 1, UserID: newMember}).
+					Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Forbidden_Member",
+			userID: householdMember,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(householdFamily, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Forbidden_Stranger",
+			userID: newMember,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(householdFamily, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Unauthorized_NoCookie",
+			mockSetup:  func(m *mock_server.MockhouseholdManager) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "NotFound",
+			userID: householdPayer,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(models.HouseholdJSON{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockhouseholdManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			addUserCookie(req, tt.userID)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewHouseholdHandler(mockManager, nil, logger)
+			if err := handler.AddHouseholdMember(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestDeleteHouseholdMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	tests := []struct {
+		name       string
+		url        string
+		userID     uuid.UUID
+		mockSetup  func(m *mock_server.MockhouseholdManager)
+		wantStatus int
+	}{
+		{
+			name:   "Success_Owner",
+			url:    "/?household_id=1&user_id=" + householdMember.String(),
+			userID: householdPayer,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(householdFamily, nil)
+				m.EXPECT().DeleteHouseholdMember(gomock.Any(),
+					models.HouseholdMemberJSON{HouseholdID: 1, UserID: householdMember}).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Forbidden_Member",
+			url:    "/?household_id=1&user_id=" + householdPayer.String(),
+			userID: householdMember,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(householdFamily, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "BadRequest_Owner",
+			url:    "/?household_id=1&user_id=" + householdPayer.String(),
+			userID: householdPayer,
+			mockSetup: func(m *mock_server.MockhouseholdManager) {
+				m.EXPECT().GetHousehold(gomock.Any(), 1).Return(householdFamily, nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Unauthorized_NoCookie",
+			url:        "/?household_id=1&user_id=" + householdMember.String(),
+			mockSetup:  func(m *mock_server.MockhouseholdManager) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockhouseholdManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodDelete, tt.url, nil)
+			addUserCookie(req, tt.userID)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewHouseholdHandler(mockManager, nil, logger)
+			if err := handler.DeleteHouseholdMember(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestDeleteSharedSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	sub := models.Subscription{ID: 1, UserID: householdPayer, Price: 400}
+
+	tests := []struct {
+		name       string
+		url        string
+		userID     uuid.UUID
+		mockSetup  func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter)
+		wantStatus int
+	}{
+		{
+			name:   "Success",
+			url:    "/?subscription_id=1",
+			userID: householdPayer,
+			mockSetup: func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 1).Return(sub, nil)
+				m.EXPECT().DeleteSharedSubscription(gomock.Any(), 1).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Forbidden_Member",
+			url:    "/?subscription_id=1",
+			userID: householdMember,
+			mockSetup: func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 1).Return(sub, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "BadRequest_NotShared",
+			url:    "/?subscription_id=1",
+			userID: householdPayer,
+			mockSetup: func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 1).Return(sub, nil)
+				m.EXPECT().DeleteSharedSubscription(gomock.Any(), 1).Return(models.ErrNotFound)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Unauthorized_NoCookie",
+			url:        "/?subscription_id=1",
+			mockSetup:  func(m *mock_server.MockhouseholdManager, s *mock_server.MocksubscriptionGetter) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockhouseholdManager(ctrl)
+			mockSubscriptions := mock_server.NewMocksubscriptionGetter(ctrl)
+			tt.mockSetup(mockManager, mockSubscriptions)
+
+			req := httptest.NewRequest(http.MethodDelete, tt.url, nil)
+			addUserCookie(req, tt.userID)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewHouseholdHandler(mockManager, mockSubscriptions, logger)
+			if err := handler.DeleteSharedSubscription(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func addUserCookie(req *http.Request, userID uuid.UUID) {
+	if userID != uuid.Nil {
+		req.AddCookie(&http.Cookie{Name: "userId", Value: userID.String()})
+	}
+}
@@ -0,0 +1,214 @@
+package server_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, template *x509.Certificate, parent *testCert) testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeTestFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestNewHTTPServerAppliesConfig(t *testing.T) {
+	t.Parallel()
+
+	srv, err := server.NewHTTPServer(config.ServerConfig{
+		Host:               "127.0.0.1",
+		Port:               8080,
+		ServerReadTimeout:  5 * time.Second,
+		ServerWriteTimeout: 6 * time.Second,
+		ServerIdleTimeout:  time.Minute,
+		ReadHeaderTimeout:  2 * time.Second,
+		MaxHeaderBytes:     4096,
+	}, http.NotFoundHandler())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if srv.Addr != "127.0.0.1:8080" || srv.ReadTimeout != 5*time.Second || srv.WriteTimeout != 6*time.Second ||
+		srv.IdleTimeout != time.Minute || srv.ReadHeaderTimeout != 2*time.Second || srv.MaxHeaderBytes != 4096 {
+		t.Errorf("config not applied: %+v", srv)
+	}
+
+	if srv.TLSConfig != nil {
+		t.Error("expected no TLS without cert files")
+	}
+}
+
+func TestNewHTTPServerMutualTLS(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil)
+	serverCert := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, &ca)
+	clientCert := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, &ca)
+
+	dir := t.TempDir()
+
+	srv, err := server.NewHTTPServer(config.ServerConfig{
+		Host: "127.0.0.1",
+		Port: 8443,
+		TLS: config.TLSConfig{
+			CertFile:     writeTestFile(t, dir, "server.crt", serverCert.certPEM),
+			KeyFile:      writeTestFile(t, dir, "server.key", serverCert.keyPEM),
+			ClientCAFile: writeTestFile(t, dir, "ca.crt", ca.certPEM),
+		},
+	}, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewUnstartedServer(srv.Handler)
+	ts.Config = srv
+	ts.TLS = srv.TLSConfig
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	newClient := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: certs,
+			MinVersion:   tls.VersionTLS12,
+		}}}
+	}
+
+	clientPair, err := tls.X509KeyPair(clientCert.certPEM, clientCert.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := newClient(clientPair).Get(ts.URL)
+	if err != nil {
+		t.Fatalf("expected the client certificate to be accepted: %v", err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", resp.StatusCode)
+	}
+
+	if resp, err := newClient().Get(ts.URL); err == nil {
+		resp.Body.Close()
+		t.Error("expected the request without client certificate to fail")
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		port     int
+		target   string
+		location string
+	}{
+		{"DefaultPort", 443, "http://api.example.com:8080/subscription/users?user_id=1", "https://api.example.com/subscription/users?user_id=1"},
+		{"CustomPort", 8443, "http://api.example.com/subscription", "https://api.example.com:8443/subscription"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			server.RedirectHandler(tt.port).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.target, nil))
+
+			if rec.Code != http.StatusPermanentRedirect || rec.Header().Get("Location") != tt.location {
+				t.Errorf("expected 308 to %s, got %d to %s", tt.location, rec.Code, rec.Header().Get("Location"))
+			}
+		})
+	}
+}
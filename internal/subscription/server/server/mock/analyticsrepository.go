@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: analytics.go
+
+// Package mock_server is a generated GoMock package.
+package mock_server
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockanalyticsManager is a mock of analyticsManager interface.
+type MockanalyticsManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockanalyticsManagerMockRecorder
+}
+
+// MockanalyticsManagerMockRecorder is the mock recorder for MockanalyticsManager.
+type MockanalyticsManagerMockRecorder struct {
+	mock *MockanalyticsManager
+}
+
+// NewMockanalyticsManager creates a new mock instance.
+func NewMockanalyticsManager(ctrl *gomock.Controller) *MockanalyticsManager {
+	mock := &MockanalyticsManager{ctrl: ctrl}
+	mock.recorder = &MockanalyticsManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockanalyticsManager) EXPECT() *MockanalyticsManagerMockRecorder {
+	return m.recorder
+}
+
+// GetSpendAnalytics mocks base method.
+func (m *MockanalyticsManager) GetSpendAnalytics(ctx context.Context, filter models.SpendAnalyticsFilter) (models.SpendAnalyticsDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpendAnalytics", ctx, filter)
+	ret0, _ := ret[0].(models.SpendAnalyticsDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpendAnalytics indicates an expected call of GetSpendAnalytics.
+func (mr *MockanalyticsManagerMockRecorder) GetSpendAnalytics(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpendAnalytics", reflect.TypeOf((*MockanalyticsManager)(nil).GetSpendAnalytics), ctx, filter)
+}
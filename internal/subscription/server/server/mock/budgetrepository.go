@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: budget.go
+
+// Package mock_server is a generated GoMock package.
+package mock_server
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockbudgetManager is a mock of budgetManager interface.
+type MockbudgetManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockbudgetManagerMockRecorder
+}
+
+// MockbudgetManagerMockRecorder is the mock recorder for MockbudgetManager.
+type MockbudgetManagerMockRecorder struct {
+	mock *MockbudgetManager
+}
+
+// NewMockbudgetManager creates a new mock instance.
+func NewMockbudgetManager(ctrl *gomock.Controller) *MockbudgetManager {
+	mock := &MockbudgetManager{ctrl: ctrl}
+	mock.recorder = &MockbudgetManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockbudgetManager) EXPECT() *MockbudgetManagerMockRecorder {
+	return m.recorder
+}
+
+// DeleteBudget mocks base method.
+func (m *MockbudgetManager) DeleteBudget(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBudget", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBudget indicates an expected call of DeleteBudget.
+func (mr *MockbudgetManagerMockRecorder) DeleteBudget(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBudget", reflect.TypeOf((*MockbudgetManager)(nil).DeleteBudget), ctx, id)
+}
+
+// GetBudget mocks base method.
+func (m *MockbudgetManager) GetBudget(ctx context.Context, id int) (models.BudgetJSON, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBudget", ctx, id)
+	ret0, _ := ret[0].(models.BudgetJSON)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBudget indicates an expected call of GetBudget.
+func (mr *MockbudgetManagerMockRecorder) GetBudget(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudget", reflect.TypeOf((*MockbudgetManager)(nil).GetBudget), ctx, id)
+}
+
+// GetBudgetsByUserID mocks base method.
+func (m *MockbudgetManager) GetBudgetsByUserID(ctx context.Context, id uuid.UUID) ([]models.BudgetJSON, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBudgetsByUserID", ctx, id)
+	ret0, _ := ret[0].([]models.BudgetJSON)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBudgetsByUserID indicates an expected call of GetBudgetsByUserID.
+func (mr *MockbudgetManagerMockRecorder) GetBudgetsByUserID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudgetsByUserID", reflect.TypeOf((*MockbudgetManager)(nil).GetBudgetsByUserID), ctx, id)
+}
+
+// PostBudget mocks base method.
+func (m *MockbudgetManager) PostBudget(ctx context.Context, budget models.BudgetJSON) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostBudget", ctx, budget)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostBudget indicates an expected call of PostBudget.
+func (mr *MockbudgetManagerMockRecorder) PostBudget(ctx, budget interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostBudget", reflect.TypeOf((*MockbudgetManager)(nil).PostBudget), ctx, budget)
+}
+
+// UpdateBudget mocks base method.
+func (m *MockbudgetManager) UpdateBudget(ctx context.Context, budget models.BudgetJSON, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBudget", ctx, budget, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBudget indicates an expected call of UpdateBudget.
+func (mr *MockbudgetManagerMockRecorder) UpdateBudget(ctx, budget, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBudget", reflect.TypeOf((*MockbudgetManager)(nil).UpdateBudget), ctx, budget, id)
+}
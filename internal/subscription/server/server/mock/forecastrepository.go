@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: forecast.go
+
+// Package mock_server is a generated GoMock package.
+package mock_server
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockforecastManager is a mock of forecastManager interface.
+type MockforecastManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockforecastManagerMockRecorder
+}
+
+// MockforecastManagerMockRecorder is the mock recorder for MockforecastManager.
+type MockforecastManagerMockRecorder struct {
+	mock *MockforecastManager
+}
+
+// NewMockforecastManager creates a new mock instance.
+func NewMockforecastManager(ctrl *gomock.Controller) *MockforecastManager {
+	mock := &MockforecastManager{ctrl: ctrl}
+	mock.recorder = &MockforecastManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockforecastManager) EXPECT() *MockforecastManagerMockRecorder {
+	return m.recorder
+}
+
+// GetForecastSubscriptionsByUserID mocks base method.
+func (m *MockforecastManager) GetForecastSubscriptionsByUserID(ctx context.Context, id uuid.UUID, from time.Time) ([]models.ForecastSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForecastSubscriptionsByUserID", ctx, id, from)
+	ret0, _ := ret[0].([]models.ForecastSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForecastSubscriptionsByUserID indicates an expected call of GetForecastSubscriptionsByUserID.
+func (mr *MockforecastManagerMockRecorder) GetForecastSubscriptionsByUserID(ctx, id, from interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForecastSubscriptionsByUserID", reflect.TypeOf((*MockforecastManager)(nil).GetForecastSubscriptionsByUserID), ctx, id, from)
+}
+
+// PostPriceChange mocks base method.
+func (m *MockforecastManager) PostPriceChange(ctx context.Context, subscriptionID int, change models.PriceChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostPriceChange", ctx, subscriptionID, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostPriceChange indicates an expected call of PostPriceChange.
+func (mr *MockforecastManagerMockRecorder) PostPriceChange(ctx, subscriptionID, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostPriceChange", reflect.TypeOf((*MockforecastManager)(nil).PostPriceChange), ctx, subscriptionID, change)
+}
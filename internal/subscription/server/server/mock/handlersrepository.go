@@ -37,24 +37,24 @@ func (m *MocksubscriptionManager) EXPECT() *MocksubscriptionManagerMockRecorder
 }
 
 // DeleteSubscription mocks base method.
-func (m *MocksubscriptionManager) DeleteSubscription(ctx context.Context, id int) error {
+func (m *MocksubscriptionManager) DeleteSubscription(ctx context.Context, userID uuid.UUID, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, userID, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSubscription indicates an expected call of DeleteSubscription.
-func (mr *MocksubscriptionManagerMockRecorder) DeleteSubscription(ctx, id interface{}) *gomock.Call {
+func (mr *MocksubscriptionManagerMockRecorder) DeleteSubscription(ctx, userID, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MocksubscriptionManager)(nil).DeleteSubscription), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MocksubscriptionManager)(nil).DeleteSubscription), ctx, userID, id)
 }
 
 // GetSubscriptionListByUserID mocks base method.
-func (m *MocksubscriptionManager) GetSubscriptionListByUserID(ctx context.Context, id uuid.UUID) ([]models.SubscriptionListDB, error) {
+func (m *MocksubscriptionManager) GetSubscriptionListByUserID(ctx context.Context, id uuid.UUID) ([]models.Subscription, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSubscriptionListByUserID", ctx, id)
-	ret0, _ := ret[0].([]models.SubscriptionListDB)
+	ret0, _ := ret[0].([]models.Subscription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -80,6 +80,21 @@ func (mr *MocksubscriptionManagerMockRecorder) GetTotalPeriodCostByDatesAndServi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalPeriodCostByDatesAndServiceName", reflect.TypeOf((*MocksubscriptionManager)(nil).GetTotalPeriodCostByDatesAndServiceName), ctx, subList)
 }
 
+// GetTotalPeriodCostsByUserIDs mocks base method.
+func (m *MocksubscriptionManager) GetTotalPeriodCostsByUserIDs(ctx context.Context, ids []uuid.UUID, subList models.SubscriptionListToCostJSON) (map[uuid.UUID]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalPeriodCostsByUserIDs", ctx, ids, subList)
+	ret0, _ := ret[0].(map[uuid.UUID]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalPeriodCostsByUserIDs indicates an expected call of GetTotalPeriodCostsByUserIDs.
+func (mr *MocksubscriptionManagerMockRecorder) GetTotalPeriodCostsByUserIDs(ctx, ids, subList interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalPeriodCostsByUserIDs", reflect.TypeOf((*MocksubscriptionManager)(nil).GetTotalPeriodCostsByUserIDs), ctx, ids, subList)
+}
+
 // PostSubscription mocks base method.
 func (m *MocksubscriptionManager) PostSubscription(ctx context.Context, sub models.SubscriptionListJSON) error {
 	m.ctrl.T.Helper()
@@ -95,15 +110,15 @@ func (mr *MocksubscriptionManagerMockRecorder) PostSubscription(ctx, sub interfa
 }
 
 // UpdateSubscription mocks base method.
-func (m *MocksubscriptionManager) UpdateSubscription(ctx context.Context, sub models.SubscriptionListJSON, id int) error {
+func (m *MocksubscriptionManager) UpdateSubscription(ctx context.Context, userID uuid.UUID, sub models.SubscriptionListJSON, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSubscription", ctx, sub, id)
+	ret := m.ctrl.Call(m, "UpdateSubscription", ctx, userID, sub, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateSubscription indicates an expected call of UpdateSubscription.
-func (mr *MocksubscriptionManagerMockRecorder) UpdateSubscription(ctx, sub, id interface{}) *gomock.Call {
+func (mr *MocksubscriptionManagerMockRecorder) UpdateSubscription(ctx, userID, sub, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscription", reflect.TypeOf((*MocksubscriptionManager)(nil).UpdateSubscription), ctx, sub, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscription", reflect.TypeOf((*MocksubscriptionManager)(nil).UpdateSubscription), ctx, userID, sub, id)
 }
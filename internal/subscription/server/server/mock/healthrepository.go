@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: health.go
+
+// Package mock_server is a generated GoMock package.
+package mock_server
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockhealthManager is a mock of healthManager interface.
+type MockhealthManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockhealthManagerMockRecorder
+}
+
+// MockhealthManagerMockRecorder is the mock recorder for MockhealthManager.
+type MockhealthManagerMockRecorder struct {
+	mock *MockhealthManager
+}
+
+// NewMockhealthManager creates a new mock instance.
+func NewMockhealthManager(ctrl *gomock.Controller) *MockhealthManager {
+	mock := &MockhealthManager{ctrl: ctrl}
+	mock.recorder = &MockhealthManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockhealthManager) EXPECT() *MockhealthManagerMockRecorder {
+	return m.recorder
+}
+
+// Ready mocks base method.
+func (m *MockhealthManager) Ready(ctx context.Context) (models.HealthJSON, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", ctx)
+	ret0, _ := ret[0].(models.HealthJSON)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockhealthManagerMockRecorder) Ready(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockhealthManager)(nil).Ready), ctx)
+}
@@ -0,0 +1,191 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: household.go
+
+// Package mock_server is a generated GoMock package.
+package mock_server
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockhouseholdManager is a mock of householdManager interface.
+type MockhouseholdManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockhouseholdManagerMockRecorder
+}
+
+// MockhouseholdManagerMockRecorder is the mock recorder for MockhouseholdManager.
+type MockhouseholdManagerMockRecorder struct {
+	mock *MockhouseholdManager
+}
+
+// NewMockhouseholdManager creates a new mock instance.
+func NewMockhouseholdManager(ctrl *gomock.Controller) *MockhouseholdManager {
+	mock := &MockhouseholdManager{ctrl: ctrl}
+	mock.recorder = &MockhouseholdManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockhouseholdManager) EXPECT() *MockhouseholdManagerMockRecorder {
+	return m.recorder
+}
+
+// AddHouseholdMember mocks base method.
+func (m *MockhouseholdManager) AddHouseholdMember(ctx context.Context, member models.HouseholdMemberJSON) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHouseholdMember", ctx, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddHouseholdMember indicates an expected call of AddHouseholdMember.
+func (mr *MockhouseholdManagerMockRecorder) AddHouseholdMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHouseholdMember", reflect.TypeOf((*MockhouseholdManager)(nil).AddHouseholdMember), ctx, member)
+}
+
+// DeleteHouseholdMember mocks base method.
+func (m *MockhouseholdManager) DeleteHouseholdMember(ctx context.Context, member models.HouseholdMemberJSON) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHouseholdMember", ctx, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHouseholdMember indicates an expected call of DeleteHouseholdMember.
+func (mr *MockhouseholdManagerMockRecorder) DeleteHouseholdMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHouseholdMember", reflect.TypeOf((*MockhouseholdManager)(nil).DeleteHouseholdMember), ctx, member)
+}
+
+// DeleteSharedSubscription mocks base method.
+func (m *MockhouseholdManager) DeleteSharedSubscription(ctx context.Context, subscriptionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSharedSubscription", ctx, subscriptionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSharedSubscription indicates an expected call of DeleteSharedSubscription.
+func (mr *MockhouseholdManagerMockRecorder) DeleteSharedSubscription(ctx, subscriptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSharedSubscription", reflect.TypeOf((*MockhouseholdManager)(nil).DeleteSharedSubscription), ctx, subscriptionID)
+}
+
+// GetHousehold mocks base method.
+func (m *MockhouseholdManager) GetHousehold(ctx context.Context, id int) (models.HouseholdJSON, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHousehold", ctx, id)
+	ret0, _ := ret[0].(models.HouseholdJSON)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHousehold indicates an expected call of GetHousehold.
+func (mr *MockhouseholdManagerMockRecorder) GetHousehold(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHousehold", reflect.TypeOf((*MockhouseholdManager)(nil).GetHousehold), ctx, id)
+}
+
+// GetHouseholdCharges mocks base method.
+func (m *MockhouseholdManager) GetHouseholdCharges(ctx context.Context, id int, from, to time.Time) ([]models.SharedCharge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouseholdCharges", ctx, id, from, to)
+	ret0, _ := ret[0].([]models.SharedCharge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouseholdCharges indicates an expected call of GetHouseholdCharges.
+func (mr *MockhouseholdManagerMockRecorder) GetHouseholdCharges(ctx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholdCharges", reflect.TypeOf((*MockhouseholdManager)(nil).GetHouseholdCharges), ctx, id, from, to)
+}
+
+// GetSharedSubscriptionsByHouseholdID mocks base method.
+func (m *MockhouseholdManager) GetSharedSubscriptionsByHouseholdID(ctx context.Context, id int) ([]models.SharedSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedSubscriptionsByHouseholdID", ctx, id)
+	ret0, _ := ret[0].([]models.SharedSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedSubscriptionsByHouseholdID indicates an expected call of GetSharedSubscriptionsByHouseholdID.
+func (mr *MockhouseholdManagerMockRecorder) GetSharedSubscriptionsByHouseholdID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedSubscriptionsByHouseholdID", reflect.TypeOf((*MockhouseholdManager)(nil).GetSharedSubscriptionsByHouseholdID), ctx, id)
+}
+
+// PostHousehold mocks base method.
+func (m *MockhouseholdManager) PostHousehold(ctx context.Context, household models.HouseholdJSON) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostHousehold", ctx, household)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostHousehold indicates an expected call of PostHousehold.
+func (mr *MockhouseholdManagerMockRecorder) PostHousehold(ctx, household interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostHousehold", reflect.TypeOf((*MockhouseholdManager)(nil).PostHousehold), ctx, household)
+}
+
+// PutSharedSubscription mocks base method.
+func (m *MockhouseholdManager) PutSharedSubscription(ctx context.Context, shared models.SharedSubscriptionJSON) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutSharedSubscription", ctx, shared)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutSharedSubscription indicates an expected call of PutSharedSubscription.
+func (mr *MockhouseholdManagerMockRecorder) PutSharedSubscription(ctx, shared interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSharedSubscription", reflect.TypeOf((*MockhouseholdManager)(nil).PutSharedSubscription), ctx, shared)
+}
+
+// MocksubscriptionGetter is a mock of subscriptionGetter interface.
+type MocksubscriptionGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MocksubscriptionGetterMockRecorder
+}
+
+// MocksubscriptionGetterMockRecorder is the mock recorder for MocksubscriptionGetter.
+type MocksubscriptionGetterMockRecorder struct {
+	mock *MocksubscriptionGetter
+}
+
+// NewMocksubscriptionGetter creates a new mock instance.
+func NewMocksubscriptionGetter(ctrl *gomock.Controller) *MocksubscriptionGetter {
+	mock := &MocksubscriptionGetter{ctrl: ctrl}
+	mock.recorder = &MocksubscriptionGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksubscriptionGetter) EXPECT() *MocksubscriptionGetterMockRecorder {
+	return m.recorder
+}
+
+// GetSubscription mocks base method.
+func (m *MocksubscriptionGetter) GetSubscription(ctx context.Context, id int) (models.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", ctx, id)
+	ret0, _ := ret[0].(models.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MocksubscriptionGetterMockRecorder) GetSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MocksubscriptionGetter)(nil).GetSubscription), ctx, id)
+}
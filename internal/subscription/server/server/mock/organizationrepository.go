@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: organization.go
+
+// Package mock_server is a generated GoMock package.
+package mock_server
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockorganizationManager is a mock of organizationManager interface.
+type MockorganizationManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockorganizationManagerMockRecorder
+}
+
+// MockorganizationManagerMockRecorder is the mock recorder for MockorganizationManager.
+type MockorganizationManagerMockRecorder struct {
+	mock *MockorganizationManager
+}
+
+// NewMockorganizationManager creates a new mock instance.
+func NewMockorganizationManager(ctrl *gomock.Controller) *MockorganizationManager {
+	mock := &MockorganizationManager{ctrl: ctrl}
+	mock.recorder = &MockorganizationManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockorganizationManager) EXPECT() *MockorganizationManagerMockRecorder {
+	return m.recorder
+}
+
+// DeleteOrganizationMember mocks base method.
+func (m *MockorganizationManager) DeleteOrganizationMember(ctx context.Context, member models.OrganizationMemberJSON) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrganizationMember", ctx, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrganizationMember indicates an expected call of DeleteOrganizationMember.
+func (mr *MockorganizationManagerMockRecorder) DeleteOrganizationMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganizationMember", reflect.TypeOf((*MockorganizationManager)(nil).DeleteOrganizationMember), ctx, member)
+}
+
+// GetOrganization mocks base method.
+func (m *MockorganizationManager) GetOrganization(ctx context.Context, id int) (models.OrganizationJSON, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganization", ctx, id)
+	ret0, _ := ret[0].(models.OrganizationJSON)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganization indicates an expected call of GetOrganization.
+func (mr *MockorganizationManagerMockRecorder) GetOrganization(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganization", reflect.TypeOf((*MockorganizationManager)(nil).GetOrganization), ctx, id)
+}
+
+// GetOrganizationRole mocks base method.
+func (m *MockorganizationManager) GetOrganizationRole(ctx context.Context, organizationID int, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationRole", ctx, organizationID, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationRole indicates an expected call of GetOrganizationRole.
+func (mr *MockorganizationManagerMockRecorder) GetOrganizationRole(ctx, organizationID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationRole", reflect.TypeOf((*MockorganizationManager)(nil).GetOrganizationRole), ctx, organizationID, userID)
+}
+
+// GetOrganizationSpend mocks base method.
+func (m *MockorganizationManager) GetOrganizationSpend(ctx context.Context, filter models.OrganizationSpendFilter) ([]models.OrganizationSpendDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationSpend", ctx, filter)
+	ret0, _ := ret[0].([]models.OrganizationSpendDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationSpend indicates an expected call of GetOrganizationSpend.
+func (mr *MockorganizationManagerMockRecorder) GetOrganizationSpend(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationSpend", reflect.TypeOf((*MockorganizationManager)(nil).GetOrganizationSpend), ctx, filter)
+}
+
+// GetSeatChanges mocks base method.
+func (m *MockorganizationManager) GetSeatChanges(ctx context.Context, subscriptionID int) ([]models.SeatChangeJSON, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeatChanges", ctx, subscriptionID)
+	ret0, _ := ret[0].([]models.SeatChangeJSON)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeatChanges indicates an expected call of GetSeatChanges.
+func (mr *MockorganizationManagerMockRecorder) GetSeatChanges(ctx, subscriptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeatChanges", reflect.TypeOf((*MockorganizationManager)(nil).GetSeatChanges), ctx, subscriptionID)
+}
+
+// PostOrganization mocks base method.
+func (m *MockorganizationManager) PostOrganization(ctx context.Context, org models.OrganizationJSON) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostOrganization", ctx, org)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostOrganization indicates an expected call of PostOrganization.
+func (mr *MockorganizationManagerMockRecorder) PostOrganization(ctx, org interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostOrganization", reflect.TypeOf((*MockorganizationManager)(nil).PostOrganization), ctx, org)
+}
+
+// PutOrganizationMember mocks base method.
+func (m *MockorganizationManager) PutOrganizationMember(ctx context.Context, member models.OrganizationMemberJSON) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutOrganizationMember", ctx, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutOrganizationMember indicates an expected call of PutOrganizationMember.
+func (mr *MockorganizationManagerMockRecorder) PutOrganizationMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutOrganizationMember", reflect.TypeOf((*MockorganizationManager)(nil).PutOrganizationMember), ctx, member)
+}
@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: recommendation.go
+
+// Package mock_server is a generated GoMock package.
+package mock_server
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockrecommendationManager is a mock of recommendationManager interface.
+type MockrecommendationManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockrecommendationManagerMockRecorder
+}
+
+// MockrecommendationManagerMockRecorder is the mock recorder for MockrecommendationManager.
+type MockrecommendationManagerMockRecorder struct {
+	mock *MockrecommendationManager
+}
+
+// NewMockrecommendationManager creates a new mock instance.
+func NewMockrecommendationManager(ctrl *gomock.Controller) *MockrecommendationManager {
+	mock := &MockrecommendationManager{ctrl: ctrl}
+	mock.recorder = &MockrecommendationManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockrecommendationManager) EXPECT() *MockrecommendationManagerMockRecorder {
+	return m.recorder
+}
+
+// GetActiveSubscriptionsByUserID mocks base method.
+func (m *MockrecommendationManager) GetActiveSubscriptionsByUserID(ctx context.Context, id uuid.UUID, month time.Time) ([]models.ActiveSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSubscriptionsByUserID", ctx, id, month)
+	ret0, _ := ret[0].([]models.ActiveSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSubscriptionsByUserID indicates an expected call of GetActiveSubscriptionsByUserID.
+func (mr *MockrecommendationManagerMockRecorder) GetActiveSubscriptionsByUserID(ctx, id, month interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSubscriptionsByUserID", reflect.TypeOf((*MockrecommendationManager)(nil).GetActiveSubscriptionsByUserID), ctx, id, month)
+}
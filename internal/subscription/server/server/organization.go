@@ -0,0 +1,409 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/organization"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+//go:generate mockgen -source=organization.go -destination=mock/organizationrepository.go
+type organizationManager interface {
+	PostOrganization(ctx context.Context, org models.OrganizationJSON) (int, error)
+	GetOrganization(ctx context.Context, id int) (models.OrganizationJSON, error)
+	PutOrganizationMember(ctx context.Context, member models.OrganizationMemberJSON) error
+	DeleteOrganizationMember(ctx context.Context, member models.OrganizationMemberJSON) error
+	GetOrganizationRole(ctx context.Context, organizationID int, userID uuid.UUID) (string, error)
+	GetSeatChanges(ctx context.Context, subscriptionID int) ([]models.SeatChangeJSON, error)
+	GetOrganizationSpend(ctx context.Context, filter models.OrganizationSpendFilter) ([]models.OrganizationSpendDB, error)
+}
+
+// errNotMember is returned by role when the requesting user isn't a member of the organization.
+var errNotMember = errors.New("not a member of the organization")
+
+type organizationController struct {
+	manager       organizationManager
+	subscriptions subscriptionGetter
+	logger        *slog.Logger
+}
+
+func NewOrganizationHandler(manager organizationManager, subscriptions subscriptionGetter, log *slog.Logger) *organizationController {
+	return &organizationController{manager, subscriptions, log}
+}
+
+// PostOrganization godoc
+// @Summary Создать организацию
+// @Description Создает организацию для командных подписок. Пользователь admin_id становится ее администратором
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param organization body models.OrganizationJSON true "Название и администратор"
+// @Success 200 {object} models.OrganizationJSON
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 503 {object} map[string]string "Сервис временно недоступен, повторите запрос"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/organization [post]
+func (ctr organizationController) PostOrganization(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for POST Organization")
+
+	var res models.OrganizationJSON
+
+	if err := echo.Bind(&res); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	if err := organization.ValidateOrganization(res); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	id, err := ctr.manager.PostOrganization(echo.Request().Context(), res)
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	res.ID = id
+	res.Members = []models.OrganizationMemberJSON{{OrganizationID: id, UserID: res.AdminID, Role: models.RoleAdmin}}
+
+	return echo.JSON(http.StatusOK, res)
+}
+
+// GetOrganization godoc
+// @Summary Получить организацию
+// @Description Возвращает организацию с ее участниками, их ролями и отделами. Доступно участникам организации
+// @Tags organizations
+// @Produce json
+// @Param id query int true "ID организации"
+// @Param userId header string true "userId из cookie, пользователь, выполняющий запрос"
+// @Success 200 {object} models.OrganizationJSON
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Пользователь не состоит в организации"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/organization [get]
+func (ctr organizationController) GetOrganization(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Organization")
+
+	id, err := strconv.Atoi(echo.QueryParam("id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	ctx := echo.Request().Context()
+
+	_, err = ctr.role(ctx, id, requester)
+
+	var res models.OrganizationJSON
+	if err == nil {
+		res, err = ctr.manager.GetOrganization(ctx, id)
+	}
+
+	if err != nil {
+		return ctr.fail(echo, err)
+	}
+
+	return echo.JSON(http.StatusOK, res)
+}
+
+// PutOrganizationMember godoc
+// @Summary Добавить или изменить участника организации
+// @Description Добавляет участника или меняет его роль (admin или member) и отдел. Доступно администраторам
+// @Description организации, свою роль администратор изменить не может
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param member body models.OrganizationMemberJSON true "Организация, пользователь, роль и отдел"
+// @Param userId header string true "userId из cookie, администратор, выполняющий запрос"
+// @Success 200 {string} string "Участник успешно сохранен"
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Пользователь не является администратором организации"
+// @Failure 422 {object} map[string]string "Нарушено ограничение, в constraint и column его имя и столбец"
+// @Failure 503 {object} map[string]string "Сервис временно недоступен, повторите запрос"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/organization/member [put]
+func (ctr organizationController) PutOrganizationMember(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for PUT Organization Member")
+
+	var member models.OrganizationMemberJSON
+
+	if err := echo.Bind(&member); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	if err := organization.ValidateMember(&member); err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	// The organization always keeps an admin: the admins can't demote themselves.
+	if member.UserID == requester && member.Role != models.RoleAdmin {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	ctx := echo.Request().Context()
+
+	err = ctr.admin(ctx, member.OrganizationID, requester)
+	if err == nil {
+		err = ctr.manager.PutOrganizationMember(ctx, member)
+	}
+
+	if err != nil {
+		return ctr.fail(echo, err)
+	}
+
+	return echo.JSON(http.StatusOK, map[string]string{"result": "Участник успешно сохранен"})
+}
+
+// DeleteOrganizationMember godoc
+// @Summary Удалить участника организации
+// @Description Удаляет участника из организации. Доступно администраторам организации, удалить себя администратор не может.
+// @Description Подписки участника остаются у организации и относятся к отделу «unassigned», если у них нет своего отдела
+// @Tags organizations
+// @Produce json
+// @Param organization_id query int true "ID организации"
+// @Param user_id query string true "ID пользователя"
+// @Param userId header string true "userId из cookie, администратор, выполняющий запрос"
+// @Success 200 {string} string "Участник успешно удален"
+// @Failure 400 {string} string "Некорректные параметры или участник не найден"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Пользователь не является администратором организации"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/organization/member [delete]
+func (ctr organizationController) DeleteOrganizationMember(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Delete Organization Member")
+
+	organizationID, err := strconv.Atoi(echo.QueryParam("organization_id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректные параметры или участник не найден"})
+	}
+
+	userID, err := uuid.Parse(echo.QueryParam("user_id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректные параметры или участник не найден"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	if requester == userID {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректные параметры или участник не найден"})
+	}
+
+	ctx := echo.Request().Context()
+
+	err = ctr.admin(ctx, organizationID, requester)
+	if err == nil {
+		err = ctr.manager.DeleteOrganizationMember(ctx, models.OrganizationMemberJSON{
+			OrganizationID: organizationID,
+			UserID:         userID,
+		})
+	}
+
+	if errors.Is(err, models.ErrNotFound) {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Некорректные параметры или участник не найден"})
+	}
+
+	if err != nil {
+		return ctr.fail(echo, err)
+	}
+
+	return echo.JSON(http.StatusOK, map[string]string{"result": "Участник успешно удален"})
+}
+
+// GetSeatHistory godoc
+// @Summary Получить историю мест подписки
+// @Description Возвращает изменения числа мест подписки от старых к новым. Доступно владельцу подписки
+// @Description и администраторам организации, которой она принадлежит
+// @Tags organizations
+// @Produce json
+// @Param subscription_id query int true "ID подписки"
+// @Param userId header string true "userId из cookie, пользователь, выполняющий запрос"
+// @Success 200 {array} models.SeatChangeJSON
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Подписка принадлежит другому пользователю"
+// @Failure 404 {string} string "Подписка не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/organization/seat-history [get]
+func (ctr organizationController) GetSeatHistory(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Seat History")
+
+	id, err := strconv.Atoi(echo.QueryParam("subscription_id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	ctx := echo.Request().Context()
+
+	sub, err := ctr.subscriptions.GetSubscription(ctx, id)
+	if err == nil && sub.UserID != requester {
+		err = models.ErrForbidden
+
+		if sub.OrganizationID != nil {
+			err = ctr.admin(ctx, *sub.OrganizationID, requester)
+		}
+	}
+
+	var changes []models.SeatChangeJSON
+	if err == nil {
+		changes, err = ctr.manager.GetSeatChanges(ctx, id)
+	}
+
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			return echo.JSON(http.StatusNotFound, map[string]string{"result": "Подписка не найдена"})
+		case errors.Is(err, models.ErrForbidden), errors.Is(err, errNotMember):
+			return echo.JSON(http.StatusForbidden, map[string]string{"result": "Подписка принадлежит другому пользователю"})
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	if changes == nil {
+		changes = []models.SeatChangeJSON{}
+	}
+
+	return echo.JSON(http.StatusOK, changes)
+}
+
+// GetOrganizationSpend godoc
+// @Summary Получить расходы организации
+// @Description Суммирует списания подписок организации за период по отделам, сервисам и валютам. Стоимость
+// @Description списания — цена места, умноженная на число мест в том месяце. Подписка относится к своему отделу,
+// @Description а без него — к отделу своего владельца. Администратор видит расходы всей организации (scope=organization),
+// @Description участник — только своих подписок (scope=member)
+// @Tags organizations
+// @Produce json
+// @Param id query int true "ID организации"
+// @Param start_date query string true "Начало периода в формате MM-YYYY"
+// @Param end_date query string true "Конец периода в формате MM-YYYY"
+// @Param userId header string true "userId из cookie, пользователь, выполняющий запрос"
+// @Success 200 {object} models.OrganizationSpendJSON
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 401 {string} string "Не передан userId в cookie"
+// @Failure 403 {string} string "Пользователь не состоит в организации"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/organization/spend [get]
+func (ctr organizationController) GetOrganizationSpend(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Organization Spend")
+
+	id, err := strconv.Atoi(echo.QueryParam("id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	from, fromErr := time.Parse(models.MonthLayout, echo.QueryParam("start_date"))
+	to, toErr := time.Parse(models.MonthLayout, echo.QueryParam("end_date"))
+
+	if fromErr != nil || toErr != nil || to.Before(from) {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	requester, err := callerID(echo)
+	if err != nil {
+		return echo.JSON(http.StatusUnauthorized, map[string]string{"result": "Не передан userId в cookie"})
+	}
+
+	ctx := echo.Request().Context()
+
+	role, err := ctr.role(ctx, id, requester)
+	if err != nil {
+		return ctr.fail(echo, err)
+	}
+
+	filter := models.OrganizationSpendFilter{OrganizationID: id, StartDate: from, EndDate: to}
+	scope := models.SpendScopeOrganization
+
+	if role != models.RoleAdmin {
+		filter.UserID = &requester
+		scope = models.SpendScopeMember
+	}
+
+	rows, err := ctr.manager.GetOrganizationSpend(ctx, filter)
+	if err != nil {
+		return ctr.fail(echo, err)
+	}
+
+	totals, departments := organization.Spend(rows)
+
+	return echo.JSON(http.StatusOK, models.OrganizationSpendJSON{
+		OrganizationID: id,
+		Scope:          scope,
+		StartDate:      from.Format(models.MonthLayout),
+		EndDate:        to.Format(models.MonthLayout),
+		Totals:         totals,
+		Departments:    departments,
+	})
+}
+
+// role returns the role of the user in the organization, errNotMember when they have none.
+func (ctr organizationController) role(ctx context.Context, organizationID int, userID uuid.UUID) (string, error) {
+	role, err := ctr.manager.GetOrganizationRole(ctx, organizationID, userID)
+	if errors.Is(err, models.ErrNotFound) {
+		return role, errNotMember
+	}
+
+	return role, err
+}
+
+// admin checks the user is an admin of the organization, ErrForbidden when they are a regular member.
+func (ctr organizationController) admin(ctx context.Context, organizationID int, userID uuid.UUID) error {
+	role, err := ctr.role(ctx, organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	if role != models.RoleAdmin {
+		return models.ErrForbidden
+	}
+
+	return nil
+}
+
+// fail writes the response to an error of the organization routes.
+func (ctr organizationController) fail(echo echo.Context, err error) error {
+	if handled, err := storageError(echo, err); handled {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, errNotMember):
+		return echo.JSON(http.StatusForbidden, map[string]string{"result": "Пользователь не состоит в организации"})
+	case errors.Is(err, models.ErrForbidden):
+		return echo.JSON(http.StatusForbidden, map[string]string{"result": "Пользователь не является администратором организации"})
+	}
+
+	return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+}
@@ -0,0 +1,306 @@
+package server_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+)
+
+var (
+	organizationAdmin  = uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")
+	organizationMember = uuid.MustParse("7a3c3d2b-8f5e-4b7a-9c1d-2e6f8a9b0c1d")
+)
+
+func TestGetOrganizationSpend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	january := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []models.OrganizationSpendDB{
+		{Department: "Разработка", ServiceName: "Slack", Currency: "RUB", Seats: 8, Total: 8000},
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		userID     string
+		mockSetup  func(m *mock_server.MockorganizationManager)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "Admin_WholeOrganization",
+			url:    "/?id=1&start_date=01-2025&end_date=01-2025",
+			userID: organizationAdmin.String(),
+			mockSetup: func(m *mock_server.MockorganizationManager) {
+				m.EXPECT().GetOrganizationRole(gomock.Any(), 1, organizationAdmin).Return(models.RoleAdmin, nil)
+				m.EXPECT().GetOrganizationSpend(gomock.Any(), models.OrganizationSpendFilter{
+					OrganizationID: 1, StartDate: january, EndDate: january,
+				}).Return(rows, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"organization_id":1,"scope":"organization","start_date":"01-2025","end_date":"01-2025",` +
+				`"totals":[{"currency":"RUB","total":8000}],"departments":[{"department":"Разработка",` +
+				`"totals":[{"currency":"RUB","total":8000}],` +
+				`"services":[{"service_name":"Slack","currency":"RUB","seats":8,"total":8000}]}]}`,
+		},
+		{
+			name:   "Member_OwnSubscriptions",
+			url:    "/?id=1&start_date=01-2025&end_date=01-2025",
+			userID: organizationMember.String(),
+			mockSetup: func(m *mock_server.MockorganizationManager) {
+				m.EXPECT().GetOrganizationRole(gomock.Any(), 1, organizationMember).Return(models.RoleMember, nil)
+				m.EXPECT().GetOrganizationSpend(gomock.Any(), models.OrganizationSpendFilter{
+					OrganizationID: 1, UserID: &organizationMember, StartDate: january, EndDate: january,
+				}).Return(nil, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"organization_id":1,"scope":"member","start_date":"01-2025","end_date":"01-2025",` +
+				`"totals":[],"departments":[]}`,
+		},
+		{
+			name:   "Forbidden_NotAMember",
+			url:    "/?id=1&start_date=01-2025&end_date=01-2025",
+			userID: uuid.NewString(),
+			mockSetup: func(m *mock_server.MockorganizationManager) {
+				m.EXPECT().GetOrganizationRole(gomock.Any(), 1, gomock.Any()).Return("", models.ErrNotFound)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Unauthorized_NoCookie",
+			url:        "/?id=1&start_date=01-2025&end_date=01-2025",
+			mockSetup:  func(m *mock_server.MockorganizationManager) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "BadRequest_ReversedPeriod",
+			url:        "/?id=1&start_date=02-2025&end_date=01-2025",
+			userID:     organizationAdmin.String(),
+			mockSetup:  func(m *mock_server.MockorganizationManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "InternalServerError",
+			url:    "/?id=1&start_date=01-2025&end_date=01-2025",
+			userID: organizationAdmin.String(),
+			mockSetup: func(m *mock_server.MockorganizationManager) {
+				m.EXPECT().GetOrganizationRole(gomock.Any(), 1, organizationAdmin).Return(models.RoleAdmin, nil)
+				m.EXPECT().GetOrganizationSpend(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockorganizationManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.userID != "" {
+				req.AddCookie(&http.Cookie{Name: "userId", Value: tt.userID})
+			}
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewOrganizationHandler(mockManager, nil, logger)
+			if err := handler.GetOrganizationSpend(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestPutOrganizationMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	tests := []struct {
+		name       string
+		body       string
+		userID     uuid.UUID
+		mockSetup  func(m *mock_server.MockorganizationManager)
+		wantStatus int
+	}{
+		{
+			name:   "Success_DefaultRole",
+			body:   `{"organization_id":1,"user_id":"` + organizationMember.String() + `","department":"Разработка"}`,
+			userID: organizationAdmin,
+			mockSetup: func(m *mock_server.MockorganizationManager) {
+				m.EXPECT().GetOrganizationRole(gomock.Any(), 1, organizationAdmin).Return(models.RoleAdmin, nil)
+				m.EXPECT().PutOrganizationMember(gomock.Any(), models.OrganizationMemberJSON{
+					OrganizationID: 1, UserID: organizationMember, Role: models.RoleMember, Department: "Разработка",
+				}).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Forbidden_NotAnAdmin",
+			body:   `{"organization_id":1,"user_id":"` + organizationMember.String() + `","role":"admin"}`,
+			userID: organizationMember,
+			mockSetup: func(m *mock_server.MockorganizationManager) {
+				m.EXPECT().GetOrganizationRole(gomock.Any(), 1, organizationMember).Return(models.RoleMember, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "BadRequest_AdminDemotesThemselves",
+			body:       `{"organization_id":1,"user_id":"` + organizationAdmin.String() + `","role":"member"}`,
+			userID:     organizationAdmin,
+			mockSetup:  func(m *mock_server.MockorganizationManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "BadRequest_UnknownRole",
+			body:       `{"organization_id":1,"user_id":"` + organizationMember.String() + `","role":"owner"}`,
+			userID:     organizationAdmin,
+			mockSetup:  func(m *mock_server.MockorganizationManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockorganizationManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.AddCookie(&http.Cookie{Name: "userId", Value: tt.userID.String()})
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewOrganizationHandler(mockManager, nil, logger)
+			if err := handler.PutOrganizationMember(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestGetSeatHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	organizationID := 1
+	sub := models.Subscription{ID: 5, UserID: organizationMember, OrganizationID: &organizationID}
+	changedAt := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		userID     uuid.UUID
+		mockSetup  func(m *mock_server.MockorganizationManager, s *mock_server.MocksubscriptionGetter)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "Owner",
+			userID: organizationMember,
+			mockSetup: func(m *mock_server.MockorganizationManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 5).Return(sub, nil)
+				m.EXPECT().GetSeatChanges(gomock.Any(), 5).Return([]models.SeatChangeJSON{
+					{SubscriptionID: 5, PreviousSeats: 5, Seats: 8, ChangedAt: changedAt},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"subscription_id":5,"previous_seats":5,"seats":8,"changed_at":"2025-09-01T10:00:00Z"}]`,
+		},
+		{
+			name:   "OrganizationAdmin",
+			userID: organizationAdmin,
+			mockSetup: func(m *mock_server.MockorganizationManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 5).Return(sub, nil)
+				m.EXPECT().GetOrganizationRole(gomock.Any(), 1, organizationAdmin).Return(models.RoleAdmin, nil)
+				m.EXPECT().GetSeatChanges(gomock.Any(), 5).Return(nil, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `[]`,
+		},
+		{
+			name:   "Forbidden_OtherMember",
+			userID: uuid.MustParse("33333333-3333-3333-3333-333333333333"),
+			mockSetup: func(m *mock_server.MockorganizationManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 5).Return(sub, nil)
+				m.EXPECT().GetOrganizationRole(gomock.Any(), 1, gomock.Any()).Return(models.RoleMember, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "NotFound",
+			userID: organizationMember,
+			mockSetup: func(m *mock_server.MockorganizationManager, s *mock_server.MocksubscriptionGetter) {
+				s.EXPECT().GetSubscription(gomock.Any(), 5).Return(models.Subscription{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockorganizationManager(ctrl)
+			mockSubscriptions := mock_server.NewMocksubscriptionGetter(ctrl)
+			tt.mockSetup(mockManager, mockSubscriptions)
+
+			req := httptest.NewRequest(http.MethodGet, "/?subscription_id=5", nil)
+			req.AddCookie(&http.Cookie{Name: "userId", Value: tt.userID.String()})
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewOrganizationHandler(mockManager, mockSubscriptions, logger)
+			if err := handler.GetSeatHistory(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/recommendation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+//go:generate mockgen -source=recommendation.go -destination=mock/recommendationrepository.go
+type recommendationManager interface {
+	GetActiveSubscriptionsByUserID(ctx context.Context, id uuid.UUID, month time.Time) ([]models.ActiveSubscription, error)
+}
+
+type recommendationController struct {
+	manager recommendationManager
+	logger  *slog.Logger
+}
+
+func NewRecommendationHandler(manager recommendationManager, log *slog.Logger) *recommendationController {
+	return &recommendationController{manager, log}
+}
+
+// GetRecommendations godoc
+// @Summary Получить рекомендации по экономии
+// @Description Находит пересекающиеся сервисы одной категории, точные и похожие дубликаты подписок
+// @Description и подписки дороже цены каталога, для каждой находки оценивает годовую экономию
+// @Tags recommendations
+// @Produce json
+// @Param user_id query string true "ID пользователя"
+// @Success 200 {object} models.RecommendationsJSON
+// @Failure 400 {string} string "Ошибка в параметрах запроса"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /subscription/recommendations [get]
+func (ctr recommendationController) GetRecommendations(echo echo.Context) error {
+	ctr.logger.Debug("Get Request for Subscription Recommendations")
+
+	userID, err := uuid.Parse(echo.QueryParam("user_id"))
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, map[string]string{"result": "Ошибка в параметрах запроса"})
+	}
+
+	subs, err := ctr.manager.GetActiveSubscriptionsByUserID(echo.Request().Context(), userID, time.Now())
+	if err != nil {
+		if handled, err := storageError(echo, err); handled {
+			return err
+		}
+
+		return echo.JSON(http.StatusInternalServerError, map[string]string{"result": "Внутренняя ошибка сервера"})
+	}
+
+	findings := recommendation.Detect(subs)
+	res := models.RecommendationsJSON{
+		UserID:            userID,
+		Findings:          findings,
+		TotalAnnualSaving: recommendation.TotalAnnualSaving(findings),
+	}
+
+	return echo.JSON(http.StatusOK, res)
+}
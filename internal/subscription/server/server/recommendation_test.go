@@ -0,0 +1,105 @@
+package server_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/server/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+)
+
+func TestGetRecommendations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	e := echo.New()
+
+	userID := uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")
+	catalogPrice := 149
+
+	tests := []struct {
+		name       string
+		url        string
+		mockSetup  func(m *mock_server.MockrecommendationManager)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "Success_SharedSubscriptionCountedOnce",
+			url:  "/?user_id=" + userID.String(),
+			mockSetup: func(m *mock_server.MockrecommendationManager) {
+				m.EXPECT().GetActiveSubscriptionsByUserID(gomock.Any(), userID, gomock.Any()).
+					Return([]models.ActiveSubscription{
+						{ID: 1, ServiceName: "iCloud", Price: 149, Currency: "RUB", CatalogPrice: &catalogPrice},
+						{ID: 2, ServiceName: "iCloud", Price: 199, Currency: "RUB", CatalogPrice: &catalogPrice},
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","total_annual_saving":2388,"findings":[` +
+				`{"type":"near_duplicate","subscription_ids":[1,2],"service_names":["iCloud","iCloud"],` +
+				`"description":"Похожие подписки, вероятно, на один и тот же сервис","annual_saving":2388},` +
+				`{"type":"above_catalog_price","subscription_ids":[2],"service_names":["iCloud"],` +
+				`"description":"Цена 199 выше цены каталога 149, проверьте тариф","annual_saving":600}]}`,
+		},
+		{
+			name: "Success_NoSubscriptions",
+			url:  "/?user_id=" + userID.String(),
+			mockSetup: func(m *mock_server.MockrecommendationManager) {
+				m.EXPECT().GetActiveSubscriptionsByUserID(gomock.Any(), userID, gomock.Any()).Return(nil, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","total_annual_saving":0,"findings":[]}`,
+		},
+		{
+			name:       "BadRequest_InvalidUserID",
+			url:        "/?user_id=invalid",
+			mockSetup:  func(m *mock_server.MockrecommendationManager) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "InternalServerError",
+			url:  "/?user_id=" + userID.String(),
+			mockSetup: func(m *mock_server.MockrecommendationManager) {
+				m.EXPECT().GetActiveSubscriptionsByUserID(gomock.Any(), userID, gomock.Any()).
+					Return(nil, errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockManager := mock_server.NewMockrecommendationManager(ctrl)
+			tt.mockSetup(mockManager)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := server.NewRecommendationHandler(mockManager, logger)
+			if err := handler.GetRecommendations(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
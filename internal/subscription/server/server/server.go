@@ -5,26 +5,78 @@ import (
 	"errors"
 	"fmt"
 	_ "github.com/Ostmind/subscriptionservice/docs"
-	"github.com/Ostmind/subscriptionservice/internal/storage/postgres"
+	"github.com/Ostmind/subscriptionservice/internal/storage"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/config"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/health"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/metrics"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/ratelimit"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/server/gql"
 	"github.com/Ostmind/subscriptionservice/internal/subscription/server/middleware"
+	"github.com/prometheus/client_golang/prometheus"
 	echoSwagger "github.com/swaggo/echo-swagger"
+	"go.opentelemetry.io/otel"
 	"log/slog"
+	"net"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 )
 
 type Server struct {
-	server  *echo.Echo
-	logger  *slog.Logger
-	storage *postgres.Storage
+	server     *echo.Echo
+	httpServer *http.Server
+	admin      *http.Server
+	redirect   *http.Server
+	logger     *slog.Logger
+	listeners  []net.Listener
 }
 
-func New(db *postgres.Storage, logger *slog.Logger) *Server {
+// New builds the HTTP API over the subscriptions service and db. The routes of the other features are
+// registered when db implements their repositories. collectors are exported next to the server metrics.
+func New(subscriptions subscriptionManager, db storage.Repository, logger *slog.Logger, cfg config.ServerConfig,
+	rateLimit config.RateLimitConfig, checker *health.Checker, collectors ...prometheus.Collector,
+) (*Server, error) {
 	server := echo.New()
+	server.IPExtractor = middleware.IPExtractor(cfg.TrustedProxies)
 
+	registry := metrics.NewRegistry()
+	registry.MustRegister(collectors...)
+
+	if repo, ok := db.(storage.MetricsRepository); ok {
+		registry.MustRegister(metrics.NewBusinessCollector(repo, logger))
+	}
+
+	server.Use(middleware.Tracing(otel.GetTracerProvider(), otel.GetTextMapPropagator()))
 	server.Use(middleware.LogRequest(logger))
-	subController := NewSubscriptionHandler(db, logger)
+	server.Use(middleware.Metrics(registry))
+
+	if cfg.QueryTimeout > 0 {
+		server.Use(middleware.QueryTimeout(cfg.QueryTimeout))
+	}
+
+	if cfg.MaxBodyBytes > 0 {
+		server.Use(middleware.BodyLimit(cfg.MaxBodyBytes))
+	}
+
+	if rateLimit.Enabled {
+		repo, ok := db.(storage.RateLimitRepository)
+		if !ok && rateLimit.Backend == ratelimit.BackendPostgres {
+			return nil, fmt.Errorf("error creating rate limiter %w", config.ErrRateLimitNeedsPostgres)
+		}
+
+		limiter, err := ratelimit.New(rateLimit.Backend, repo, logger)
+		if err != nil {
+			return nil, fmt.Errorf("error creating rate limiter %w", err)
+		}
+
+		server.Use(middleware.RateLimit(limiter, rateLimit, logger))
+	}
+
+	healthController := NewHealthHandler(checker, logger)
+	server.GET("healthz", healthController.GetHealthz)
+	server.GET("readyz", healthController.GetReadyz)
+
+	subController := NewSubscriptionHandler(subscriptions, logger)
 
 	server.POST("subscription", subController.PostSubscription)
 	server.GET("subscription/users", subController.GetSubscriptionListByUserID)
@@ -33,36 +85,196 @@ func New(db *postgres.Storage, logger *slog.Logger) *Server {
 
 	server.GET("subscription/total-price", subController.GetTotalPeriodCostByDatesAndServiceName)
 
+	unsupported := func(feature string) {
+		logger.Warn("Storage doesn't support the feature, its routes are disabled", "feature", feature)
+	}
+
+	if repo, ok := db.(storage.AnalyticsRepository); ok {
+		analyticsController := NewAnalyticsHandler(repo, logger)
+		server.GET("subscription/analytics", analyticsController.GetSpendAnalytics)
+	} else {
+		unsupported("analytics")
+	}
+
+	if repo, ok := db.(storage.ForecastRepository); ok {
+		forecastController := NewForecastHandler(repo, db, logger)
+		server.POST("subscription/forecast", forecastController.GetForecast)
+		server.POST("subscription/price-change", forecastController.PostPriceChange)
+	} else {
+		unsupported("forecast")
+	}
+
+	if repo, ok := db.(storage.BudgetRepository); ok {
+		budgetController := NewBudgetHandler(repo, logger)
+		server.POST("subscription/budget", budgetController.PostBudget)
+		server.GET("subscription/budgets", budgetController.GetBudgetsByUserID)
+		server.PUT("subscription/budget", budgetController.UpdateBudget)
+		server.DELETE("subscription/budget", budgetController.DeleteBudget)
+	} else {
+		unsupported("budgets")
+	}
+
+	if repo, ok := db.(storage.HouseholdRepository); ok {
+		householdController := NewHouseholdHandler(repo, db, logger)
+		server.POST("subscription/household", householdController.PostHousehold)
+		server.GET("subscription/household", householdController.GetHousehold)
+		server.POST("subscription/household/member", householdController.AddHouseholdMember)
+		server.DELETE("subscription/household/member", householdController.DeleteHouseholdMember)
+		server.PUT("subscription/household/share", householdController.PutSharedSubscription)
+		server.DELETE("subscription/household/share", householdController.DeleteSharedSubscription)
+		server.GET("subscription/household/settle-up", householdController.SettleUp)
+	} else {
+		unsupported("households")
+	}
+
+	if repo, ok := db.(storage.OrganizationRepository); ok {
+		organizationController := NewOrganizationHandler(repo, db, logger)
+		server.POST("subscription/organization", organizationController.PostOrganization)
+		server.GET("subscription/organization", organizationController.GetOrganization)
+		server.PUT("subscription/organization/member", organizationController.PutOrganizationMember)
+		server.DELETE("subscription/organization/member", organizationController.DeleteOrganizationMember)
+		server.GET("subscription/organization/seat-history", organizationController.GetSeatHistory)
+		server.GET("subscription/organization/spend", organizationController.GetOrganizationSpend)
+	} else {
+		unsupported("organizations")
+	}
+
+	if repo, ok := db.(storage.RecommendationRepository); ok {
+		recommendationController := NewRecommendationHandler(repo, logger)
+		server.GET("subscription/recommendations", recommendationController.GetRecommendations)
+	} else {
+		unsupported("recommendations")
+	}
+
+	if repo, ok := db.(gql.Repository); ok {
+		graphqlHandler, err := gql.New(repo, subscriptions, logger, cfg.GraphQLMaxDepth, cfg.GraphQLMaxComplexity)
+		if err != nil {
+			return nil, fmt.Errorf("error creating GraphQL handler %w", err)
+		}
+
+		server.POST("graphql", graphqlHandler.Serve)
+		server.GET("graphql", graphqlHandler.Serve)
+	} else {
+		unsupported("graphql")
+	}
+
 	server.GET("/swagger/*", echoSwagger.WrapHandler)
 
-	return &Server{
-		logger:  logger,
-		server:  server,
-		storage: db,
+	httpServer, err := NewHTTPServer(cfg, server)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP server %w", err)
+	}
+
+	res := &Server{
+		logger:     logger,
+		server:     server,
+		httpServer: httpServer,
 	}
+
+	// Without a separate admin port the metrics are served next to the API.
+	metricsHandler := echo.WrapHandler(metrics.Handler(registry))
+
+	if cfg.AdminPort == 0 {
+		server.GET("metrics", metricsHandler)
+	} else {
+		admin := echo.New()
+		admin.HideBanner = true
+		admin.GET("metrics", metricsHandler)
+
+		adminCfg := cfg
+		adminCfg.Port = cfg.AdminPort
+		adminCfg.TLS = config.TLSConfig{}
+
+		if res.admin, err = NewHTTPServer(adminCfg, admin); err != nil {
+			return nil, fmt.Errorf("error creating admin server %w", err)
+		}
+	}
+
+	if cfg.TLS.RedirectPort != 0 {
+		res.redirect = newRedirectServer(cfg)
+	}
+
+	return res, nil
 }
-func (s Server) Run(serverHost string, serverPort int) {
-	s.logger.Info("Server is running on: localhost", "Port", serverPort)
 
-	if err := s.server.Start(fmt.Sprintf("%s:%d", serverHost, serverPort)); err != nil {
-		if !errors.Is(err, http.ErrServerClosed) {
-			s.logger.Error("Server starting error: %v", slog.Any("error_details", err))
+// Start listens on the ports of the API, admin and redirect servers and serves them in the background. The
+// listeners are open when Start returns, so a busy port fails it.
+func (s *Server) Start() error {
+	for _, server := range s.servers() {
+		listener, err := net.Listen("tcp", server.Addr)
+		if err != nil {
+			s.closeListeners()
+
+			return fmt.Errorf("error listening on %s %w", server.Addr, err)
 		}
+
+		s.listeners = append(s.listeners, listener)
 	}
+
+	for i, server := range s.servers() {
+		go s.serve(server, s.listeners[i])
+	}
+
+	return nil
 }
 
-func (s Server) Stop(ctx context.Context) error {
-	s.logger.Info("Stopping DB Connection")
+// Addr returns the address the API server listens on, once started.
+func (s *Server) Addr() net.Addr {
+	return s.listeners[0].Addr()
+}
 
-	s.storage.Close()
+// servers returns the API server first, then the optional admin and redirect servers.
+func (s *Server) servers() []*http.Server {
+	servers := []*http.Server{s.httpServer}
 
+	for _, server := range []*http.Server{s.admin, s.redirect} {
+		if server != nil {
+			servers = append(servers, server)
+		}
+	}
+
+	return servers
+}
+
+func (s *Server) closeListeners() {
+	for _, listener := range s.listeners {
+		listener.Close()
+	}
+
+	s.listeners = nil
+}
+
+func (s *Server) serve(server *http.Server, listener net.Listener) {
+	s.logger.Info("Server is running on", "Address", listener.Addr().String(), "TLS", server.TLSConfig != nil)
+
+	var err error
+	if server.TLSConfig != nil {
+		err = server.ServeTLS(listener, "", "")
+	} else {
+		err = server.Serve(listener)
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.Error("Server serving error: %v", slog.Any("error_details", err))
+	}
+}
+
+// Stop stops accepting connections and lets the in-flight requests finish until ctx expires.
+func (s *Server) Stop(ctx context.Context) error {
 	s.logger.Info("Stopping server...")
-	err := s.server.Shutdown(ctx)
 
-	if err != nil {
-		s.logger.Error("Error: ", slog.Any("error_details", err))
+	var result error
+
+	// The API server goes last, the others can't have requests depending on it.
+	servers := s.servers()
+	for i := len(servers) - 1; i >= 0; i-- {
+		if err := servers[i].Shutdown(ctx); err != nil {
+			result = errors.Join(result, err)
+		}
+	}
 
-		return fmt.Errorf("error while stopping Server Request %w", err)
+	if result != nil {
+		return fmt.Errorf("error while stopping Server Request %w", result)
 	}
 
 	return nil
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+)
+
+// Publisher delivers the events of the changed subscriptions. It is called after the change is stored, so a
+// failure to publish doesn't undo the change.
+type Publisher interface {
+	Publish(ctx context.Context, event models.SubscriptionEvent)
+}
+
+// LogPublisher writes the events to the service log, it is used until a real broker is configured.
+type LogPublisher struct {
+	logger *slog.Logger
+}
+
+func NewLogPublisher(logger *slog.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+func (p *LogPublisher) Publish(_ context.Context, event models.SubscriptionEvent) {
+	p.logger.Info("Subscription event",
+		"Type", event.Type,
+		"SubscriptionID", event.SubscriptionID,
+		"UserID", event.UserID,
+		"ServiceName", event.ServiceName)
+}
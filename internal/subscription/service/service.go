@@ -0,0 +1,329 @@
+// Package service holds the rules about subscriptions: validation, date handling, ownership and costs. The
+// storage only persists what the service hands it, and every change is published as an event.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/household"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultCurrency      = "RUB"
+	defaultBillingPeriod = models.BillingMonthly
+	defaultSeats         = 1
+	maxServiceNameLength = 64
+	maxDepartmentLength  = 64
+)
+
+// SharesRepository finds the subscriptions a user shares with a household.
+type SharesRepository interface {
+	GetSharedSubscriptionsByUserID(ctx context.Context, id uuid.UUID) ([]models.SharedSubscription, error)
+	GetSharedSubscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) ([]models.SharedSubscription, error)
+}
+
+// OrganizationsRepository finds the role of a user in an organization, ErrNotFound when they aren't a member.
+type OrganizationsRepository interface {
+	GetOrganizationRole(ctx context.Context, organizationID int, userID uuid.UUID) (string, error)
+}
+
+type Service struct {
+	repo          storage.Repository
+	shares        SharesRepository
+	organizations OrganizationsRepository
+	publisher     Publisher
+	now           func() time.Time
+}
+
+func New(repo storage.Repository, publisher Publisher) *Service {
+	return &Service{repo: repo, publisher: publisher, now: time.Now}
+}
+
+// SetShares makes the costs count the parts of the shared subscriptions, by default every subscription
+// costs its user the full price.
+func (s *Service) SetShares(shares SharesRepository) {
+	s.shares = shares
+}
+
+// SetOrganizations lets the subscriptions belong to organizations, by default organization_id is rejected.
+func (s *Service) SetOrganizations(organizations OrganizationsRepository) {
+	s.organizations = organizations
+}
+
+// GetSubscriptionListByUserID returns the subscriptions of the user ordered by start date, ErrNotFound when
+// there are none.
+func (s *Service) GetSubscriptionListByUserID(ctx context.Context, id uuid.UUID) ([]models.Subscription, error) {
+	subs, err := s.repo.GetSubscriptionsByUserID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(subs) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	return subs, nil
+}
+
+func (s *Service) PostSubscription(ctx context.Context, sub models.SubscriptionListJSON) error {
+	newSub, err := parseSubscription(sub)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkOrganization(ctx, newSub); err != nil {
+		return err
+	}
+
+	if newSub.ID, err = s.repo.PostSubscription(ctx, newSub); err != nil {
+		return err
+	}
+
+	s.publish(ctx, models.SubscriptionCreated, newSub)
+
+	return nil
+}
+
+// UpdateSubscription replaces the subscription id on behalf of userID, the user making the request. Only the
+// owner can update it and it can't be moved to another user: ErrForbidden is returned when userID doesn't own
+// the stored subscription or sub names a different user. sub without a user is the caller's.
+func (s *Service) UpdateSubscription(ctx context.Context, userID uuid.UUID, sub models.SubscriptionListJSON, id int) error {
+	if userID == uuid.Nil {
+		return models.ErrForbidden
+	}
+
+	if sub.UserID == uuid.Nil {
+		sub.UserID = userID
+	}
+
+	updated, err := parseSubscription(sub)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing.UserID != userID || updated.UserID != userID {
+		return models.ErrForbidden
+	}
+
+	if err := s.checkOrganization(ctx, updated); err != nil {
+		return err
+	}
+
+	updated.ID = id
+
+	if err := s.repo.UpdateSubscription(ctx, updated); err != nil {
+		return err
+	}
+
+	s.publish(ctx, models.SubscriptionUpdated, updated)
+
+	return nil
+}
+
+// DeleteSubscription deletes the subscription id on behalf of userID, the user making the request.
+// ErrForbidden is returned when it belongs to another user.
+func (s *Service) DeleteSubscription(ctx context.Context, userID uuid.UUID, id int) error {
+	if userID == uuid.Nil {
+		return models.ErrForbidden
+	}
+
+	existing, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing.UserID != userID {
+		return models.ErrForbidden
+	}
+
+	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+		return err
+	}
+
+	s.publish(ctx, models.SubscriptionDeleted, existing)
+
+	return nil
+}
+
+// checkOrganization allows a subscription to belong to an organization only when its user is a member of it.
+func (s *Service) checkOrganization(ctx context.Context, sub models.Subscription) error {
+	if sub.OrganizationID == nil {
+		return nil
+	}
+
+	if s.organizations == nil {
+		return fmt.Errorf("%w: the storage has no organizations", models.ErrInvalidSubscription)
+	}
+
+	_, err := s.organizations.GetOrganizationRole(ctx, *sub.OrganizationID, sub.UserID)
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrForbidden
+	}
+
+	return err
+}
+
+// GetTotalPeriodCostByDatesAndServiceName sums the costs, seats times price, of the subscriptions of the user
+// started within the period, bounds included. An empty service list counts every service. A shared
+// subscription counts with the part of its cost that falls on the user, whether they pay for it or not.
+func (s *Service) GetTotalPeriodCostByDatesAndServiceName(ctx context.Context,
+	subList models.SubscriptionListToCostJSON,
+) (int, error) {
+	period, err := newCostPeriod(subList)
+	if err != nil {
+		return 0, err
+	}
+
+	subs, err := s.repo.GetSubscriptionsByUserID(ctx, subList.UserID)
+	if err != nil {
+		return 0, err
+	}
+
+	var shared []models.SharedSubscription
+
+	if s.shares != nil {
+		if shared, err = s.shares.GetSharedSubscriptionsByUserID(ctx, subList.UserID); err != nil {
+			return 0, err
+		}
+	}
+
+	return period.cost(subList.UserID, subs, shared), nil
+}
+
+// GetTotalPeriodCostsByUserIDs is GetTotalPeriodCostByDatesAndServiceName for several users, subList.UserID
+// is ignored. Their subscriptions and shares are loaded with a single query each when the repositories can.
+func (s *Service) GetTotalPeriodCostsByUserIDs(ctx context.Context, ids []uuid.UUID,
+	subList models.SubscriptionListToCostJSON,
+) (map[uuid.UUID]int, error) {
+	period, err := newCostPeriod(subList)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := s.subscriptionsByUserIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []models.SharedSubscription
+
+	if s.shares != nil {
+		if shared, err = s.shares.GetSharedSubscriptionsByUserIDs(ctx, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	costs := make(map[uuid.UUID]int, len(ids))
+	for _, id := range ids {
+		costs[id] = period.cost(id, subs[id], shared)
+	}
+
+	return costs, nil
+}
+
+// subscriptionsByUserIDs groups the subscriptions of the users by user, the repositories without batching are
+// asked user by user.
+func (s *Service) subscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]models.Subscription, error) {
+	res := make(map[uuid.UUID][]models.Subscription, len(ids))
+
+	if batch, ok := s.repo.(storage.BatchRepository); ok {
+		subs, err := batch.GetSubscriptionsByUserIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sub := range subs {
+			res[sub.UserID] = append(res[sub.UserID], sub)
+		}
+
+		return res, nil
+	}
+
+	for _, id := range ids {
+		subs, err := s.repo.GetSubscriptionsByUserID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		res[id] = subs
+	}
+
+	return res, nil
+}
+
+// costPeriod is the period and the services a cost is summed over.
+type costPeriod struct {
+	from, to time.Time
+	services []string
+}
+
+func newCostPeriod(subList models.SubscriptionListToCostJSON) (costPeriod, error) {
+	from, err := parseMonth("start_date", subList.StartDate)
+	if err != nil {
+		return costPeriod{}, err
+	}
+
+	to, err := parseMonth("end_date", subList.EndDate)
+	if err != nil {
+		return costPeriod{}, err
+	}
+
+	if to.Before(from) {
+		return costPeriod{}, fmt.Errorf("%w: end_date is before start_date", models.ErrInvalidSubscription)
+	}
+
+	return costPeriod{from: from, to: to, services: subList.ServiceName}, nil
+}
+
+func (p costPeriod) counts(sub models.Subscription) bool {
+	if sub.StartDate.Before(p.from) || sub.StartDate.After(p.to) {
+		return false
+	}
+
+	return len(p.services) == 0 || slices.Contains(p.services, sub.ServiceName)
+}
+
+// cost sums the subscriptions of the user and their parts of the shared ones. shared may hold subscriptions
+// shared with other users only, those add nothing.
+func (p costPeriod) cost(user uuid.UUID, subs []models.Subscription, shared []models.SharedSubscription) int {
+	total := 0
+	sharedIDs := make(map[int]bool, len(shared))
+
+	for _, sub := range shared {
+		sharedIDs[sub.Subscription.ID] = true
+
+		if p.counts(sub.Subscription) {
+			total += household.Split(sub.Subscription.Cost(), sub)[user]
+		}
+	}
+
+	for _, sub := range subs {
+		if !sharedIDs[sub.ID] && p.counts(sub) {
+			total += sub.Cost()
+		}
+	}
+
+	return total
+}
+
+func (s *Service) publish(ctx context.Context, eventType models.SubscriptionEventType, sub models.Subscription) {
+	s.publisher.Publish(ctx, models.SubscriptionEvent{
+		Type:           eventType,
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		ServiceName:    sub.ServiceName,
+		At:             s.now(),
+	})
+}
@@ -0,0 +1,448 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/storage/memory"
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []models.SubscriptionEvent
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event models.SubscriptionEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+}
+
+func (p *recordingPublisher) types() []models.SubscriptionEventType {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	types := make([]models.SubscriptionEventType, 0, len(p.events))
+	for _, event := range p.events {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+func newService() (*Service, *recordingPublisher) {
+	publisher := &recordingPublisher{}
+
+	return New(memory.New(), publisher), publisher
+}
+
+func subscription(userID uuid.UUID, service string, price int, startDate string) models.SubscriptionListJSON {
+	return models.SubscriptionListJSON{UserID: userID, ServiceName: service, Price: price, StartDate: startDate}
+}
+
+func TestPostSubscriptionValidation(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		modify  func(sub *models.SubscriptionListJSON)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(*models.SubscriptionListJSON) {}},
+		{name: "no user", modify: func(sub *models.SubscriptionListJSON) { sub.UserID = uuid.Nil }, wantErr: true},
+		{name: "no service", modify: func(sub *models.SubscriptionListJSON) { sub.ServiceName = "" }, wantErr: true},
+		{name: "negative price", modify: func(sub *models.SubscriptionListJSON) { sub.Price = -1 }, wantErr: true},
+		{name: "bad start date", modify: func(sub *models.SubscriptionListJSON) { sub.StartDate = "2025-09" }, wantErr: true},
+		{name: "bad currency", modify: func(sub *models.SubscriptionListJSON) { sub.Currency = "rubles" }, wantErr: true},
+		{name: "bad period", modify: func(sub *models.SubscriptionListJSON) { sub.BillingPeriod = "weekly" }, wantErr: true},
+		{name: "end before start", modify: func(sub *models.SubscriptionListJSON) { sub.EndDate = "08-2025" }, wantErr: true},
+		{name: "trial before start", modify: func(sub *models.SubscriptionListJSON) { sub.TrialEndDate = "01-2025" }, wantErr: true},
+		{name: "end date", modify: func(sub *models.SubscriptionListJSON) { sub.EndDate = "12-2025" }},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, publisher := newService()
+			sub := subscription(userID, "Netflix", 400, "09-2025")
+			tt.modify(&sub)
+
+			err := svc.PostSubscription(context.Background(), sub)
+			if tt.wantErr {
+				if !errors.Is(err, models.ErrInvalidSubscription) {
+					t.Errorf("expected ErrInvalidSubscription, got %v", err)
+				}
+
+				if len(publisher.types()) != 0 {
+					t.Errorf("expected no events, got %v", publisher.types())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostSubscriptionDefaults(t *testing.T) {
+	t.Parallel()
+
+	svc, publisher := newService()
+	userID := uuid.New()
+
+	if err := svc.PostSubscription(context.Background(), subscription(userID, "Netflix", 400, "09-2025")); err != nil {
+		t.Fatal(err)
+	}
+
+	subs, err := svc.GetSubscriptionListByUserID(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := subs[0]
+	if got.Currency != "RUB" || got.BillingPeriod != models.BillingMonthly ||
+		!got.StartDate.Equal(time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected a RUB monthly subscription from 09-2025, got %+v", got)
+	}
+
+	if len(publisher.events) != 1 || publisher.events[0].Type != models.SubscriptionCreated ||
+		publisher.events[0].SubscriptionID != got.ID || publisher.events[0].UserID != userID {
+		t.Errorf("expected a created event for %d, got %+v", got.ID, publisher.events)
+	}
+
+	if err := svc.PostSubscription(context.Background(), subscription(userID, "Netflix", 400, "09-2025")); !errors.Is(err, models.ErrUnique) {
+		t.Errorf("expected ErrUnique, got %v", err)
+	}
+}
+
+func TestOwnership(t *testing.T) {
+	t.Parallel()
+
+	svc, publisher := newService()
+	ownerID, otherID := uuid.New(), uuid.New()
+
+	if err := svc.PostSubscription(context.Background(), subscription(ownerID, "Netflix", 400, "09-2025")); err != nil {
+		t.Fatal(err)
+	}
+
+	subs, err := svc.GetSubscriptionListByUserID(context.Background(), ownerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := subs[0].ID
+
+	// Naming the owner in the body doesn't make another user the owner.
+	if err := svc.UpdateSubscription(context.Background(), otherID, subscription(ownerID, "Netflix", 500, "09-2025"), id); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("update by another user: expected ErrForbidden, got %v", err)
+	}
+
+	if err := svc.UpdateSubscription(context.Background(), ownerID, subscription(otherID, "Netflix", 500, "09-2025"), id); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("move to another user: expected ErrForbidden, got %v", err)
+	}
+
+	if err := svc.UpdateSubscription(context.Background(), uuid.Nil, subscription(ownerID, "Netflix", 500, "09-2025"), id); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("update without a user: expected ErrForbidden, got %v", err)
+	}
+
+	if err := svc.DeleteSubscription(context.Background(), otherID, id); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("delete by another user: expected ErrForbidden, got %v", err)
+	}
+
+	if err := svc.DeleteSubscription(context.Background(), uuid.Nil, id); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("delete without a user: expected ErrForbidden, got %v", err)
+	}
+
+	if err := svc.UpdateSubscription(context.Background(), ownerID, subscription(uuid.Nil, "Netflix", 500, "09-2025"), id); err != nil {
+		t.Errorf("update by the owner: unexpected error %v", err)
+	}
+
+	if err := svc.DeleteSubscription(context.Background(), ownerID, id); err != nil {
+		t.Errorf("delete by the owner: unexpected error %v", err)
+	}
+
+	if err := svc.DeleteSubscription(context.Background(), ownerID, id); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("delete twice: expected ErrNotFound, got %v", err)
+	}
+
+	if _, err := svc.GetSubscriptionListByUserID(context.Background(), ownerID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+
+	want := []models.SubscriptionEventType{models.SubscriptionCreated, models.SubscriptionUpdated, models.SubscriptionDeleted}
+	if got := publisher.types(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("expected events %v, got %v", want, got)
+	}
+}
+
+func TestTotalPeriodCost(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newService()
+	userID := uuid.New()
+
+	for _, sub := range []models.SubscriptionListJSON{
+		subscription(userID, "Netflix", 400, "08-2025"),
+		subscription(userID, "Netflix", 400, "09-2025"),
+		subscription(userID, "Spotify", 200, "10-2025"),
+		subscription(userID, "Yandex Plus", 300, "12-2025"),
+		subscription(userID, "Kinopoisk", 250, "01-2026"),
+		subscription(uuid.New(), "Netflix", 1000, "10-2025"),
+	} {
+		if err := svc.PostSubscription(context.Background(), sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		from, to string
+		services []string
+		want     int
+		wantErr  error
+	}{
+		{name: "all services, period bounds included", userID: userID, from: "09-2025", to: "12-2025", want: 900},
+		{name: "single service", userID: userID, from: "09-2025", to: "12-2025", services: []string{"Netflix"}, want: 400},
+		{name: "several services", userID: userID, from: "09-2025", to: "12-2025", services: []string{"Spotify", "Yandex Plus"}, want: 500},
+		{name: "unknown service", userID: userID, from: "09-2025", to: "12-2025", services: []string{"HBO"}, want: 0},
+		{name: "no subscriptions", userID: uuid.New(), from: "09-2025", to: "12-2025", want: 0},
+		{name: "invalid date", userID: userID, from: "09-2025", to: "2025-12", wantErr: models.ErrInvalidSubscription},
+		{name: "reversed period", userID: userID, from: "12-2025", to: "09-2025", wantErr: models.ErrInvalidSubscription},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := svc.GetTotalPeriodCostByDatesAndServiceName(context.Background(), models.SubscriptionListToCostJSON{
+				UserID: tt.userID, StartDate: tt.from, EndDate: tt.to, ServiceName: tt.services,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+// sharesStub shares every subscription of the payer it was given with member, 30% of the price.
+type sharesStub struct {
+	repo          *memory.Storage
+	payer, member uuid.UUID
+}
+
+func (s sharesStub) GetSharedSubscriptionsByUserID(ctx context.Context, id uuid.UUID) ([]models.SharedSubscription, error) {
+	if id != s.payer && id != s.member {
+		return nil, nil
+	}
+
+	subs, err := s.repo.GetSubscriptionsByUserID(ctx, s.payer)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := make([]models.SharedSubscription, 0, len(subs))
+	for _, sub := range subs {
+		shared = append(shared, models.SharedSubscription{
+			Subscription: sub,
+			Split:        models.SplitPercentage,
+			Shares:       []models.ShareJSON{{UserID: s.member, Value: 30}},
+		})
+	}
+
+	return shared, nil
+}
+
+func (s sharesStub) GetSharedSubscriptionsByUserIDs(ctx context.Context, ids []uuid.UUID) ([]models.SharedSubscription, error) {
+	if !slices.Contains(ids, s.payer) && !slices.Contains(ids, s.member) {
+		return nil, nil
+	}
+
+	return s.GetSharedSubscriptionsByUserID(ctx, s.payer)
+}
+
+func TestTotalPeriodCostShared(t *testing.T) {
+	t.Parallel()
+
+	repo := memory.New()
+	svc := New(repo, &recordingPublisher{})
+	payer, member := uuid.New(), uuid.New()
+
+	svc.SetShares(sharesStub{repo: repo, payer: payer, member: member})
+
+	for _, sub := range []models.SubscriptionListJSON{
+		subscription(payer, "Yandex Plus", 999, "09-2025"),
+		subscription(member, "Spotify", 200, "09-2025"),
+	} {
+		if err := svc.PostSubscription(context.Background(), sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, tt := range map[string]struct {
+		userID uuid.UUID
+		want   int
+	}{
+		"payer keeps the rest": {userID: payer, want: 700},
+		"member adds the part": {userID: member, want: 200 + 299},
+	} {
+		got, err := svc.GetTotalPeriodCostByDatesAndServiceName(context.Background(), models.SubscriptionListToCostJSON{
+			UserID: tt.userID, StartDate: "09-2025", EndDate: "09-2025",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: expected %d, got %d", name, tt.want, got)
+		}
+	}
+
+	other := uuid.New()
+
+	costs, err := svc.GetTotalPeriodCostsByUserIDs(context.Background(), []uuid.UUID{payer, member, other},
+		models.SubscriptionListToCostJSON{StartDate: "09-2025", EndDate: "09-2025"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[uuid.UUID]int{payer: 700, member: 200 + 299, other: 0}; !maps.Equal(costs, want) {
+		t.Errorf("expected the batch to match the single costs %v, got %v", want, costs)
+	}
+}
+
+func TestTotalPeriodCostSeats(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newService()
+	userID := uuid.New()
+
+	team := subscription(userID, "Slack", 500, "09-2025")
+	team.Seats = 8
+
+	for _, sub := range []models.SubscriptionListJSON{team, subscription(userID, "Spotify", 200, "09-2025")} {
+		if err := svc.PostSubscription(context.Background(), sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := svc.GetTotalPeriodCostByDatesAndServiceName(context.Background(), models.SubscriptionListToCostJSON{
+		UserID: userID, StartDate: "09-2025", EndDate: "09-2025",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := 8*500 + 200; got != want {
+		t.Errorf("expected %d, got %d", want, got)
+	}
+}
+
+// organizationsStub makes member the only member of the organization 1.
+type organizationsStub struct {
+	member uuid.UUID
+}
+
+func (s organizationsStub) GetOrganizationRole(_ context.Context, organizationID int, userID uuid.UUID) (string, error) {
+	if organizationID != 1 || userID != s.member {
+		return "", models.ErrNotFound
+	}
+
+	return models.RoleMember, nil
+}
+
+func TestOrganizationSubscription(t *testing.T) {
+	t.Parallel()
+
+	member, outsider := uuid.New(), uuid.New()
+	organizationID := 1
+
+	orgSubscription := func(userID uuid.UUID) models.SubscriptionListJSON {
+		sub := subscription(userID, "Slack", 500, "09-2025")
+		sub.OrganizationID, sub.Department, sub.Seats = &organizationID, "Разработка", 8
+
+		return sub
+	}
+
+	tests := []struct {
+		name          string
+		organizations OrganizationsRepository
+		sub           models.SubscriptionListJSON
+		wantErr       error
+	}{
+		{name: "member", organizations: organizationsStub{member: member}, sub: orgSubscription(member)},
+		{name: "outsider", organizations: organizationsStub{member: member}, sub: orgSubscription(outsider), wantErr: models.ErrForbidden},
+		{name: "no organizations", sub: orgSubscription(member), wantErr: models.ErrInvalidSubscription},
+		{
+			name: "department without organization",
+			sub: func() models.SubscriptionListJSON {
+				sub := subscription(member, "Slack", 500, "09-2025")
+				sub.Department = "Разработка"
+
+				return sub
+			}(),
+			wantErr: models.ErrInvalidSubscription,
+		},
+		{
+			name: "negative seats",
+			sub: func() models.SubscriptionListJSON {
+				sub := subscription(member, "Slack", 500, "09-2025")
+				sub.Seats = -1
+
+				return sub
+			}(),
+			wantErr: models.ErrInvalidSubscription,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, _ := newService()
+			if tt.organizations != nil {
+				svc.SetOrganizations(tt.organizations)
+			}
+
+			err := svc.PostSubscription(context.Background(), tt.sub)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			subs, err := svc.GetSubscriptionListByUserID(context.Background(), tt.sub.UserID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := subs[0]; got.Seats != 8 || got.OrganizationID == nil || *got.OrganizationID != 1 ||
+				got.Department != "Разработка" {
+				t.Errorf("expected an organization subscription with 8 seats, got %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Ostmind/subscriptionservice/internal/subscription/models"
+	"github.com/google/uuid"
+)
+
+// parseSubscription validates sub and converts it to the stored form, filling in the default currency,
+// billing period and seats. Every error wraps ErrInvalidSubscription.
+func parseSubscription(sub models.SubscriptionListJSON) (models.Subscription, error) {
+	res := models.Subscription{
+		UserID:         sub.UserID,
+		ServiceName:    sub.ServiceName,
+		Price:          sub.Price,
+		Currency:       sub.Currency,
+		BillingPeriod:  sub.BillingPeriod,
+		Seats:          sub.Seats,
+		OrganizationID: sub.OrganizationID,
+		Department:     sub.Department,
+	}
+
+	if res.UserID == uuid.Nil {
+		return res, fmt.Errorf("%w: user_id is required", models.ErrInvalidSubscription)
+	}
+
+	if res.ServiceName == "" || len([]rune(res.ServiceName)) > maxServiceNameLength {
+		return res, fmt.Errorf("%w: service_name must have 1 to %d characters", models.ErrInvalidSubscription,
+			maxServiceNameLength)
+	}
+
+	if res.Price < 0 {
+		return res, fmt.Errorf("%w: price is negative", models.ErrInvalidSubscription)
+	}
+
+	if res.Seats == 0 {
+		res.Seats = defaultSeats
+	} else if res.Seats < 0 {
+		return res, fmt.Errorf("%w: seats must be positive", models.ErrInvalidSubscription)
+	}
+
+	if res.OrganizationID != nil && *res.OrganizationID <= 0 {
+		return res, fmt.Errorf("%w: organization_id must be positive", models.ErrInvalidSubscription)
+	}
+
+	if res.Department != "" && res.OrganizationID == nil {
+		return res, fmt.Errorf("%w: department needs organization_id", models.ErrInvalidSubscription)
+	}
+
+	if len([]rune(res.Department)) > maxDepartmentLength {
+		return res, fmt.Errorf("%w: department must have at most %d characters", models.ErrInvalidSubscription,
+			maxDepartmentLength)
+	}
+
+	if res.Currency == "" {
+		res.Currency = defaultCurrency
+	} else if !isCurrencyCode(res.Currency) {
+		return res, fmt.Errorf("%w: currency %q is not a 3-letter code", models.ErrInvalidSubscription, res.Currency)
+	}
+
+	if res.BillingPeriod == "" {
+		res.BillingPeriod = defaultBillingPeriod
+	} else if _, ok := models.BillingPeriodMonths[res.BillingPeriod]; !ok {
+		return res, fmt.Errorf("%w: unknown billing_period %q", models.ErrInvalidSubscription, res.BillingPeriod)
+	}
+
+	var err error
+
+	if res.StartDate, err = parseMonth("start_date", sub.StartDate); err != nil {
+		return res, err
+	}
+
+	if res.EndDate, err = parseMonthNotBefore("end_date", sub.EndDate, res.StartDate); err != nil {
+		return res, err
+	}
+
+	if res.TrialEndDate, err = parseMonthNotBefore("trial_end_date", sub.TrialEndDate, res.StartDate); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func parseMonth(field, value string) (time.Time, error) {
+	date, err := time.Parse(models.MonthLayout, value)
+	if err != nil {
+		return date, fmt.Errorf("%w: %s %q is not in MM-YYYY format", models.ErrInvalidSubscription, field, value)
+	}
+
+	return date, nil
+}
+
+// parseMonthNotBefore parses a date that may be omitted, returning nil for an empty value. The date can't
+// be before startDate.
+func parseMonthNotBefore(field, value string, startDate time.Time) (*time.Time, error) {
+	date, err := models.ParseOptionalMonth(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s %q is not in MM-YYYY format", models.ErrInvalidSubscription, field, value)
+	}
+
+	if date == nil {
+		return nil, nil
+	}
+
+	if date.Before(startDate) {
+		return nil, fmt.Errorf("%w: %s is before start_date", models.ErrInvalidSubscription, field)
+	}
+
+	return date, nil
+}
+
+func isCurrencyCode(value string) bool {
+	if len(value) != 3 {
+		return false
+	}
+
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}